@@ -65,13 +65,60 @@ type UserConfig struct {
 	Password string
 }
 
+// TrashConfig 回收站配置
+type TrashConfig struct {
+	BaseDir       string // 回收站根目录，按任务ID划分子目录
+	RetentionDays int    // 保留天数，过期后自动清除
+}
+
+// HealthCheckConfig 源站健康检查配置
+type HealthCheckConfig struct {
+	Enabled            bool // 是否启用后台健康检查
+	Interval           int  // 检查间隔，秒
+	FailureThreshold   int  // 连续失败多少次判定为异常
+	SkipUnhealthyTasks bool // 源站异常时是否跳过定时任务，而不是记录为执行失败
+	RetentionDays      int  // 检查记录保留天数
+}
+
+// SchedulerConfig 定时调度配置
+type SchedulerConfig struct {
+	Timezone        string // 默认时区，为空时使用服务器本地时区
+	JitterSeconds   int    // 触发时随机延迟的最大秒数，避免多个任务同时访问源站
+	BlackoutWindows string // 禁止执行时段，如 19:00-23:00，多个时段用逗号分隔
+}
+
+// CommandConfig 外部命令配置，用于任务后续动作等执行 Shell 命令的场景
+type CommandConfig struct {
+	Enabled bool // 是否允许执行 Shell 命令，默认关闭
+	Timeout int  // 未单独设置时的默认超时时间，秒
+}
+
+// RunLogConfig 运行日志配置，按任务日志记录每次执行的详细过程
+type RunLogConfig struct {
+	Level         string // 记录的最低级别：debug / info / warn / error
+	MaxEntries    int    // 单次执行最多记录的条目数
+	RetentionDays int    // 保留天数，过期后自动清除
+}
+
+// ProvisionConfig 声明式配置，启动时和收到 SIGHUP 信号时将文件中声明的任务和配置同步到数据库
+type ProvisionConfig struct {
+	File string // 声明文件路径，支持 JSON 和 YAML，为空时不启用
+	Mode string // 同步模式：sync 以文件为准覆盖同名任务和配置并禁用未声明的任务，seed 仅创建不存在的任务和配置
+}
+
 // AppConfig 应用配置
 type AppConfig struct {
-	Server   ServerConfig
-	Log      LogConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	User     UserConfig
+	Server      ServerConfig
+	Log         LogConfig
+	Database    DatabaseConfig
+	JWT         JWTConfig
+	User        UserConfig
+	Trash       TrashConfig
+	HealthCheck HealthCheckConfig
+	Scheduler   SchedulerConfig
+	Command     CommandConfig
+	RunLog      RunLogConfig
+	Provision   ProvisionConfig
 }
 
 // 全局配置变量
@@ -155,6 +202,35 @@ func LoadConfig() *AppConfig {
 			Name:     getEnv("USER_NAME", "admin"),
 			Password: getEnv("USER_PASSWORD", ""),
 		},
+		Trash: TrashConfig{
+			BaseDir:       getEnv("TRASH_BASE_DIR", "../data/trash"),
+			RetentionDays: getEnvAsInt("TRASH_RETENTION_DAYS", 30),
+		},
+		HealthCheck: HealthCheckConfig{
+			Enabled:            getEnvAsBool("HEALTH_CHECK_ENABLED", true),
+			Interval:           getEnvAsInt("HEALTH_CHECK_INTERVAL", 300),
+			FailureThreshold:   getEnvAsInt("HEALTH_CHECK_FAILURE_THRESHOLD", 2),
+			SkipUnhealthyTasks: getEnvAsBool("HEALTH_CHECK_SKIP_UNHEALTHY_TASKS", false),
+			RetentionDays:      getEnvAsInt("HEALTH_CHECK_RETENTION_DAYS", 7),
+		},
+		Scheduler: SchedulerConfig{
+			Timezone:        getEnv("SCHEDULER_TIMEZONE", ""),
+			JitterSeconds:   getEnvAsInt("SCHEDULER_JITTER", 0),
+			BlackoutWindows: getEnv("SCHEDULER_BLACKOUT_WINDOWS", ""),
+		},
+		Command: CommandConfig{
+			Enabled: getEnvAsBool("SHELL_COMMAND_ENABLED", false),
+			Timeout: getEnvAsInt("SHELL_COMMAND_TIMEOUT", 300),
+		},
+		RunLog: RunLogConfig{
+			Level:         getEnv("RUN_LOG_LEVEL", "info"),
+			MaxEntries:    getEnvAsInt("RUN_LOG_MAX_ENTRIES", 10000),
+			RetentionDays: getEnvAsInt("RUN_LOG_RETENTION_DAYS", 30),
+		},
+		Provision: ProvisionConfig{
+			File: getEnv("PROVISION_FILE", ""),
+			Mode: getEnv("PROVISION_MODE", "seed"),
+		},
 	}
 
 	return GlobalConfig
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/backup"
+	backupRequest "github.com/MccRay-s/alist2strm/model/backup/request"
+	"github.com/MccRay-s/alist2strm/model/common/response"
+	"github.com/MccRay-s/alist2strm/service"
+	"github.com/MccRay-s/alist2strm/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// 包级别的导入导出控制器实例
+var Backup = &BackupController{}
+
+type BackupController struct{}
+
+// Export 以 JSON 或 YAML 文件导出全部任务和配置
+func (bc *BackupController) Export(c *gin.Context) {
+	var req backupRequest.ExportReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+	if req.Format == "" {
+		req.Format = backup.FormatJSON
+	}
+
+	doc, err := service.Backup.Export(&req)
+	if err != nil {
+		utils.Error("导出任务和配置失败", "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	data, err := service.Backup.Encode(doc, req.Format)
+	if err != nil {
+		utils.Error("编码导出文档失败", "format", req.Format, "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	contentType := "application/json; charset=utf-8"
+	if req.Format == backup.FormatYAML {
+		contentType = "application/yaml; charset=utf-8"
+	}
+	filename := fmt.Sprintf("alist2strm-%s.%s", doc.ExportedAt.Format("20060102-150405"), req.Format)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// Import 导入任务和配置，请求体为导出的 JSON 或 YAML 文档
+func (bc *BackupController) Import(c *gin.Context) {
+	var req backupRequest.ImportReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+	if req.Format == "" {
+		req.Format = backup.FormatJSON
+		if strings.Contains(c.ContentType(), "yaml") {
+			req.Format = backup.FormatYAML
+		}
+	}
+
+	data, err := c.GetRawData()
+	if err != nil || len(data) == 0 {
+		response.FailWithMessage("请求体不能为空", c)
+		return
+	}
+	doc, err := service.Backup.Decode(data, req.Format)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	start := time.Now()
+	resp, err := service.Backup.Import(doc, &req)
+	if err != nil {
+		utils.Error("导入任务和配置失败", "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	utils.Info("导入任务和配置", "dry_run", req.DryRun, "valid", resp.Valid, "applied", resp.Applied, "duration", time.Since(start), "request_id", c.GetString("request_id"))
+	response.SuccessWithData(resp, c)
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"github.com/MccRay-s/alist2strm/model/common/response"
+	healthRequest "github.com/MccRay-s/alist2strm/model/health/request"
+	"github.com/MccRay-s/alist2strm/service"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct{}
+
+// 包级别的全局实例
+var Health = &HealthController{}
+
+// GetStatus 获取各源站当前健康状态
+func (c *HealthController) GetStatus(ctx *gin.Context) {
+	response.SuccessWithData(service.Health.GetStatus(), ctx)
+}
+
+// CheckNow 立即检查所有源站
+func (c *HealthController) CheckNow(ctx *gin.Context) {
+	response.SuccessWithData(service.Health.CheckAll(), ctx)
+}
+
+// GetHistory 获取健康检查记录分页列表
+func (c *HealthController) GetHistory(ctx *gin.Context) {
+	var req healthRequest.HealthHistoryReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), ctx)
+		return
+	}
+
+	resp, err := service.Health.GetHistory(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), ctx)
+		return
+	}
+
+	response.SuccessWithData(resp, ctx)
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/MccRay-s/alist2strm/model/common/response"
+	"github.com/MccRay-s/alist2strm/model/notification"
+	notificationRequest "github.com/MccRay-s/alist2strm/model/notification/request"
+	"github.com/MccRay-s/alist2strm/service"
+	"github.com/MccRay-s/alist2strm/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationController 通知控制器
+type NotificationController struct{}
+
+var Notification = &NotificationController{}
+
+// SendTest 向指定渠道发送测试通知
+func (n *NotificationController) SendTest(c *gin.Context) {
+	var req notificationRequest.NotificationTestReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error("发送测试通知参数绑定失败", "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	templateType := notification.TemplateType(req.TemplateType)
+	if templateType != "" && !notification.IsValidTemplateType(templateType) {
+		response.FailWithMessage("不支持的模板类型", c)
+		return
+	}
+
+	if err := service.GetNotificationService().SendTestNotification(notification.NotificationChannelType(req.ChannelType), templateType); err != nil {
+		utils.Error("发送测试通知失败", "channel", req.ChannelType, "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage("发送测试通知失败: "+err.Error(), c)
+		return
+	}
+
+	response.SuccessWithMessage("测试通知已发送", c)
+}
+
+// GetSettings 获取通知设置
+func (n *NotificationController) GetSettings(c *gin.Context) {
+	settings, err := service.GetNotificationService().GetSettings()
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithData(settings, c)
+}
+
+// UpdateSettings 更新通知设置
+func (n *NotificationController) UpdateSettings(c *gin.Context) {
+	var req notification.Settings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error("更新通知设置参数绑定失败", "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	if err := service.GetNotificationService().UpdateSettings(&req); err != nil {
+		utils.Error("更新通知设置失败", "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithMessage("通知设置已更新", c)
+}
+
+// PreviewTemplate 校验并预览通知模板
+func (n *NotificationController) PreviewTemplate(c *gin.Context) {
+	var req notificationRequest.NotificationTemplatePreviewReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	resp, err := service.GetNotificationService().PreviewTemplate(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithData(resp, c)
+}
+
+// GetQueueList 获取通知队列列表（分页）
+func (n *NotificationController) GetQueueList(c *gin.Context) {
+	var req notificationRequest.NotificationQueueListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	resp, err := service.GetNotificationService().GetQueueList(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithData(resp, c)
+}
+
+// RetryQueueItem 重试一条发送失败的通知
+func (n *NotificationController) RetryQueueItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("ID参数错误", c)
+		return
+	}
+
+	if err := service.GetNotificationService().RetryQueueItem(uint(id)); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithMessage("通知已重新加入队列", c)
+}
+
+// RetryFailedQueue 重试全部发送失败的通知
+func (n *NotificationController) RetryFailedQueue(c *gin.Context) {
+	resp, err := service.GetNotificationService().RetryFailedQueue()
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithData(resp, c)
+}
+
+// DeleteQueueItem 删除一条通知
+func (n *NotificationController) DeleteQueueItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("ID参数错误", c)
+		return
+	}
+
+	if err := service.GetNotificationService().DeleteQueueItem(uint(id)); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithMessage("删除成功", c)
+}
+
+// CleanQueue 按状态批量删除通知
+func (n *NotificationController) CleanQueue(c *gin.Context) {
+	var req notificationRequest.NotificationQueueCleanReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	resp, err := service.GetNotificationService().CleanQueue(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithData(resp, c)
+}
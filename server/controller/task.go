@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/MccRay-s/alist2strm/model/common/response"
+	"github.com/MccRay-s/alist2strm/model/task"
 	taskRequest "github.com/MccRay-s/alist2strm/model/task/request"
 	"github.com/MccRay-s/alist2strm/service"
 	"github.com/MccRay-s/alist2strm/utils"
@@ -216,6 +217,26 @@ func (tc *TaskController) ResetTaskStatus(c *gin.Context) {
 	response.SuccessWithMessage("重置任务状态成功", c)
 }
 
+// CancelTask 取消正在执行或排队中的任务
+func (tc *TaskController) CancelTask(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.Error("取消任务ID参数错误", "id", idStr, "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage("任务ID参数错误", c)
+		return
+	}
+
+	message, err := service.Task.CancelTask(uint(id))
+	if err != nil {
+		utils.Error("取消任务失败", "task_id", id, "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithMessage(message, c)
+}
+
 // ExecuteTask 执行任务
 func (tc *TaskController) ExecuteTask(c *gin.Context) {
 	idStr := c.Param("id")
@@ -241,7 +262,7 @@ func (tc *TaskController) ExecuteTask(c *gin.Context) {
 	var err2 error
 	if async {
 		// 异步执行
-		err2 = service.Task.ExecuteStrmGenerationAsync(uint(id))
+		err2 = service.Task.ExecuteStrmGenerationAsync(uint(id), task.TriggerManual)
 		if err2 == nil {
 			utils.Info("异步执行任务已启动", "task_id", id, "request_id", c.GetString("request_id"))
 			response.SuccessWithMessage("任务已启动", c)
@@ -278,3 +299,91 @@ func (tc *TaskController) ExecuteTask(c *gin.Context) {
 		return
 	}
 }
+
+// RescanPath 局部重新扫描指定路径
+func (tc *TaskController) RescanPath(c *gin.Context) {
+	var req taskRequest.TaskRescanReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error("局部扫描请求参数错误", "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	resp, err := service.Task.RescanPath(&req, task.TriggerAPI)
+	if err != nil {
+		utils.Error("提交局部扫描失败", "task_id", req.TaskID, "path", req.Path, "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithData(resp, c)
+}
+
+// PreviewSchedule 预览调度表达式接下来的触发时间
+func (tc *TaskController) PreviewSchedule(c *gin.Context) {
+	var req taskRequest.TaskSchedulePreviewReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error("调度预览请求参数错误", "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	resp, err := service.GetTaskScheduler().PreviewSchedule(&req)
+	if err != nil {
+		utils.Error("调度预览失败", "cron", req.Cron, "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithData(resp, c)
+}
+
+// GetQueue 获取执行队列
+func (tc *TaskController) GetQueue(c *gin.Context) {
+	items, err := service.GetTaskQueue().List()
+	if err != nil {
+		utils.Error("获取执行队列失败", "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithData(items, c)
+}
+
+// ReorderQueue 调整执行队列顺序
+func (tc *TaskController) ReorderQueue(c *gin.Context) {
+	var req taskRequest.TaskQueueReorderReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error("调整队列顺序请求参数错误", "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	if err := service.GetTaskQueue().Reorder(req.IDs); err != nil {
+		utils.Error("调整队列顺序失败", "ids", req.IDs, "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	utils.Info("调整队列顺序成功", "ids", req.IDs, "request_id", c.GetString("request_id"))
+	response.SuccessWithMessage("队列顺序已更新", c)
+}
+
+// RemoveQueueEntry 从执行队列移除条目
+func (tc *TaskController) RemoveQueueEntry(c *gin.Context) {
+	idStr := c.Param("entryId")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.Error("队列条目ID参数错误", "id", idStr, "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage("队列条目ID参数错误", c)
+		return
+	}
+
+	if err := service.GetTaskQueue().Remove(uint(id)); err != nil {
+		utils.Error("移除队列条目失败", "entry_id", id, "error", err.Error(), "request_id", c.GetString("request_id"))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	response.SuccessWithMessage("已从队列移除", c)
+}
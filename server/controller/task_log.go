@@ -1,11 +1,14 @@
 package controller
 
 import (
+	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/MccRay-s/alist2strm/model/common/response"
 	taskLogRequest "github.com/MccRay-s/alist2strm/model/tasklog/request"
 	"github.com/MccRay-s/alist2strm/service"
+	"github.com/MccRay-s/alist2strm/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -74,3 +77,122 @@ func (c *TaskLogController) GetFileProcessingStats(ctx *gin.Context) {
 
 	response.SuccessWithData(stats, ctx)
 }
+
+// GetFileFailureList 获取某次运行的失败文件列表
+func (c *TaskLogController) GetFileFailureList(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("ID参数错误", ctx)
+		return
+	}
+
+	var req taskLogRequest.FileFailureListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), ctx)
+		return
+	}
+	req.TaskLogID = uint(id)
+
+	resp, err := service.TaskLogServiceInstance.GetFileFailureList(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), ctx)
+		return
+	}
+
+	response.SuccessWithData(resp, ctx)
+}
+
+// RetryFailures 重新处理某次运行中的失败文件
+func (c *TaskLogController) RetryFailures(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("ID参数错误", ctx)
+		return
+	}
+
+	resp, err := service.TaskLogServiceInstance.RetryFailures(uint(id))
+	if err != nil {
+		response.FailWithMessage(err.Error(), ctx)
+		return
+	}
+
+	response.SuccessWithData(resp, ctx)
+}
+
+// StreamProgress 通过 Server-Sent Events 推送某次运行的实时进度
+func (c *TaskLogController) StreamProgress(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("ID参数错误", ctx)
+		return
+	}
+
+	started := false
+	err = service.TaskLogServiceInstance.StreamProgress(ctx.Request.Context(), uint(id), func(event string, data interface{}) error {
+		if !started {
+			ctx.Header("Cache-Control", "no-cache")
+			ctx.Header("Connection", "keep-alive")
+			ctx.Header("X-Accel-Buffering", "no") // 避免反向代理缓冲事件流
+			ctx.Status(http.StatusOK)
+			started = true
+		}
+		ctx.SSEvent(event, data)
+		ctx.Writer.Flush()
+		return ctx.Request.Context().Err()
+	})
+	if err != nil {
+		if !started {
+			response.FailWithMessage(err.Error(), ctx)
+			return
+		}
+		if ctx.Request.Context().Err() == nil {
+			utils.Warn("推送运行进度失败", "task_log_id", id, "error", err.Error(), "request_id", ctx.GetString("request_id"))
+		}
+	}
+}
+
+// GetLogEntryList 分页获取某次运行的日志条目，可按最低级别和事件类型筛选
+func (c *TaskLogController) GetLogEntryList(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("ID参数错误", ctx)
+		return
+	}
+
+	var req taskLogRequest.LogEntryListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), ctx)
+		return
+	}
+	req.TaskLogID = uint(id)
+
+	resp, err := service.TaskLogServiceInstance.GetLogEntryList(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), ctx)
+		return
+	}
+
+	response.SuccessWithData(resp, ctx)
+}
+
+// DownloadLogEntries 以纯文本文件下载某次运行的日志
+func (c *TaskLogController) DownloadLogEntries(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("ID参数错误", ctx)
+		return
+	}
+
+	write, err := service.TaskLogServiceInstance.ExportLogEntries(uint(id), ctx.Query("level"))
+	if err != nil {
+		response.FailWithMessage(err.Error(), ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "text/plain; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=task-log-%d.log", id))
+	ctx.Status(http.StatusOK)
+	if err := write(ctx.Writer); err != nil {
+		utils.Error("导出运行日志失败", "task_log_id", id, "error", err.Error(), "request_id", ctx.GetString("request_id"))
+	}
+}
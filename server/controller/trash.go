@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"github.com/MccRay-s/alist2strm/model/common/response"
+	trashRequest "github.com/MccRay-s/alist2strm/model/trash/request"
+	"github.com/MccRay-s/alist2strm/service"
+	"github.com/MccRay-s/alist2strm/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TrashController struct{}
+
+// 包级别的全局实例
+var Trash = &TrashController{}
+
+// GetTrashList 获取回收站分页列表
+func (c *TrashController) GetTrashList(ctx *gin.Context) {
+	var req trashRequest.TrashListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), ctx)
+		return
+	}
+
+	resp, err := service.Trash.GetTrashList(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), ctx)
+		return
+	}
+
+	response.SuccessWithData(resp, ctx)
+}
+
+// Restore 恢复回收站条目
+func (c *TrashController) Restore(ctx *gin.Context) {
+	var req trashRequest.TrashRestoreReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), ctx)
+		return
+	}
+
+	resp, err := service.Trash.Restore(&req)
+	if err != nil {
+		utils.Error("恢复回收站条目失败", "error", err.Error(), "request_id", ctx.GetString("request_id"))
+		response.FailWithMessage(err.Error(), ctx)
+		return
+	}
+
+	utils.Info("恢复回收站条目完成", "restored", resp.Restored, "failed", resp.Failed, "request_id", ctx.GetString("request_id"))
+	response.SuccessWithData(resp, ctx)
+}
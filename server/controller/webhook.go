@@ -43,22 +43,16 @@ func (*WebhookController) FileNotifyHandler(c *gin.Context) {
 		go func(e webhook.FileChangeEvent) {
 			switch e.Action {
 			case "create":
+				// 新增的文件或目录加入执行队列逐个处理，与其他执行排队，避免并发处理
 				for _, taskInfo := range tasks {
 					// 检查创建的文件/目录是否在任务的源路径下
 					if strings.HasPrefix(e.SourceFile, taskInfo.SourcePath) {
-						log.Printf("  -> File '%s' matches task '%s'. Triggering processing.", e.SourceFile, taskInfo.Name)
-
-						// 从数据库获取完整的任务对象
-						fullTask, err := repository.Task.GetByID(taskInfo.ID)
-						if err != nil {
-							log.Printf("  -> Error fetching full task details for task ID %d: %v", taskInfo.ID, err)
-							continue // 继续处理下一个匹配的任务
-						}
-
-						err = service.GetStrmGeneratorService().ProcessFileChangeEvent(fullTask, &e)
-						if err != nil {
-							log.Printf("  -> Error processing event for '%s': %v", e.SourceFile, err)
+						if !taskInfo.Enabled {
+							log.Printf("  -> File '%s' matches disabled task '%s'. Skipping.", e.SourceFile, taskInfo.Name)
+							continue
 						}
+						log.Printf("  -> File '%s' matches task '%s'. Queueing file event.", e.SourceFile, taskInfo.Name)
+						service.GetTaskQueue().AddFileEvent(taskInfo.ID, e.SourceFile, bool(e.IsDir))
 					}
 				}
 			case "delete":
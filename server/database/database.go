@@ -9,9 +9,11 @@ import (
 	"github.com/MccRay-s/alist2strm/config"
 	"github.com/MccRay-s/alist2strm/model/configs"
 	"github.com/MccRay-s/alist2strm/model/filehistory"
+	"github.com/MccRay-s/alist2strm/model/health"
 	"github.com/MccRay-s/alist2strm/model/notification"
 	"github.com/MccRay-s/alist2strm/model/task"
 	"github.com/MccRay-s/alist2strm/model/tasklog"
+	"github.com/MccRay-s/alist2strm/model/trash"
 	"github.com/MccRay-s/alist2strm/model/user"
 	"gorm.io/gorm"
 )
@@ -101,14 +103,27 @@ func healthCheck(db *gorm.DB) error {
 
 // migrateDatabase 执行数据库表结构迁移
 func migrateDatabase(db *gorm.DB) error {
+	// 早期版本的 hash 字段为唯一索引，内容相同的文件无法同时记录，迁移前先移除
+	if db.Migrator().HasIndex(&filehistory.FileHistory{}, "idx_file_histories_hash") {
+		if err := db.Migrator().DropIndex(&filehistory.FileHistory{}, "idx_file_histories_hash"); err != nil {
+			return fmt.Errorf("移除文件历史 hash 唯一索引失败: %v", err)
+		}
+	}
+
 	// 自动迁移数据库表结构
 	if err := db.AutoMigrate(
 		&user.User{},
 		&configs.Config{},
 		&task.Task{},
 		&tasklog.TaskLog{},
+		&tasklog.FileFailure{},
+		&tasklog.LogEntry{},
 		&filehistory.FileHistory{},
+		&filehistory.TargetMapping{},
 		&notification.Queue{},
+		&trash.TrashItem{},
+		&health.HealthCheck{},
+		&task.QueueEntry{},
 	); err != nil {
 		return fmt.Errorf("数据库表迁移失败: %v", err)
 	}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	_ "time/tzdata" // 内置时区数据，容器中缺少 tzdata 时也能使用任务时区
 
 	"github.com/MccRay-s/alist2strm/config"
 	"github.com/MccRay-s/alist2strm/database"
@@ -30,6 +31,14 @@ func main() {
 		log.Fatalf("初始化默认用户失败: %v", err)
 	}
 
+	// 同步声明式配置文件（如果配置了的话），需在创建默认配置之前执行，校验失败时终止启动
+	if service.Provision.Enabled() {
+		if _, err := service.Provision.Reconcile("startup"); err != nil {
+			utils.Error("同步声明式配置失败", "error", err.Error())
+			log.Fatalf("同步声明式配置失败: %v", err)
+		}
+	}
+
 	// 初始化默认STRM配置（如果没有配置的话）
 	if err := service.Config.InitializeDefaultConfig(); err != nil {
 		utils.Error("初始化默认STRM配置失败", "error", err.Error())
@@ -58,6 +67,9 @@ func main() {
 	strmService := service.GetStrmGeneratorService()
 	strmService.Initialize(logger)
 
+	// 为历史任务回填目标文件映射（仅对尚无映射的任务执行一次），在任务队列执行器启动前完成
+	strmService.BackfillTargetMappings()
+
 	// 初始化任务队列
 	service.GetTaskQueue()
 	utils.Info("任务队列初始化完成")
@@ -70,6 +82,13 @@ func main() {
 		utils.Info("通知服务初始化完成")
 	}
 
+	// 启动回收站过期清理任务
+	service.Trash.StartPurgeTask()
+	utils.Info("回收站清理任务已启动")
+
+	// 启动源站健康检查
+	service.Health.StartMonitor()
+
 	// 初始化任务调度器
 	taskScheduler := service.GetTaskScheduler()
 
@@ -85,6 +104,12 @@ func main() {
 		utils.Info("任务调度器启动完成，没有启用的定时任务")
 	}
 
+	// 收到 SIGHUP 信号时重新同步声明式配置
+	if service.Provision.Enabled() {
+		service.Provision.WatchReload()
+		utils.Info("声明式配置已启用，可发送 SIGHUP 信号重新同步", "file", cfg.Provision.File, "mode", cfg.Provision.Mode)
+	}
+
 	utils.Info("服务初始化完成")
 
 	// 设置路由
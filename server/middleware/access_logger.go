@@ -9,15 +9,17 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// responseWriter 包装 gin.ResponseWriter 以捕获响应内容
+// responseWriter 包装 gin.ResponseWriter 以统计响应大小
+// 只计数不缓存响应内容，避免长时间推送的事件流占用内存
 type responseWriter struct {
 	gin.ResponseWriter
-	body *bytes.Buffer
+	size int
 }
 
 func (w *responseWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
-	return w.ResponseWriter.Write(b)
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
 }
 
 // AccessLogger 访问日志中间件
@@ -35,7 +37,6 @@ func AccessLogger() gin.HandlerFunc {
 		// 包装响应写入器
 		wrapper := &responseWriter{
 			ResponseWriter: c.Writer,
-			body:           bytes.NewBuffer([]byte{}),
 		}
 		c.Writer = wrapper
 
@@ -56,7 +57,7 @@ func AccessLogger() gin.HandlerFunc {
 				"referer", c.Request.Referer(),
 				"status", c.Writer.Status(),
 				"duration", duration,
-				"response_size", wrapper.body.Len(),
+				"response_size", wrapper.size,
 				"request_id", GetRequestID(c),
 				"timestamp", startTime.Format(time.RFC3339),
 			)
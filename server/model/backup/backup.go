@@ -0,0 +1,103 @@
+package backup
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/task"
+)
+
+// Version 当前导出文档的版本号
+const Version = 1
+
+// RedactedValue 导出时替代密钥的占位值，导入时遇到该值将保留已保存的密钥
+const RedactedValue = "******"
+
+// 文档格式
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// 导入时任务名称冲突的处理策略
+const (
+	// ConflictSkip 跳过同名任务（默认）
+	ConflictSkip = "skip"
+	// ConflictOverwrite 覆盖同名任务
+	ConflictOverwrite = "overwrite"
+	// ConflictRename 以新名称创建任务
+	ConflictRename = "rename"
+)
+
+// 导入结果中每一项的处理方式
+const (
+	ActionCreate    = "create"
+	ActionOverwrite = "overwrite"
+	ActionRename    = "rename"
+	ActionSkip      = "skip"
+	ActionInvalid   = "invalid"
+	ActionFailed    = "failed"
+)
+
+// ConfigCodes 支持导入导出的配置代码
+var ConfigCodes = []string{"ALIST", "CLOUD_DRIVE", "STRM", "EMBY", "NOTIFICATION_SETTINGS"}
+
+// IsValidConfigCode 检查配置代码是否支持导入导出
+func IsValidConfigCode(code string) bool {
+	for _, c := range ConfigCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Document 任务和配置的导出文档
+type Document struct {
+	Version    int          `json:"version"`
+	ExportedAt time.Time    `json:"exportedAt"`
+	Redacted   bool         `json:"redacted"` // 密钥是否已脱敏
+	Tasks      []TaskSpec   `json:"tasks"`
+	Configs    []ConfigSpec `json:"configs"`
+}
+
+// TaskSpec 导出的任务定义，不包含ID、运行状态等与实例相关的字段
+type TaskSpec struct {
+	Name               string           `json:"name"`
+	MediaType          string           `json:"mediaType"`
+	ConfigType         string           `json:"configType"`
+	SourcePath         string           `json:"sourcePath"`
+	TargetPath         string           `json:"targetPath"`
+	FileSuffix         string           `json:"fileSuffix"`
+	Overwrite          bool             `json:"overwrite"`
+	Enabled            bool             `json:"enabled"`
+	Cron               string           `json:"cron"`
+	DownloadMetadata   bool             `json:"downloadMetadata"`
+	DownloadSubtitle   bool             `json:"downloadSubtitle"`
+	MetadataExtensions string           `json:"metadataExtensions"`
+	SubtitleExtensions string           `json:"subtitleExtensions"`
+	NotifyEvents       string           `json:"notifyEvents"`
+	NotifyChannels     string           `json:"notifyChannels"`
+	NotifyMinGenerated int              `json:"notifyMinGenerated"`
+	RunOnlyIfIdle      bool             `json:"runOnlyIfIdle"`
+	Timezone           string           `json:"timezone"`
+	JitterSeconds      int              `json:"jitterSeconds"`
+	BlackoutWindows    string           `json:"blackoutWindows"`
+	MisfirePolicy      string           `json:"misfirePolicy"`
+	MisfireLimit       int              `json:"misfireLimit"`
+	PostActions        []PostActionSpec `json:"postActions"`
+	Hooks              task.TaskHooks   `json:"hooks"`
+}
+
+// PostActionSpec 导出的后续动作，触发其他任务时额外记录任务名称，导入时按名称重新关联
+type PostActionSpec struct {
+	task.PostAction
+	TaskName string `json:"taskName,omitempty"`
+}
+
+// ConfigSpec 导出的配置，值以对象形式保存便于阅读和编辑
+type ConfigSpec struct {
+	Code  string          `json:"code"`
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
@@ -0,0 +1,14 @@
+package request
+
+// ExportReq 导出请求
+type ExportReq struct {
+	Format string `json:"format" form:"format" binding:"omitempty,oneof=json yaml" example:"yaml"` // 文档格式，默认 json
+	Redact bool   `json:"redact" form:"redact" example:"true"`                                     // 是否将密码、Token 等密钥替换为占位值
+}
+
+// ImportReq 导入请求，文档内容通过请求体传递
+type ImportReq struct {
+	Format   string `json:"format" form:"format" binding:"omitempty,oneof=json yaml" example:"yaml"`                 // 文档格式，为空时根据 Content-Type 判断，默认 json
+	Conflict string `json:"conflict" form:"conflict" binding:"omitempty,oneof=skip overwrite rename" example:"skip"` // 任务名称冲突时的处理策略，默认 skip
+	DryRun   bool   `json:"dryRun" form:"dryRun" example:"false"`                                                    // 仅校验文档并返回处理计划，不写入数据
+}
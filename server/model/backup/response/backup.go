@@ -0,0 +1,18 @@
+package response
+
+// ImportItem 导入结果中的单项
+type ImportItem struct {
+	Name    string `json:"name"`              // 任务名称或配置代码
+	Action  string `json:"action"`            // 处理方式：create / overwrite / rename / skip / invalid / failed
+	NewName string `json:"newName,omitempty"` // 重命名后的任务名称
+	Message string `json:"message,omitempty"` // 校验错误或提示信息
+}
+
+// ImportResp 导入结果
+type ImportResp struct {
+	DryRun  bool         `json:"dryRun"`
+	Valid   bool         `json:"valid"`   // 文档是否全部通过校验，未通过时不会写入任何数据
+	Applied bool         `json:"applied"` // 是否已写入数据
+	Tasks   []ImportItem `json:"tasks"`
+	Configs []ImportItem `json:"configs"`
+}
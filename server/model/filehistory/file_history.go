@@ -15,13 +15,13 @@ type FileHistory struct {
 	// 文件基本信息
 	FileName       string     `json:"fileName" gorm:"not null;index;type:varchar(200);uniqueIndex:idx_file_path_size"`
 	SourcePath     string     `json:"sourcePath" gorm:"not null;index;type:varchar(200);uniqueIndex:idx_file_path_size"`
-	TargetFilePath string     `json:"targetFilePath" gorm:"not null;type:varchar(200)"`
+	TargetFilePath string     `json:"targetFilePath" gorm:"not null;type:varchar(1024)"`
 	FileSize       int64      `json:"fileSize" gorm:"not null;uniqueIndex:idx_file_path_size"`
 	FileType       string     `json:"fileType" gorm:"not null;type:varchar(20);uniqueIndex:idx_file_path_size"`
 	FileSuffix     string     `json:"fileSuffix" gorm:"not null;type:varchar(20)"`
 	IsStrm         bool       `json:"isStrm" gorm:"not null;index;default:false"`
 	ModifiedAt     *time.Time `json:"modifiedAt" gorm:"index"`
-	Hash           *string    `json:"hash" gorm:"type:varchar(20);uniqueIndex"` // 使用指针类型支持NULL值
+	Hash           *string    `json:"hash" gorm:"type:varchar(64);index:idx_file_history_hash"` // 使用指针类型支持NULL值，同一内容可能存在多份，不做唯一约束
 }
 
 // TableName 表名
@@ -0,0 +1,22 @@
+package filehistory
+
+import (
+	"time"
+)
+
+// TargetMapping 源文件与实际生成的目标文件之间的映射
+// 目标文件名可能因长度限制被截断并追加哈希，删除和重命名时必须依赖此映射而不是重新计算路径
+type TargetMapping struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	TaskID     uint      `json:"taskId" gorm:"not null;uniqueIndex:idx_task_source_path"`
+	SourcePath string    `json:"sourcePath" gorm:"not null;type:varchar(1024);uniqueIndex:idx_task_source_path"`
+	TargetPath string    `json:"targetPath" gorm:"not null;type:varchar(1024);index"`
+	FileType   string    `json:"fileType" gorm:"not null;type:varchar(20)"`
+}
+
+// TableName 表名
+func (TargetMapping) TableName() string {
+	return "target_mappings"
+}
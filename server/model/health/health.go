@@ -0,0 +1,49 @@
+package health
+
+import (
+	"time"
+)
+
+// 检查的源站
+const (
+	SourceAList      = "alist"
+	SourceCloudDrive = "clouddrive"
+	SourceEmby       = "emby"
+)
+
+// 源站状态
+const (
+	StatusUnknown      = "unknown"      // 尚未检查
+	StatusUnconfigured = "unconfigured" // 未配置，不参与检查
+	StatusUp           = "up"
+	StatusDown         = "down"
+)
+
+// HealthCheck 源站健康检查记录
+type HealthCheck struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+	Source    string    `json:"source" gorm:"not null;index;type:varchar(20)"`
+	Healthy   bool      `json:"healthy" gorm:"not null"`
+	LatencyMs int64     `json:"latencyMs" gorm:"not null;default:0"` // 检查耗时，毫秒
+	Message   string    `json:"message" gorm:"type:text"`            // 失败原因
+}
+
+// TableName 表名
+func (HealthCheck) TableName() string {
+	return "health_checks"
+}
+
+// SourceName 源站显示名称
+func SourceName(source string) string {
+	switch source {
+	case SourceAList:
+		return "AList"
+	case SourceCloudDrive:
+		return "CloudDrive"
+	case SourceEmby:
+		return "Emby"
+	default:
+		return source
+	}
+}
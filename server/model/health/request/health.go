@@ -0,0 +1,9 @@
+package request
+
+// HealthHistoryReq 健康检查记录分页查询请求
+type HealthHistoryReq struct {
+	Page     int    `json:"page" form:"page" binding:"required,min=1"`
+	PageSize int    `json:"pageSize" form:"pageSize" binding:"required,min=1,max=100"`
+	Source   string `json:"source" form:"source"`   // alist / clouddrive / emby，默认全部
+	Healthy  *bool  `json:"healthy" form:"healthy"` // 仅查询成功或失败的记录
+}
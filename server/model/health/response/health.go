@@ -0,0 +1,34 @@
+package response
+
+import (
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/health"
+)
+
+// SourceStatus 源站当前状态
+type SourceStatus struct {
+	Source              string     `json:"source"`
+	Name                string     `json:"name"`
+	Status              string     `json:"status"`              // unknown / unconfigured / up / down
+	Since               *time.Time `json:"since"`               // 进入当前状态的时间
+	LastCheckAt         *time.Time `json:"lastCheckAt"`         // 最近一次检查时间
+	LatencyMs           int64      `json:"latencyMs"`           // 最近一次检查耗时，毫秒
+	Message             string     `json:"message"`             // 最近一次失败原因
+	ConsecutiveFailures int        `json:"consecutiveFailures"` // 连续失败次数
+}
+
+// HealthStatusResp 源站健康状态响应
+type HealthStatusResp struct {
+	Enabled  bool           `json:"enabled"`
+	Interval int            `json:"interval"` // 检查间隔，秒
+	Sources  []SourceStatus `json:"sources"`
+}
+
+// HealthHistoryResp 健康检查记录分页响应
+type HealthHistoryResp struct {
+	List  []*health.HealthCheck `json:"list"`
+	Total int64                 `json:"total"`
+	Page  int                   `json:"page"`
+	Size  int                   `json:"size"`
+}
@@ -0,0 +1,128 @@
+package notification
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/utils"
+)
+
+// 摘要通知发送频率
+const (
+	// DigestFrequencyDaily 每日摘要
+	DigestFrequencyDaily = "daily"
+	// DigestFrequencyWeekly 每周摘要
+	DigestFrequencyWeekly = "weekly"
+)
+
+// DigestSettings 定期摘要通知设置
+type DigestSettings struct {
+	Enabled          bool     `json:"enabled"`
+	Frequency        string   `json:"frequency"`        // 发送频率：daily / weekly
+	Time             string   `json:"time"`             // 发送时间，格式 HH:MM（服务器本地时区）
+	Weekday          int      `json:"weekday"`          // 每周摘要的发送日，0 表示周日
+	Channels         []string `json:"channels"`         // 发送渠道，为空时使用默认渠道
+	SuppressRealtime bool     `json:"suppressRealtime"` // 是否不再逐次发送任务完成/新增媒体通知（失败通知仍实时发送）
+}
+
+// Validate 校验摘要设置
+func (d DigestSettings) Validate() error {
+	if d.Frequency != DigestFrequencyDaily && d.Frequency != DigestFrequencyWeekly {
+		return fmt.Errorf("摘要发送频率无效: %s", d.Frequency)
+	}
+	if _, err := utils.ParseClock(d.Time); err != nil {
+		return fmt.Errorf("摘要发送时间无效: %w", err)
+	}
+	if d.Frequency == DigestFrequencyWeekly && (d.Weekday < 0 || d.Weekday > 6) {
+		return fmt.Errorf("摘要发送日无效: %d", d.Weekday)
+	}
+	return nil
+}
+
+// Period 返回距 now 最近一次（不晚于 now）的计划发送时间以及该次摘要统计周期的起始时间
+func (d DigestSettings) Period(now time.Time) (time.Time, time.Time, error) {
+	minute, err := utils.ParseClock(d.Time)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	fireAt := time.Date(now.Year(), now.Month(), now.Day(), minute/60, minute%60, 0, 0, now.Location())
+	if d.Frequency == DigestFrequencyWeekly {
+		fireAt = fireAt.AddDate(0, 0, -((int(now.Weekday()) - d.Weekday + 7) % 7))
+		if fireAt.After(now) {
+			fireAt = fireAt.AddDate(0, 0, -7)
+		}
+		return fireAt, fireAt.AddDate(0, 0, -7), nil
+	}
+
+	if fireAt.After(now) {
+		fireAt = fireAt.AddDate(0, 0, -1)
+	}
+	return fireAt, fireAt.AddDate(0, 0, -1), nil
+}
+
+// QuietHours 渠道免打扰时段，时段内的非紧急通知延迟到时段结束后发送
+type QuietHours struct {
+	Enabled bool   `json:"enabled"`
+	Start   string `json:"start"` // 开始时间，格式 HH:MM
+	End     string `json:"end"`   // 结束时间，格式 HH:MM，早于开始时间表示跨越午夜
+}
+
+// Validate 校验免打扰时段
+func (q QuietHours) Validate() error {
+	if !q.Enabled {
+		return nil
+	}
+	if _, err := utils.ParseClockWindow(q.Start, q.End); err != nil {
+		return fmt.Errorf("免打扰时段无效: %w", err)
+	}
+	return nil
+}
+
+// Until 判断 now 是否处于免打扰时段，是则返回时段结束时间
+func (q QuietHours) Until(now time.Time) (time.Time, bool) {
+	if !q.Enabled {
+		return time.Time{}, false
+	}
+	window, err := utils.ParseClockWindow(q.Start, q.End)
+	if err != nil || !window.Contains(now) {
+		return time.Time{}, false
+	}
+	return window.EndAfter(now), true
+}
+
+// DigestSummary 摘要通知的统计数据
+type DigestSummary struct {
+	Frequency       string              `json:"frequency"`
+	PeriodStart     string              `json:"periodStart"`
+	PeriodEnd       string              `json:"periodEnd"`
+	TotalRuns       int                 `json:"totalRuns"`
+	CompletedRuns   int                 `json:"completedRuns"`
+	FailedRuns      int                 `json:"failedRuns"`
+	GeneratedFile   int                 `json:"generatedFile"`
+	FailedFiles     int                 `json:"failedFiles"`
+	Tasks           []DigestTaskSummary `json:"tasks"`
+	Failures        []DigestFailure     `json:"failures"`
+	FailuresOmitted int                 `json:"failuresOmitted"`
+}
+
+// DigestTaskSummary 摘要中单个任务的汇总
+type DigestTaskSummary struct {
+	TaskID             uint   `json:"taskId"`
+	TaskName           string `json:"taskName"`
+	Runs               int    `json:"runs"`
+	Completed          int    `json:"completed"`
+	Failed             int    `json:"failed"`
+	GeneratedFile      int    `json:"generatedFile"`
+	SkipFile           int    `json:"skipFile"`
+	MetadataDownloaded int    `json:"metadataDownloaded"`
+	SubtitleDownloaded int    `json:"subtitleDownloaded"`
+	FailedCount        int    `json:"failedCount"`
+}
+
+// DigestFailure 摘要中的一次失败执行
+type DigestFailure struct {
+	TaskName string `json:"taskName"`
+	Time     string `json:"time"`
+	Message  string `json:"message"`
+}
@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursUntil(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		now   time.Time
+		want  time.Time
+		ok    bool
+	}{
+		{name: "未启用", quiet: QuietHours{Start: "23:00", End: "07:00"}, now: at(10, 23, 30)},
+		{name: "同一天内", quiet: QuietHours{Enabled: true, Start: "13:00", End: "14:00"}, now: at(10, 13, 30), want: at(10, 14, 0), ok: true},
+		{name: "结束时间不包含", quiet: QuietHours{Enabled: true, Start: "13:00", End: "14:00"}, now: at(10, 14, 0)},
+		{name: "跨越午夜-午夜前", quiet: QuietHours{Enabled: true, Start: "23:00", End: "07:00"}, now: at(10, 23, 30), want: at(11, 7, 0), ok: true},
+		{name: "跨越午夜-午夜后", quiet: QuietHours{Enabled: true, Start: "23:00", End: "07:00"}, now: at(11, 6, 59), want: at(11, 7, 0), ok: true},
+		{name: "跨越午夜-时段外", quiet: QuietHours{Enabled: true, Start: "23:00", End: "07:00"}, now: at(10, 12, 0)},
+		{name: "结束于 24:00", quiet: QuietHours{Enabled: true, Start: "22:00", End: "24:00"}, now: at(10, 23, 0), want: at(11, 0, 0), ok: true},
+		{name: "时间无效", quiet: QuietHours{Enabled: true, Start: "23:00", End: "bad"}, now: at(10, 23, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.quiet.Until(tt.now)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("Until(%s) = (%s, %v), want (%s, %v)", tt.now, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestQuietHoursValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		quiet   QuietHours
+		wantErr bool
+	}{
+		{name: "未启用时不校验", quiet: QuietHours{Start: "bad"}},
+		{name: "跨越午夜", quiet: QuietHours{Enabled: true, Start: "23:00", End: "07:00"}},
+		{name: "结束于 24:00", quiet: QuietHours{Enabled: true, Start: "22:00", End: "24:00"}},
+		{name: "开始与结束相同", quiet: QuietHours{Enabled: true, Start: "7:00", End: "07:00"}, wantErr: true},
+		{name: "00:00 与 24:00 相同", quiet: QuietHours{Enabled: true, Start: "00:00", End: "24:00"}, wantErr: true},
+		{name: "开始时间无效", quiet: QuietHours{Enabled: true, Start: "", End: "07:00"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.quiet.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDigestSettingsPeriod(t *testing.T) {
+	now := time.Date(2024, 3, 13, 10, 0, 0, 0, time.UTC) // 周三
+	tests := []struct {
+		name      string
+		settings  DigestSettings
+		wantFire  time.Time
+		wantStart time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "每日-今天已到发送时间",
+			settings:  DigestSettings{Frequency: DigestFrequencyDaily, Time: "09:00"},
+			wantFire:  time.Date(2024, 3, 13, 9, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2024, 3, 12, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "每日-今天未到发送时间",
+			settings:  DigestSettings{Frequency: DigestFrequencyDaily, Time: "21:30"},
+			wantFire:  time.Date(2024, 3, 12, 21, 30, 0, 0, time.UTC),
+			wantStart: time.Date(2024, 3, 11, 21, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "每日-24:00 视为 00:00",
+			settings:  DigestSettings{Frequency: DigestFrequencyDaily, Time: "24:00"},
+			wantFire:  time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "每周",
+			settings:  DigestSettings{Frequency: DigestFrequencyWeekly, Time: "08:00", Weekday: 1},
+			wantFire:  time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "时间无效",
+			settings: DigestSettings{Frequency: DigestFrequencyDaily, Time: "9"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fire, start, err := tt.settings.Period(now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Period() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (!fire.Equal(tt.wantFire) || !start.Equal(tt.wantStart)) {
+				t.Errorf("Period() = (%s, %s), want (%s, %s)", fire, start, tt.wantFire, tt.wantStart)
+			}
+		})
+	}
+}
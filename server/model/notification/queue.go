@@ -1,6 +1,10 @@
 package notification
 
 import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -66,12 +70,187 @@ type TaskNotificationData struct {
 	SubtitleSkipped int    `json:"subtitleSkipped"` // 跳过的字幕文件数
 	OtherSkipped    int    `json:"otherSkipped"`    // 跳过的其他文件数
 	ErrorMessage    string `json:"errorMessage,omitempty"`
-	EventTime       string `json:"eventTime"`  // 事件发生时间，格式为 2006-01-02 15:04:05
-	SourcePath      string `json:"sourcePath"` // 任务源路径
-	TargetPath      string `json:"targetPath"` // 任务目标路径
+	EventTime       string `json:"eventTime"`           // 事件发生时间，格式为 2006-01-02 15:04:05
+	SourcePath      string `json:"sourcePath"`          // 任务源路径
+	TargetPath      string `json:"targetPath"`          // 任务目标路径
+	ScopePath       string `json:"scopePath,omitempty"` // 局部扫描的源子路径，为空表示完整扫描
+
+	// 以下字段仅用于新增媒体通知
+	NewMediaCount   int             `json:"newMediaCount,omitempty"`   // 新增媒体总数
+	NewMedia        []NewMediaGroup `json:"newMedia,omitempty"`        // 按剧集/目录分组的新增媒体（已截断）
+	NewMediaOmitted int             `json:"newMediaOmitted,omitempty"` // 因分组数量限制未列出的条目数
+	PosterPaths     []string        `json:"posterPaths,omitempty"`     // 用于在 Emby 中查找海报的 STRM 文件路径
+	Link            string          `json:"link,omitempty"`            // 点击通知时跳转的链接，如 Emby 网页地址
+	Poster          []byte          `json:"-"`                         // 发送前从 Emby 获取的海报图片
+	PosterType      string          `json:"-"`                         // 海报图片的内容类型
+
+	// 以下字段仅用于摘要通知，摘要中的新增媒体复用上面的新增媒体字段
+	Digest *DigestSummary `json:"digest,omitempty"`
+
+	// 以下字段仅用于源站异常/恢复通知
+	Health *SourceHealthEvent `json:"health,omitempty"`
+}
+
+// SourceHealthEvent 源站状态变化
+type SourceHealthEvent struct {
+	Source        string   `json:"source"`                  // alist / clouddrive / emby
+	SourceName    string   `json:"sourceName"`              // 源站显示名称
+	Message       string   `json:"message,omitempty"`       // 最近一次失败原因
+	Failures      int      `json:"failures,omitempty"`      // 连续失败次数
+	LatencyMs     int64    `json:"latencyMs"`               // 最近一次检查耗时，毫秒
+	DownSince     string   `json:"downSince,omitempty"`     // 开始异常的时间
+	DownDuration  string   `json:"downDuration,omitempty"`  // 异常持续时长，仅恢复通知
+	AffectedTasks []string `json:"affectedTasks,omitempty"` // 使用该源站的已启用任务
+}
+
+// NewMediaGroup 新增媒体分组
+type NewMediaGroup struct {
+	Name  string   `json:"name"`           // 分组名称，剧集名（含季）或所在目录名
+	Items []string `json:"items"`          // 新增条目名称（不含扩展名）
+	More  int      `json:"more,omitempty"` // 因截断未列出的条目数
 }
 
 // GetTaskName 获取任务名称
 func (d *TaskNotificationData) GetTaskName() string {
 	return d.TaskName
 }
+
+// SampleTaskNotificationData 生成用于测试发送和模板预览的示例通知数据
+func SampleTaskNotificationData(templateType TemplateType) *TaskNotificationData {
+	data := &TaskNotificationData{
+		TaskID:             1,
+		TaskName:           "示例任务",
+		Status:             "completed",
+		Duration:           42,
+		TotalFile:          128,
+		GeneratedFile:      96,
+		SkipFile:           30,
+		OverwriteFile:      2,
+		MetadataCount:      24,
+		SubtitleCount:      8,
+		MetadataDownloaded: 20,
+		SubtitleDownloaded: 6,
+		MetadataSkipped:    4,
+		SubtitleSkipped:    2,
+		EventTime:          time.Now().Format("2006-01-02 15:04:05"),
+		SourcePath:         "/alist/电视剧",
+		TargetPath:         "/media/电视剧",
+	}
+
+	if templateType == TemplateTypeNewMedia || templateType == TemplateTypeDigest {
+		paths := []string{
+			"/media/电视剧/示例剧集/Season 1/示例剧集 S01E01.strm",
+			"/media/电视剧/示例剧集/Season 1/示例剧集 S01E02.strm",
+			"/media/电影/示例电影 (2024)/示例电影 (2024).strm",
+		}
+		data.NewMedia, data.NewMediaOmitted = BuildNewMediaGroups("/media", paths, DefaultNewMediaMaxGroups, DefaultNewMediaMaxItemsPerGroup)
+		data.NewMediaCount = len(paths)
+	}
+
+	if templateType == TemplateTypeDigest {
+		now := time.Now()
+		data.TaskName = "通知摘要"
+		data.Digest = &DigestSummary{
+			Frequency:     DigestFrequencyDaily,
+			PeriodStart:   now.AddDate(0, 0, -1).Format("2006-01-02 15:04"),
+			PeriodEnd:     now.Format("2006-01-02 15:04"),
+			TotalRuns:     5,
+			CompletedRuns: 4,
+			FailedRuns:    1,
+			GeneratedFile: 99,
+			FailedFiles:   3,
+			Tasks: []DigestTaskSummary{
+				{TaskID: 1, TaskName: "示例任务", Runs: 4, Completed: 4, GeneratedFile: 96, SkipFile: 30, MetadataDownloaded: 20, SubtitleDownloaded: 6},
+				{TaskID: 2, TaskName: "示例电影", Runs: 1, Failed: 1, GeneratedFile: 3, FailedCount: 3},
+			},
+			Failures: []DigestFailure{
+				{TaskName: "示例电影", Time: now.Add(-2 * time.Hour).Format("2006-01-02 15:04:05"), Message: "连接 AList 超时"},
+			},
+		}
+	}
+
+	if templateType == TemplateTypeSourceDown || templateType == TemplateTypeSourceRecovered {
+		data.TaskName = "AList"
+		data.Health = &SourceHealthEvent{
+			Source:        "alist",
+			SourceName:    "AList",
+			Failures:      2,
+			LatencyMs:     120,
+			DownSince:     time.Now().Add(-15 * time.Minute).Format("2006-01-02 15:04:05"),
+			AffectedTasks: []string{"示例任务", "示例电影"},
+		}
+		if templateType == TemplateTypeSourceDown {
+			data.Status = "failed"
+			data.Health.LatencyMs = 30000
+			data.Health.Message = "这是一条测试通知：连接测试失败: context deadline exceeded"
+		} else {
+			data.Health.DownDuration = "15分0秒"
+		}
+	}
+
+	if templateType == TemplateTypeTaskFailed {
+		data.Status = "failed"
+		data.GeneratedFile = 12
+		data.FailedCount = 3
+		data.ErrorMessage = "这是一条测试通知：连接 AList 超时"
+	}
+
+	return data
+}
+
+// seasonDirPattern 季目录名称，如 Season 1、S01、第1季、Specials
+var seasonDirPattern = regexp.MustCompile(`(?i)^(season\s*\d+|s\d{1,3}|第.+季|specials?)$`)
+
+// BuildNewMediaGroups 将新增的 STRM 文件按剧集或所在目录分组，并按数量限制截断
+// 返回截断后的分组以及因分组数量限制而未列出的条目数
+func BuildNewMediaGroups(rootPath string, paths []string, maxGroups, maxItemsPerGroup int) ([]NewMediaGroup, int) {
+	rootPath = filepath.Clean(rootPath)
+	groupItems := make(map[string][]string)
+	var order []string
+
+	for _, p := range paths {
+		name := newMediaGroupName(rootPath, p)
+		if _, exists := groupItems[name]; !exists {
+			order = append(order, name)
+		}
+		base := filepath.Base(p)
+		groupItems[name] = append(groupItems[name], strings.TrimSuffix(base, filepath.Ext(base)))
+	}
+	sort.Strings(order)
+
+	groups := make([]NewMediaGroup, 0, len(order))
+	omitted := 0
+	for i, name := range order {
+		items := groupItems[name]
+		if maxGroups > 0 && i >= maxGroups {
+			omitted += len(items)
+			continue
+		}
+		sort.Strings(items)
+		group := NewMediaGroup{Name: name, Items: items}
+		if maxItemsPerGroup > 0 && len(items) > maxItemsPerGroup {
+			group.Items = items[:maxItemsPerGroup]
+			group.More = len(items) - maxItemsPerGroup
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, omitted
+}
+
+// newMediaGroupName 获取 STRM 文件所属分组名称：位于季目录时使用“剧集名 / 季”，否则使用所在目录名
+func newMediaGroupName(rootPath, filePath string) string {
+	dir := filepath.Dir(filepath.Clean(filePath))
+	if dir == rootPath || dir == "." || dir == string(filepath.Separator) {
+		return filepath.Base(rootPath)
+	}
+
+	dirName := filepath.Base(dir)
+	if seasonDirPattern.MatchString(dirName) {
+		parent := filepath.Dir(dir)
+		if parent != rootPath && parent != "." {
+			return filepath.Base(parent) + " / " + dirName
+		}
+	}
+	return dirName
+}
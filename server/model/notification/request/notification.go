@@ -0,0 +1,33 @@
+package request
+
+import (
+	"github.com/MccRay-s/alist2strm/model/common/request"
+	"github.com/MccRay-s/alist2strm/model/notification"
+)
+
+// NotificationTestReq 发送测试通知请求
+type NotificationTestReq struct {
+	ChannelType  string `json:"channelType" binding:"required" validate:"required" example:"bark"` // 通知渠道类型
+	TemplateType string `json:"templateType" example:"taskComplete"`                               // 模板类型 taskComplete/taskFailed/newMedia/digest，默认 taskComplete
+}
+
+// NotificationTemplatePreviewReq 模板校验与预览请求
+type NotificationTemplatePreviewReq struct {
+	TemplateType string                             `json:"templateType" binding:"required,oneof=taskComplete taskFailed newMedia digest sourceDown sourceRecovered" example:"taskComplete"` // 模板类型
+	TemplateKey  string                             `json:"templateKey" binding:"required" example:"telegram"`                                                                               // 模板键，通常为渠道类型，邮件 HTML 正文为 emailHtml
+	Content      string                             `json:"content" example:"任务 {{.TaskName}} 已完成"`                                                                                          // 模板内容，为空时使用当前已保存的模板
+	Data         *notification.TaskNotificationData `json:"data"`                                                                                                                            // 预览数据，为空时使用示例数据
+}
+
+// NotificationQueueListReq 通知队列列表查询请求
+type NotificationQueueListReq struct {
+	request.PageInfo
+	Status       string `json:"status" form:"status" binding:"omitempty,oneof=pending processing sent failed" example:"failed"` // 状态筛选
+	ChannelType  string `json:"channelType" form:"channelType" example:"telegram"`                                              // 渠道类型筛选
+	TemplateType string `json:"templateType" form:"templateType" example:"taskFailed"`                                          // 模板类型筛选
+}
+
+// NotificationQueueCleanReq 按状态批量删除通知队列请求
+type NotificationQueueCleanReq struct {
+	Status string `json:"status" form:"status" binding:"required,oneof=pending sent failed" example:"sent"` // 需要删除的状态，处理中的通知不允许删除
+}
@@ -0,0 +1,21 @@
+package response
+
+import "github.com/MccRay-s/alist2strm/model/notification"
+
+// NotificationTemplatePreviewResp 模板预览响应
+type NotificationTemplatePreviewResp struct {
+	Content string `json:"content"` // 渲染后的内容
+}
+
+// NotificationQueueListResp 通知队列列表响应
+type NotificationQueueListResp struct {
+	List     []notification.Queue `json:"list"`
+	Total    int64                `json:"total"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"pageSize"`
+}
+
+// NotificationQueueBatchResp 通知队列批量操作响应
+type NotificationQueueBatchResp struct {
+	Count int64 `json:"count"` // 受影响的通知数量
+}
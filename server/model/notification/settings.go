@@ -7,19 +7,35 @@ type Settings struct {
 	Channels       map[string]ChannelConfig  `json:"channels"`
 	Templates      map[string]TemplateConfig `json:"templates"`
 	QueueSettings  QueueSettings             `json:"queueSettings"`
+	NewMedia       NewMediaSettings          `json:"newMedia"`
+	Digest         DigestSettings            `json:"digest"`
 }
 
 // ChannelConfig 通知渠道配置
 type ChannelConfig struct {
-	Enabled bool              `json:"enabled"`
-	Type    string            `json:"type"`
-	Config  map[string]string `json:"config"`
+	Enabled    bool              `json:"enabled"`
+	Type       string            `json:"type"`
+	Config     map[string]string `json:"config"`
+	QuietHours QuietHours        `json:"quietHours"`
 }
 
-// TemplateConfig 模板配置
-type TemplateConfig struct {
-	Telegram string `json:"telegram"`
-	Wework   string `json:"wework"`
+// TemplateConfig 模板配置，键为渠道类型（或渠道的附加模板键），值为模板内容
+type TemplateConfig map[string]string
+
+// 渠道附加模板键
+const (
+	// TemplateKeyEmailHTML 邮件 HTML 正文模板键，邮件纯文本正文使用渠道类型 email 作为键
+	TemplateKeyEmailHTML = "emailHtml"
+	// TemplateKeyWeworkNews 企业微信图文消息正文模板键（纯文本），用于新增媒体通知
+	TemplateKeyWeworkNews = "weworkNews"
+)
+
+// Get 获取指定键的模板内容
+func (t TemplateConfig) Get(key string) string {
+	if t == nil {
+		return ""
+	}
+	return t[key]
 }
 
 // QueueSettings 队列设置
@@ -29,6 +45,32 @@ type QueueSettings struct {
 	Concurrency   int `json:"concurrency"`
 }
 
+// NewMediaSettings 新增媒体通知设置
+type NewMediaSettings struct {
+	MaxGroups        int  `json:"maxGroups"`        // 最多列出的分组（剧集/目录）数
+	MaxItemsPerGroup int  `json:"maxItemsPerGroup"` // 每个分组最多列出的条目数
+	IncludePoster    bool `json:"includePoster"`    // 是否从 Emby 获取海报（Telegram 以图片消息发送，企业微信以图文消息发送）
+}
+
+// 新增媒体通知的默认截断参数
+const (
+	DefaultNewMediaMaxGroups        = 10
+	DefaultNewMediaMaxItemsPerGroup = 5
+)
+
+// Limits 获取分组截断参数，未配置时使用默认值
+func (n NewMediaSettings) Limits() (int, int) {
+	maxGroups := n.MaxGroups
+	if maxGroups <= 0 {
+		maxGroups = DefaultNewMediaMaxGroups
+	}
+	maxItems := n.MaxItemsPerGroup
+	if maxItems <= 0 {
+		maxItems = DefaultNewMediaMaxItemsPerGroup
+	}
+	return maxGroups, maxItems
+}
+
 // NotificationChannelType 通知渠道类型
 type NotificationChannelType string
 
@@ -37,6 +79,28 @@ const (
 	ChannelTypeTelegram NotificationChannelType = "telegram"
 	// ChannelTypeWework 企业微信通知渠道
 	ChannelTypeWework NotificationChannelType = "wework"
+	// ChannelTypeWebhook 通用 Webhook 通知渠道
+	ChannelTypeWebhook NotificationChannelType = "webhook"
+	// ChannelTypeEmail SMTP 邮件通知渠道
+	ChannelTypeEmail NotificationChannelType = "email"
+	// ChannelTypeDiscord Discord Webhook 通知渠道
+	ChannelTypeDiscord NotificationChannelType = "discord"
+	// ChannelTypeSlack Slack Incoming Webhook 通知渠道
+	ChannelTypeSlack NotificationChannelType = "slack"
+	// ChannelTypeDingtalk 钉钉机器人通知渠道
+	ChannelTypeDingtalk NotificationChannelType = "dingtalk"
+	// ChannelTypeFeishu 飞书/Lark 机器人通知渠道
+	ChannelTypeFeishu NotificationChannelType = "feishu"
+	// ChannelTypeBark Bark 推送渠道
+	ChannelTypeBark NotificationChannelType = "bark"
+	// ChannelTypeNtfy ntfy 推送渠道
+	ChannelTypeNtfy NotificationChannelType = "ntfy"
+	// ChannelTypeGotify Gotify 推送渠道
+	ChannelTypeGotify NotificationChannelType = "gotify"
+	// ChannelTypeServerChan Server酱 推送渠道
+	ChannelTypeServerChan NotificationChannelType = "serverchan"
+	// ChannelTypePushPlus PushPlus 推送渠道
+	ChannelTypePushPlus NotificationChannelType = "pushplus"
 )
 
 // TemplateType 通知模板类型
@@ -47,6 +111,69 @@ const (
 	TemplateTypeTaskComplete TemplateType = "taskComplete"
 	// TemplateTypeTaskFailed 任务失败通知模板
 	TemplateTypeTaskFailed TemplateType = "taskFailed"
+	// TemplateTypeNewMedia 新增媒体通知模板
+	TemplateTypeNewMedia TemplateType = "newMedia"
+	// TemplateTypeDigest 定期摘要通知模板
+	TemplateTypeDigest TemplateType = "digest"
+	// TemplateTypeSourceDown 源站异常通知模板
+	TemplateTypeSourceDown TemplateType = "sourceDown"
+	// TemplateTypeSourceRecovered 源站恢复通知模板
+	TemplateTypeSourceRecovered TemplateType = "sourceRecovered"
+)
+
+// IsValidTemplateType 判断模板类型是否有效
+func IsValidTemplateType(templateType TemplateType) bool {
+	switch templateType {
+	case TemplateTypeTaskComplete, TemplateTypeTaskFailed, TemplateTypeNewMedia, TemplateTypeDigest,
+		TemplateTypeSourceDown, TemplateTypeSourceRecovered:
+		return true
+	}
+	return false
+}
+
+// IsCritical 判断是否为紧急通知（任务失败、源站异常），紧急通知不受免打扰时段和摘要设置影响，并按失败级别展示
+func (t TemplateType) IsCritical() bool {
+	return t == TemplateTypeTaskFailed || t == TemplateTypeSourceDown
+}
+
+// 默认模板中被多个渠道共用的内容
+const (
+	// defaultMarkdownCompleteTemplate 标准 Markdown 格式的任务完成模板
+	defaultMarkdownCompleteTemplate = "**任务名称**：{{.TaskName}}\n**完成时间**：{{.EventTime}}\n**处理耗时**：{{.Duration}} 秒\n\n**STRM 文件**：已生成 {{.GeneratedFile}}，已跳过 {{.SkipFile}}\n**元数据**：已下载 {{.MetadataDownloaded}}，已跳过 {{.MetadataSkipped}}\n**字幕**：已下载 {{.SubtitleDownloaded}}，已跳过 {{.SubtitleSkipped}}\n**失败**：{{.FailedCount}}\n\n**源路径**：`{{.SourcePath}}`\n**目标路径**：`{{.TargetPath}}`"
+	// defaultMarkdownFailedTemplate 标准 Markdown 格式的任务失败模板
+	defaultMarkdownFailedTemplate = "**任务名称**：{{.TaskName}}\n**时间**：{{.EventTime}}\n**耗时**：{{.Duration}} 秒\n**错误信息**：\n```\n{{.ErrorMessage}}\n```"
+	// defaultSlackCompleteTemplate Slack mrkdwn 格式的任务完成模板
+	defaultSlackCompleteTemplate = "*任务名称*：`{{.TaskName}}`\n*完成时间*：{{.EventTime}}\n*处理耗时*：{{.Duration}} 秒\n\n*STRM 文件*：已生成 {{.GeneratedFile}}，已跳过 {{.SkipFile}}\n*元数据*：已下载 {{.MetadataDownloaded}}，已跳过 {{.MetadataSkipped}}\n*字幕*：已下载 {{.SubtitleDownloaded}}，已跳过 {{.SubtitleSkipped}}\n*失败*：{{.FailedCount}}\n\n*源路径*：`{{.SourcePath}}`\n*目标路径*：`{{.TargetPath}}`"
+	// defaultSlackFailedTemplate Slack mrkdwn 格式的任务失败模板
+	defaultSlackFailedTemplate = "*任务名称*：`{{.TaskName}}`\n*时间*：{{.EventTime}}\n*耗时*：{{.Duration}} 秒\n*错误信息*：\n```{{.ErrorMessage}}```"
+	// defaultTextCompleteTemplate 纯文本格式的任务完成模板
+	defaultTextCompleteTemplate = "任务完成通知\n\n任务名称：{{.TaskName}}\n完成时间：{{.EventTime}}\n处理耗时：{{.Duration}} 秒\n\nSTRM 文件：已生成 {{.GeneratedFile}}，已跳过 {{.SkipFile}}\n元数据：已下载 {{.MetadataDownloaded}}，已跳过 {{.MetadataSkipped}}\n字幕：已下载 {{.SubtitleDownloaded}}，已跳过 {{.SubtitleSkipped}}\n失败：{{.FailedCount}}\n\n源路径：{{.SourcePath}}\n目标路径：{{.TargetPath}}"
+	// defaultTextFailedTemplate 纯文本格式的任务失败模板
+	defaultTextFailedTemplate = "任务失败通知\n\n任务名称：{{.TaskName}}\n时间：{{.EventTime}}\n耗时：{{.Duration}} 秒\n错误信息：{{.ErrorMessage}}"
+	// defaultMarkdownNewMediaTemplate 标准 Markdown 格式的新增媒体模板
+	defaultMarkdownNewMediaTemplate = "**任务名称**：{{.TaskName}}\n**时间**：{{.EventTime}}\n**新增媒体**：{{.NewMediaCount}} 个\n{{range .NewMedia}}\n**{{.Name}}**\n{{range .Items}}- {{.}}\n{{end}}{{if .More}}- …… 等 {{.More}} 个\n{{end}}{{end}}{{if .NewMediaOmitted}}\n另有 {{.NewMediaOmitted}} 个未列出{{end}}"
+	// defaultSlackNewMediaTemplate Slack mrkdwn 格式的新增媒体模板
+	defaultSlackNewMediaTemplate = "*任务名称*：`{{.TaskName}}`\n*时间*：{{.EventTime}}\n*新增媒体*：{{.NewMediaCount}} 个\n{{range .NewMedia}}\n*{{.Name}}*\n{{range .Items}}• {{.}}\n{{end}}{{if .More}}• …… 等 {{.More}} 个\n{{end}}{{end}}{{if .NewMediaOmitted}}\n另有 {{.NewMediaOmitted}} 个未列出{{end}}"
+	// defaultTextNewMediaTemplate 纯文本格式的新增媒体模板
+	defaultTextNewMediaTemplate = "新增媒体通知\n\n任务名称：{{.TaskName}}\n时间：{{.EventTime}}\n新增媒体：{{.NewMediaCount}} 个\n{{range .NewMedia}}\n【{{.Name}}】\n{{range .Items}}· {{.}}\n{{end}}{{if .More}}· …… 等 {{.More}} 个\n{{end}}{{end}}{{if .NewMediaOmitted}}\n另有 {{.NewMediaOmitted}} 个未列出{{end}}"
+	// defaultMarkdownDigestTemplate 标准 Markdown 格式的摘要模板
+	defaultMarkdownDigestTemplate = "**统计周期**：{{.Digest.PeriodStart}} ~ {{.Digest.PeriodEnd}}\n**执行次数**：{{.Digest.TotalRuns}}（成功 {{.Digest.CompletedRuns}}，失败 {{.Digest.FailedRuns}}）\n**新增 STRM**：{{.Digest.GeneratedFile}}，**失败文件**：{{.Digest.FailedFiles}}\n{{if .Digest.Tasks}}\n**任务汇总**\n{{range .Digest.Tasks}}- {{.TaskName}}：执行 {{.Runs}} 次（成功 {{.Completed}}，失败 {{.Failed}}），生成 {{.GeneratedFile}}，失败文件 {{.FailedCount}}\n{{end}}{{end}}{{if .Digest.Failures}}\n**失败记录**\n{{range .Digest.Failures}}- {{.Time}} {{.TaskName}}：{{.Message}}\n{{end}}{{if .Digest.FailuresOmitted}}- …… 另有 {{.Digest.FailuresOmitted}} 条\n{{end}}{{end}}{{if .NewMedia}}\n**新增媒体**：{{.NewMediaCount}} 个\n{{range .NewMedia}}\n**{{.Name}}**\n{{range .Items}}- {{.}}\n{{end}}{{if .More}}- …… 等 {{.More}} 个\n{{end}}{{end}}{{if .NewMediaOmitted}}\n另有 {{.NewMediaOmitted}} 个未列出{{end}}{{end}}"
+	// defaultSlackDigestTemplate Slack mrkdwn 格式的摘要模板
+	defaultSlackDigestTemplate = "*统计周期*：{{.Digest.PeriodStart}} ~ {{.Digest.PeriodEnd}}\n*执行次数*：{{.Digest.TotalRuns}}（成功 {{.Digest.CompletedRuns}}，失败 {{.Digest.FailedRuns}}）\n*新增 STRM*：{{.Digest.GeneratedFile}}，*失败文件*：{{.Digest.FailedFiles}}\n{{if .Digest.Tasks}}\n*任务汇总*\n{{range .Digest.Tasks}}• {{.TaskName}}：执行 {{.Runs}} 次（成功 {{.Completed}}，失败 {{.Failed}}），生成 {{.GeneratedFile}}，失败文件 {{.FailedCount}}\n{{end}}{{end}}{{if .Digest.Failures}}\n*失败记录*\n{{range .Digest.Failures}}• {{.Time}} {{.TaskName}}：{{.Message}}\n{{end}}{{if .Digest.FailuresOmitted}}• …… 另有 {{.Digest.FailuresOmitted}} 条\n{{end}}{{end}}{{if .NewMedia}}\n*新增媒体*：{{.NewMediaCount}} 个\n{{range .NewMedia}}\n*{{.Name}}*\n{{range .Items}}• {{.}}\n{{end}}{{if .More}}• …… 等 {{.More}} 个\n{{end}}{{end}}{{if .NewMediaOmitted}}\n另有 {{.NewMediaOmitted}} 个未列出{{end}}{{end}}"
+	// defaultTextDigestTemplate 纯文本格式的摘要模板
+	defaultTextDigestTemplate = "通知摘要\n\n统计周期：{{.Digest.PeriodStart}} ~ {{.Digest.PeriodEnd}}\n执行次数：{{.Digest.TotalRuns}}（成功 {{.Digest.CompletedRuns}}，失败 {{.Digest.FailedRuns}}）\n新增 STRM：{{.Digest.GeneratedFile}}，失败文件：{{.Digest.FailedFiles}}\n{{if .Digest.Tasks}}\n任务汇总\n{{range .Digest.Tasks}}· {{.TaskName}}：执行 {{.Runs}} 次（成功 {{.Completed}}，失败 {{.Failed}}），生成 {{.GeneratedFile}}，失败文件 {{.FailedCount}}\n{{end}}{{end}}{{if .Digest.Failures}}\n失败记录\n{{range .Digest.Failures}}· {{.Time}} {{.TaskName}}：{{.Message}}\n{{end}}{{if .Digest.FailuresOmitted}}· …… 另有 {{.Digest.FailuresOmitted}} 条\n{{end}}{{end}}{{if .NewMedia}}\n新增媒体：{{.NewMediaCount}} 个\n{{range .NewMedia}}\n【{{.Name}}】\n{{range .Items}}· {{.}}\n{{end}}{{if .More}}· …… 等 {{.More}} 个\n{{end}}{{end}}{{if .NewMediaOmitted}}\n另有 {{.NewMediaOmitted}} 个未列出{{end}}{{end}}"
+	// defaultMarkdownSourceDownTemplate 标准 Markdown 格式的源站异常模板
+	defaultMarkdownSourceDownTemplate = "**源站**：{{.Health.SourceName}}\n**时间**：{{.EventTime}}\n**连续失败**：{{.Health.Failures}} 次\n**错误信息**：\n```\n{{.Health.Message}}\n```{{if .Health.AffectedTasks}}\n\n**受影响的任务**\n{{range .Health.AffectedTasks}}- {{.}}\n{{end}}{{end}}"
+	// defaultSlackSourceDownTemplate Slack mrkdwn 格式的源站异常模板
+	defaultSlackSourceDownTemplate = "*源站*：`{{.Health.SourceName}}`\n*时间*：{{.EventTime}}\n*连续失败*：{{.Health.Failures}} 次\n*错误信息*：\n```{{.Health.Message}}```{{if .Health.AffectedTasks}}\n\n*受影响的任务*\n{{range .Health.AffectedTasks}}• {{.}}\n{{end}}{{end}}"
+	// defaultTextSourceDownTemplate 纯文本格式的源站异常模板
+	defaultTextSourceDownTemplate = "源站异常告警\n\n源站：{{.Health.SourceName}}\n时间：{{.EventTime}}\n连续失败：{{.Health.Failures}} 次\n错误信息：{{.Health.Message}}{{if .Health.AffectedTasks}}\n\n受影响的任务\n{{range .Health.AffectedTasks}}· {{.}}\n{{end}}{{end}}"
+	// defaultMarkdownSourceRecoveredTemplate 标准 Markdown 格式的源站恢复模板
+	defaultMarkdownSourceRecoveredTemplate = "**源站**：{{.Health.SourceName}}\n**恢复时间**：{{.EventTime}}\n**异常持续**：{{.Health.DownDuration}}\n**响应耗时**：{{.Health.LatencyMs}} ms"
+	// defaultSlackSourceRecoveredTemplate Slack mrkdwn 格式的源站恢复模板
+	defaultSlackSourceRecoveredTemplate = "*源站*：`{{.Health.SourceName}}`\n*恢复时间*：{{.EventTime}}\n*异常持续*：{{.Health.DownDuration}}\n*响应耗时*：{{.Health.LatencyMs}} ms"
+	// defaultTextSourceRecoveredTemplate 纯文本格式的源站恢复模板
+	defaultTextSourceRecoveredTemplate = "源站已恢复\n\n源站：{{.Health.SourceName}}\n恢复时间：{{.EventTime}}\n异常持续：{{.Health.DownDuration}}\n响应耗时：{{.Health.LatencyMs}} ms"
 )
 
 // DefaultSettings 返回默认通知设置
@@ -59,9 +186,11 @@ func DefaultSettings() *Settings {
 				Enabled: false,
 				Type:    string(ChannelTypeTelegram),
 				Config: map[string]string{
-					"botToken":  "",
-					"chatId":    "",
-					"parseMode": "Markdown",
+					"botToken":       "",
+					"chatId":         "",
+					"parseMode":      "Markdown",
+					"botEnabled":     "false",
+					"allowedChatIds": "",
 				},
 			},
 			string(ChannelTypeWework): {
@@ -74,15 +203,213 @@ func DefaultSettings() *Settings {
 					"toUser":     "",
 				},
 			},
+			string(ChannelTypeWebhook): {
+				Enabled: false,
+				Type:    string(ChannelTypeWebhook),
+				Config: map[string]string{
+					"url":             "",
+					"method":          "POST",
+					"contentType":     "application/json",
+					"headers":         "",
+					"secret":          "",
+					"signatureHeader": "X-Alist2strm-Signature",
+					"timeout":         "10",
+				},
+			},
+			string(ChannelTypeEmail): {
+				Enabled: false,
+				Type:    string(ChannelTypeEmail),
+				Config: map[string]string{
+					"host":       "",
+					"port":       "587",
+					"security":   "starttls",
+					"username":   "",
+					"password":   "",
+					"from":       "",
+					"to":         "",
+					"subject":    "[alist2strm] {{.TaskName}} - {{.Status}}",
+					"skipVerify": "false",
+				},
+			},
+			string(ChannelTypeDiscord): {
+				Enabled: false,
+				Type:    string(ChannelTypeDiscord),
+				Config: map[string]string{
+					"webhookUrl": "",
+					"username":   "alist2strm",
+					"avatarUrl":  "",
+				},
+			},
+			string(ChannelTypeSlack): {
+				Enabled: false,
+				Type:    string(ChannelTypeSlack),
+				Config: map[string]string{
+					"webhookUrl": "",
+				},
+			},
+			string(ChannelTypeDingtalk): {
+				Enabled: false,
+				Type:    string(ChannelTypeDingtalk),
+				Config: map[string]string{
+					"webhookUrl": "",
+					"secret":     "",
+				},
+			},
+			string(ChannelTypeFeishu): {
+				Enabled: false,
+				Type:    string(ChannelTypeFeishu),
+				Config: map[string]string{
+					"webhookUrl": "",
+					"secret":     "",
+				},
+			},
+			string(ChannelTypeBark): {
+				Enabled: false,
+				Type:    string(ChannelTypeBark),
+				Config: map[string]string{
+					"serverUrl":    "https://api.day.app",
+					"deviceKey":    "",
+					"group":        "alist2strm",
+					"sound":        "",
+					"icon":         "",
+					"successLevel": "active",
+					"failureLevel": "timeSensitive",
+				},
+			},
+			string(ChannelTypeNtfy): {
+				Enabled: false,
+				Type:    string(ChannelTypeNtfy),
+				Config: map[string]string{
+					"serverUrl":       "https://ntfy.sh",
+					"topic":           "",
+					"token":           "",
+					"username":        "",
+					"password":        "",
+					"successPriority": "3",
+					"failurePriority": "5",
+				},
+			},
+			string(ChannelTypeGotify): {
+				Enabled: false,
+				Type:    string(ChannelTypeGotify),
+				Config: map[string]string{
+					"serverUrl":       "",
+					"appToken":        "",
+					"successPriority": "5",
+					"failurePriority": "8",
+				},
+			},
+			string(ChannelTypeServerChan): {
+				Enabled: false,
+				Type:    string(ChannelTypeServerChan),
+				Config: map[string]string{
+					"sendKey":        "",
+					"successChannel": "",
+					"failureChannel": "",
+				},
+			},
+			string(ChannelTypePushPlus): {
+				Enabled: false,
+				Type:    string(ChannelTypePushPlus),
+				Config: map[string]string{
+					"token":          "",
+					"topic":          "",
+					"successChannel": "wechat",
+					"failureChannel": "wechat",
+				},
+			},
 		},
 		Templates: map[string]TemplateConfig{
 			string(TemplateTypeTaskComplete): {
-				Telegram: "🎬 *任务完成通知* ✅\n\n📋 *基本信息*\n• *任务名称*: `{{.TaskName}}`\n• *完成时间*: {{.EventTime}}\n• *处理耗时*: {{.Duration}}秒\n\n📊 *处理统计*\n• *STRM文件*: 总计 {{.GeneratedFile}}+{{.SkipFile}}\n  - 已生成: {{.GeneratedFile}}\n  - 已跳过: {{.SkipFile}}\n• *元数据*: 总计 {{.MetadataCount}}\n  - 已下载: {{.MetadataDownloaded}}\n  - 已跳过: {{.MetadataSkipped}}\n• *字幕*: 总计 {{.SubtitleCount}}\n  - 已下载: {{.SubtitleDownloaded}}\n  - 已跳过: {{.SubtitleSkipped}}\n\n📁 *路径信息*\n• *源路径*: `{{.SourcePath}}`\n• *目标路径*: `{{.TargetPath}}`",
-				Wework:   "🎬 任务完成通知 ✅\n\n## 📋 任务概览\n**任务名称**：<font color=\"info\">`{{.TaskName}}`</font>\n**完成时间**：{{.EventTime}}\n**处理耗时**：<font color=\"info\">{{.Duration}}</font> 秒\n\n## 📊 处理统计\n**STRM文件** (总计 {{.GeneratedFile}}+{{.SkipFile}})\n> 已生成：<font color=\"info\">{{.GeneratedFile}}</font> | 已跳过：<font color=\"info\">{{.SkipFile}}</font>\n\n**元数据文件** (总计 {{.MetadataCount}})\n> 已下载：<font color=\"info\">{{.MetadataDownloaded}}</font> | 已跳过：<font color=\"info\">{{.MetadataSkipped}}</font>\n\n**字幕文件** (总计 {{.SubtitleCount}})\n> 已下载：<font color=\"info\">{{.SubtitleDownloaded}}</font> | 已跳过：<font color=\"info\">{{.SubtitleSkipped}}</font>\n\n## 📂 路径信息\n**源路径**：`{{.SourcePath}}`\n**目标路径**：`{{.TargetPath}}`",
+				string(ChannelTypeTelegram):   "🎬 *任务完成通知* ✅\n\n📋 *基本信息*\n• *任务名称*: `{{.TaskName}}`\n• *完成时间*: {{.EventTime}}\n• *处理耗时*: {{.Duration}}秒\n\n📊 *处理统计*\n• *STRM文件*: 总计 {{.GeneratedFile}}+{{.SkipFile}}\n  - 已生成: {{.GeneratedFile}}\n  - 已跳过: {{.SkipFile}}\n• *元数据*: 总计 {{.MetadataCount}}\n  - 已下载: {{.MetadataDownloaded}}\n  - 已跳过: {{.MetadataSkipped}}\n• *字幕*: 总计 {{.SubtitleCount}}\n  - 已下载: {{.SubtitleDownloaded}}\n  - 已跳过: {{.SubtitleSkipped}}\n\n📁 *路径信息*\n• *源路径*: `{{.SourcePath}}`\n• *目标路径*: `{{.TargetPath}}`",
+				string(ChannelTypeWework):     "🎬 任务完成通知 ✅\n\n## 📋 任务概览\n**任务名称**：<font color=\"info\">`{{.TaskName}}`</font>\n**完成时间**：{{.EventTime}}\n**处理耗时**：<font color=\"info\">{{.Duration}}</font> 秒\n\n## 📊 处理统计\n**STRM文件** (总计 {{.GeneratedFile}}+{{.SkipFile}})\n> 已生成：<font color=\"info\">{{.GeneratedFile}}</font> | 已跳过：<font color=\"info\">{{.SkipFile}}</font>\n\n**元数据文件** (总计 {{.MetadataCount}})\n> 已下载：<font color=\"info\">{{.MetadataDownloaded}}</font> | 已跳过：<font color=\"info\">{{.MetadataSkipped}}</font>\n\n**字幕文件** (总计 {{.SubtitleCount}})\n> 已下载：<font color=\"info\">{{.SubtitleDownloaded}}</font> | 已跳过：<font color=\"info\">{{.SubtitleSkipped}}</font>\n\n## 📂 路径信息\n**源路径**：`{{.SourcePath}}`\n**目标路径**：`{{.TargetPath}}`",
+				string(ChannelTypeEmail):      defaultTextCompleteTemplate,
+				TemplateKeyEmailHTML:          "<h2>任务完成通知 ✅</h2><p><b>任务名称</b>：{{.TaskName}}<br><b>完成时间</b>：{{.EventTime}}<br><b>处理耗时</b>：{{.Duration}} 秒</p><table border=\"1\" cellpadding=\"6\" cellspacing=\"0\"><tr><th>类型</th><th>已处理</th><th>已跳过</th></tr><tr><td>STRM 文件</td><td>{{.GeneratedFile}}</td><td>{{.SkipFile}}</td></tr><tr><td>元数据</td><td>{{.MetadataDownloaded}}</td><td>{{.MetadataSkipped}}</td></tr><tr><td>字幕</td><td>{{.SubtitleDownloaded}}</td><td>{{.SubtitleSkipped}}</td></tr></table><p>失败：{{.FailedCount}}</p><p><b>源路径</b>：<code>{{.SourcePath}}</code><br><b>目标路径</b>：<code>{{.TargetPath}}</code></p>",
+				string(ChannelTypeDiscord):    defaultMarkdownCompleteTemplate,
+				string(ChannelTypeSlack):      defaultSlackCompleteTemplate,
+				string(ChannelTypeDingtalk):   defaultMarkdownCompleteTemplate,
+				string(ChannelTypeFeishu):     defaultMarkdownCompleteTemplate,
+				string(ChannelTypeBark):       defaultTextCompleteTemplate,
+				string(ChannelTypeNtfy):       defaultMarkdownCompleteTemplate,
+				string(ChannelTypeGotify):     defaultMarkdownCompleteTemplate,
+				string(ChannelTypeServerChan): defaultMarkdownCompleteTemplate,
+				string(ChannelTypePushPlus):   defaultMarkdownCompleteTemplate,
 			},
 			string(TemplateTypeTaskFailed): {
-				Telegram: "❌ *任务失败通知*\n\n📂 任务：`{{.TaskName}}`\n⏰ 时间：{{.EventTime}}\n⏱️ 耗时：{{.Duration}}秒\n❗ 错误信息：\n`{{.ErrorMessage}}`",
-				Wework:   "❌ *任务失败通知*\n\n📂 任务：`{{.TaskName}}`\n⏰ 时间：{{.EventTime}}\n⏱️ 耗时：{{.Duration}}秒\n❗ 错误信息：\n`{{.ErrorMessage}}`",
+				string(ChannelTypeTelegram):   "❌ *任务失败通知*\n\n📂 任务：`{{.TaskName}}`\n⏰ 时间：{{.EventTime}}\n⏱️ 耗时：{{.Duration}}秒\n❗ 错误信息：\n`{{.ErrorMessage}}`",
+				string(ChannelTypeWework):     "❌ *任务失败通知*\n\n📂 任务：`{{.TaskName}}`\n⏰ 时间：{{.EventTime}}\n⏱️ 耗时：{{.Duration}}秒\n❗ 错误信息：\n`{{.ErrorMessage}}`",
+				string(ChannelTypeEmail):      defaultTextFailedTemplate,
+				TemplateKeyEmailHTML:          "<h2>任务失败通知 ❌</h2><p><b>任务名称</b>：{{.TaskName}}<br><b>时间</b>：{{.EventTime}}<br><b>耗时</b>：{{.Duration}} 秒</p><p><b>错误信息</b>：</p><pre>{{.ErrorMessage}}</pre>",
+				string(ChannelTypeDiscord):    defaultMarkdownFailedTemplate,
+				string(ChannelTypeSlack):      defaultSlackFailedTemplate,
+				string(ChannelTypeDingtalk):   defaultMarkdownFailedTemplate,
+				string(ChannelTypeFeishu):     defaultMarkdownFailedTemplate,
+				string(ChannelTypeBark):       defaultTextFailedTemplate,
+				string(ChannelTypeNtfy):       defaultMarkdownFailedTemplate,
+				string(ChannelTypeGotify):     defaultMarkdownFailedTemplate,
+				string(ChannelTypeServerChan): defaultMarkdownFailedTemplate,
+				string(ChannelTypePushPlus):   defaultMarkdownFailedTemplate,
+			},
+			string(TemplateTypeNewMedia): {
+				string(ChannelTypeTelegram):   "🎬 *新增媒体通知*\n\n📂 任务：`{{.TaskName}}`\n⏰ 时间：{{.EventTime}}\n🆕 新增：{{.NewMediaCount}} 个\n{{range .NewMedia}}\n*{{.Name}}*\n{{range .Items}}• `{{.}}`\n{{end}}{{if .More}}• …… 等 {{.More}} 个\n{{end}}{{end}}{{if .NewMediaOmitted}}\n另有 {{.NewMediaOmitted}} 个未列出{{end}}",
+				string(ChannelTypeWework):     "🎬 新增媒体通知\n\n**任务名称**：<font color=\"info\">`{{.TaskName}}`</font>\n**时间**：{{.EventTime}}\n**新增**：<font color=\"info\">{{.NewMediaCount}}</font> 个\n{{range .NewMedia}}\n**{{.Name}}**\n{{range .Items}}> {{.}}\n{{end}}{{if .More}}> …… 等 {{.More}} 个\n{{end}}{{end}}{{if .NewMediaOmitted}}\n另有 {{.NewMediaOmitted}} 个未列出{{end}}",
+				TemplateKeyWeworkNews:         defaultTextNewMediaTemplate,
+				string(ChannelTypeEmail):      defaultTextNewMediaTemplate,
+				TemplateKeyEmailHTML:          "<h2>新增媒体通知 🎬</h2><p><b>任务名称</b>：{{.TaskName}}<br><b>时间</b>：{{.EventTime}}<br><b>新增</b>：{{.NewMediaCount}} 个</p>{{range .NewMedia}}<h3>{{.Name}}</h3><ul>{{range .Items}}<li>{{.}}</li>{{end}}{{if .More}}<li>…… 等 {{.More}} 个</li>{{end}}</ul>{{end}}{{if .NewMediaOmitted}}<p>另有 {{.NewMediaOmitted}} 个未列出</p>{{end}}",
+				string(ChannelTypeDiscord):    defaultMarkdownNewMediaTemplate,
+				string(ChannelTypeSlack):      defaultSlackNewMediaTemplate,
+				string(ChannelTypeDingtalk):   defaultMarkdownNewMediaTemplate,
+				string(ChannelTypeFeishu):     defaultMarkdownNewMediaTemplate,
+				string(ChannelTypeBark):       defaultTextNewMediaTemplate,
+				string(ChannelTypeNtfy):       defaultMarkdownNewMediaTemplate,
+				string(ChannelTypeGotify):     defaultMarkdownNewMediaTemplate,
+				string(ChannelTypeServerChan): defaultMarkdownNewMediaTemplate,
+				string(ChannelTypePushPlus):   defaultMarkdownNewMediaTemplate,
+			},
+			string(TemplateTypeDigest): {
+				string(ChannelTypeTelegram):   "📊 *通知摘要*\n\n*统计周期*：{{.Digest.PeriodStart}} ~ {{.Digest.PeriodEnd}}\n*执行次数*：{{.Digest.TotalRuns}}（成功 {{.Digest.CompletedRuns}}，失败 {{.Digest.FailedRuns}}）\n*新增 STRM*：{{.Digest.GeneratedFile}}，*失败文件*：{{.Digest.FailedFiles}}\n{{if .Digest.Tasks}}\n*任务汇总*\n{{range .Digest.Tasks}}• {{.TaskName}}：执行 {{.Runs}} 次（成功 {{.Completed}}，失败 {{.Failed}}），生成 {{.GeneratedFile}}，失败文件 {{.FailedCount}}\n{{end}}{{end}}{{if .Digest.Failures}}\n*失败记录*\n{{range .Digest.Failures}}• {{.Time}} {{.TaskName}}：{{.Message}}\n{{end}}{{if .Digest.FailuresOmitted}}• …… 另有 {{.Digest.FailuresOmitted}} 条\n{{end}}{{end}}{{if .NewMedia}}\n*新增媒体*：{{.NewMediaCount}} 个\n{{range .NewMedia}}\n*{{.Name}}*\n{{range .Items}}• {{.}}\n{{end}}{{if .More}}• …… 等 {{.More}} 个\n{{end}}{{end}}{{if .NewMediaOmitted}}\n另有 {{.NewMediaOmitted}} 个未列出{{end}}{{end}}",
+				string(ChannelTypeWework):     "📊 通知摘要\n\n**统计周期**：{{.Digest.PeriodStart}} ~ {{.Digest.PeriodEnd}}\n**执行次数**：{{.Digest.TotalRuns}}（成功 {{.Digest.CompletedRuns}}，失败 {{.Digest.FailedRuns}}）\n**新增 STRM**：{{.Digest.GeneratedFile}}，**失败文件**：{{.Digest.FailedFiles}}\n{{if .Digest.Tasks}}\n**任务汇总**\n{{range .Digest.Tasks}}- {{.TaskName}}：执行 {{.Runs}} 次（成功 {{.Completed}}，失败 {{.Failed}}），生成 {{.GeneratedFile}}，失败文件 {{.FailedCount}}\n{{end}}{{end}}{{if .Digest.Failures}}\n**失败记录**\n{{range .Digest.Failures}}- {{.Time}} {{.TaskName}}：{{.Message}}\n{{end}}{{if .Digest.FailuresOmitted}}- …… 另有 {{.Digest.FailuresOmitted}} 条\n{{end}}{{end}}{{if .NewMedia}}\n**新增媒体**：{{.NewMediaCount}} 个\n{{range .NewMedia}}\n**{{.Name}}**\n{{range .Items}}- {{.}}\n{{end}}{{if .More}}- …… 等 {{.More}} 个\n{{end}}{{end}}{{if .NewMediaOmitted}}\n另有 {{.NewMediaOmitted}} 个未列出{{end}}{{end}}",
+				string(ChannelTypeEmail):      defaultTextDigestTemplate,
+				TemplateKeyEmailHTML:          "<h2>通知摘要 📊</h2><p><b>统计周期</b>：{{.Digest.PeriodStart}} ~ {{.Digest.PeriodEnd}}<br><b>执行次数</b>：{{.Digest.TotalRuns}}（成功 {{.Digest.CompletedRuns}}，失败 {{.Digest.FailedRuns}}）<br><b>新增 STRM</b>：{{.Digest.GeneratedFile}}，<b>失败文件</b>：{{.Digest.FailedFiles}}</p>{{if .Digest.Tasks}}<table border=\"1\" cellpadding=\"6\" cellspacing=\"0\"><tr><th>任务</th><th>执行</th><th>成功</th><th>失败</th><th>生成</th><th>失败文件</th></tr>{{range .Digest.Tasks}}<tr><td>{{.TaskName}}</td><td>{{.Runs}}</td><td>{{.Completed}}</td><td>{{.Failed}}</td><td>{{.GeneratedFile}}</td><td>{{.FailedCount}}</td></tr>{{end}}</table>{{end}}{{if .Digest.Failures}}<h3>失败记录</h3><ul>{{range .Digest.Failures}}<li>{{.Time}} {{.TaskName}}：{{.Message}}</li>{{end}}{{if .Digest.FailuresOmitted}}<li>…… 另有 {{.Digest.FailuresOmitted}} 条</li>{{end}}</ul>{{end}}{{if .NewMedia}}<h3>新增媒体：{{.NewMediaCount}} 个</h3>{{range .NewMedia}}<h4>{{.Name}}</h4><ul>{{range .Items}}<li>{{.}}</li>{{end}}{{if .More}}<li>…… 等 {{.More}} 个</li>{{end}}</ul>{{end}}{{if .NewMediaOmitted}}<p>另有 {{.NewMediaOmitted}} 个未列出</p>{{end}}{{end}}",
+				string(ChannelTypeDiscord):    defaultMarkdownDigestTemplate,
+				string(ChannelTypeSlack):      defaultSlackDigestTemplate,
+				string(ChannelTypeDingtalk):   defaultMarkdownDigestTemplate,
+				string(ChannelTypeFeishu):     defaultMarkdownDigestTemplate,
+				string(ChannelTypeBark):       defaultTextDigestTemplate,
+				string(ChannelTypeNtfy):       defaultMarkdownDigestTemplate,
+				string(ChannelTypeGotify):     defaultMarkdownDigestTemplate,
+				string(ChannelTypeServerChan): defaultMarkdownDigestTemplate,
+				string(ChannelTypePushPlus):   defaultMarkdownDigestTemplate,
+			},
+			string(TemplateTypeSourceDown): {
+				string(ChannelTypeTelegram):   "🚨 *源站异常告警*\n\n🔌 源站：`{{.Health.SourceName}}`\n⏰ 时间：{{.EventTime}}\n🔁 连续失败：{{.Health.Failures}} 次\n❗ 错误信息：\n```\n{{.Health.Message}}\n```{{if .Health.AffectedTasks}}\n\n📋 *受影响的任务*\n{{range .Health.AffectedTasks}}• {{.}}\n{{end}}{{end}}",
+				string(ChannelTypeWework):     "🚨 源站异常告警\n\n**源站**：<font color=\"warning\">{{.Health.SourceName}}</font>\n**时间**：{{.EventTime}}\n**连续失败**：{{.Health.Failures}} 次\n**错误信息**：<font color=\"warning\">{{.Health.Message}}</font>{{if .Health.AffectedTasks}}\n\n**受影响的任务**\n{{range .Health.AffectedTasks}}- {{.}}\n{{end}}{{end}}",
+				string(ChannelTypeEmail):      defaultTextSourceDownTemplate,
+				TemplateKeyEmailHTML:          "<h2>源站异常告警 🚨</h2><p><b>源站</b>：{{.Health.SourceName}}<br><b>时间</b>：{{.EventTime}}<br><b>连续失败</b>：{{.Health.Failures}} 次</p><pre>{{.Health.Message}}</pre>{{if .Health.AffectedTasks}}<h3>受影响的任务</h3><ul>{{range .Health.AffectedTasks}}<li>{{.}}</li>{{end}}</ul>{{end}}",
+				string(ChannelTypeDiscord):    defaultMarkdownSourceDownTemplate,
+				string(ChannelTypeSlack):      defaultSlackSourceDownTemplate,
+				string(ChannelTypeDingtalk):   defaultMarkdownSourceDownTemplate,
+				string(ChannelTypeFeishu):     defaultMarkdownSourceDownTemplate,
+				string(ChannelTypeBark):       defaultTextSourceDownTemplate,
+				string(ChannelTypeNtfy):       defaultMarkdownSourceDownTemplate,
+				string(ChannelTypeGotify):     defaultMarkdownSourceDownTemplate,
+				string(ChannelTypeServerChan): defaultMarkdownSourceDownTemplate,
+				string(ChannelTypePushPlus):   defaultMarkdownSourceDownTemplate,
+			},
+			string(TemplateTypeSourceRecovered): {
+				string(ChannelTypeTelegram):   "✅ *源站已恢复*\n\n🔌 源站：`{{.Health.SourceName}}`\n⏰ 恢复时间：{{.EventTime}}\n⏱ 异常持续：{{.Health.DownDuration}}\n📶 响应耗时：{{.Health.LatencyMs}} ms",
+				string(ChannelTypeWework):     "✅ 源站已恢复\n\n**源站**：<font color=\"info\">{{.Health.SourceName}}</font>\n**恢复时间**：{{.EventTime}}\n**异常持续**：{{.Health.DownDuration}}\n**响应耗时**：{{.Health.LatencyMs}} ms",
+				string(ChannelTypeEmail):      defaultTextSourceRecoveredTemplate,
+				TemplateKeyEmailHTML:          "<h2>源站已恢复 ✅</h2><p><b>源站</b>：{{.Health.SourceName}}<br><b>恢复时间</b>：{{.EventTime}}<br><b>异常持续</b>：{{.Health.DownDuration}}<br><b>响应耗时</b>：{{.Health.LatencyMs}} ms</p>",
+				string(ChannelTypeDiscord):    defaultMarkdownSourceRecoveredTemplate,
+				string(ChannelTypeSlack):      defaultSlackSourceRecoveredTemplate,
+				string(ChannelTypeDingtalk):   defaultMarkdownSourceRecoveredTemplate,
+				string(ChannelTypeFeishu):     defaultMarkdownSourceRecoveredTemplate,
+				string(ChannelTypeBark):       defaultTextSourceRecoveredTemplate,
+				string(ChannelTypeNtfy):       defaultMarkdownSourceRecoveredTemplate,
+				string(ChannelTypeGotify):     defaultMarkdownSourceRecoveredTemplate,
+				string(ChannelTypeServerChan): defaultMarkdownSourceRecoveredTemplate,
+				string(ChannelTypePushPlus):   defaultMarkdownSourceRecoveredTemplate,
 			},
 		},
 		QueueSettings: QueueSettings{
@@ -90,5 +417,16 @@ func DefaultSettings() *Settings {
 			RetryInterval: 60,
 			Concurrency:   1,
 		},
+		NewMedia: NewMediaSettings{
+			MaxGroups:        DefaultNewMediaMaxGroups,
+			MaxItemsPerGroup: DefaultNewMediaMaxItemsPerGroup,
+			IncludePoster:    true,
+		},
+		Digest: DigestSettings{
+			Enabled:   false,
+			Frequency: DigestFrequencyDaily,
+			Time:      "09:00",
+			Weekday:   1,
+		},
 	}
 }
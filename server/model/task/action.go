@@ -0,0 +1,98 @@
+package task
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// 任务完成后的后续动作类型
+const (
+	// ActionTypeTask 触发另一个任务
+	ActionTypeTask = "task"
+	// ActionTypeEmbyRefresh 刷新 Emby 媒体库，未指定媒体库时刷新全部
+	ActionTypeEmbyRefresh = "embyRefresh"
+	// ActionTypeWebhook 调用外部 Webhook
+	ActionTypeWebhook = "webhook"
+	// ActionTypeCommand 执行 Shell 命令
+	ActionTypeCommand = "command"
+)
+
+// 后续动作的执行条件
+const (
+	// ActionOnSuccess 仅在任务执行成功时执行（默认）
+	ActionOnSuccess = "success"
+	// ActionOnFailed 仅在任务执行失败时执行
+	ActionOnFailed = "failed"
+	// ActionOnAlways 无论成功或失败都执行，任务被取消时不执行
+	ActionOnAlways = "always"
+)
+
+// PostAction 任务完成后的后续动作
+type PostAction struct {
+	Type         string            `json:"type"`                   // 动作类型：task / embyRefresh / webhook / command
+	On           string            `json:"on,omitempty"`           // 执行条件：success / failed / always，默认 success
+	MinGenerated int               `json:"minGenerated,omitempty"` // 本次生成的 STRM 文件数达到该值才执行
+	Scoped       bool              `json:"scoped,omitempty"`       // 局部扫描结束后也执行，默认仅在完整扫描后执行
+	TaskID       uint              `json:"taskId,omitempty"`       // 触发的任务ID（task）
+	LibraryIDs   []string          `json:"libraryIds,omitempty"`   // 刷新的媒体库ID，为空时刷新全部（embyRefresh）
+	URL          string            `json:"url,omitempty"`          // 请求地址（webhook）
+	Method       string            `json:"method,omitempty"`       // 请求方法，默认 POST（webhook）
+	Headers      map[string]string `json:"headers,omitempty"`      // 自定义请求头（webhook）
+	Command      string            `json:"command,omitempty"`      // 执行的命令（command）
+	Timeout      int               `json:"timeout,omitempty"`      // 超时时间，秒（webhook / command）
+}
+
+// PostActions 后续动作列表，以 JSON 形式保存在任务表中
+type PostActions []PostAction
+
+// Value 实现 driver.Valuer
+func (a PostActions) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 sql.Scanner
+func (a *PostActions) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("无法解析后续动作: %T", value)
+	}
+	if len(data) == 0 {
+		*a = nil
+		return nil
+	}
+	return json.Unmarshal(data, a)
+}
+
+// IsValidActionType 判断后续动作类型是否有效
+func IsValidActionType(actionType string) bool {
+	switch actionType {
+	case ActionTypeTask, ActionTypeEmbyRefresh, ActionTypeWebhook, ActionTypeCommand:
+		return true
+	}
+	return false
+}
+
+// IsValidActionOn 判断后续动作的执行条件是否有效
+func IsValidActionOn(on string) bool {
+	switch on {
+	case "", ActionOnSuccess, ActionOnFailed, ActionOnAlways:
+		return true
+	}
+	return false
+}
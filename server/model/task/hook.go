@@ -0,0 +1,135 @@
+package task
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// 钩子触发点
+const (
+	// HookBeforeTask 任务开始扫描前
+	HookBeforeTask = "beforeTask"
+	// HookAfterTask 任务执行结束后（发送通知前）
+	HookAfterTask = "afterTask"
+	// HookBeforeStrm 写入每个 STRM 文件前
+	HookBeforeStrm = "beforeStrm"
+	// HookAfterStrm 写入每个 STRM 文件后
+	HookAfterStrm = "afterStrm"
+	// HookBeforeDownload 下载每个元数据或字幕文件前
+	HookBeforeDownload = "beforeDownload"
+	// HookAfterDownload 下载每个元数据或字幕文件后
+	HookAfterDownload = "afterDownload"
+)
+
+// 钩子执行失败时的处理策略
+const (
+	// HookFailureIgnore 忽略失败，仅记录输出（默认）
+	HookFailureIgnore = "ignore"
+	// HookFailureFailFile 将当前文件标记为处理失败，仅适用于文件级钩子
+	HookFailureFailFile = "failFile"
+	// HookFailureFailTask 终止本次任务执行并标记为失败
+	HookFailureFailTask = "failTask"
+)
+
+// Hook 钩子命令配置
+type Hook struct {
+	Command   string `json:"command"`             // 执行的命令
+	Timeout   int    `json:"timeout,omitempty"`   // 超时时间，秒，为 0 时使用全局默认值
+	OnFailure string `json:"onFailure,omitempty"` // 失败处理策略：ignore / failFile / failTask，默认 ignore
+}
+
+// TaskHooks 任务各触发点的钩子命令，以 JSON 形式保存在任务表中
+type TaskHooks struct {
+	BeforeTask     *Hook `json:"beforeTask,omitempty"`
+	AfterTask      *Hook `json:"afterTask,omitempty"`
+	BeforeStrm     *Hook `json:"beforeStrm,omitempty"`
+	AfterStrm      *Hook `json:"afterStrm,omitempty"`
+	BeforeDownload *Hook `json:"beforeDownload,omitempty"`
+	AfterDownload  *Hook `json:"afterDownload,omitempty"`
+}
+
+// Get 获取指定触发点的钩子，未配置或命令为空时返回 nil
+func (h TaskHooks) Get(point string) *Hook {
+	var hook *Hook
+	switch point {
+	case HookBeforeTask:
+		hook = h.BeforeTask
+	case HookAfterTask:
+		hook = h.AfterTask
+	case HookBeforeStrm:
+		hook = h.BeforeStrm
+	case HookAfterStrm:
+		hook = h.AfterStrm
+	case HookBeforeDownload:
+		hook = h.BeforeDownload
+	case HookAfterDownload:
+		hook = h.AfterDownload
+	}
+	if hook == nil || hook.Command == "" {
+		return nil
+	}
+	return hook
+}
+
+// IsEmpty 是否未配置任何钩子
+func (h TaskHooks) IsEmpty() bool {
+	for _, point := range HookPoints {
+		if h.Get(point) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// HookPoints 全部钩子触发点
+var HookPoints = []string{HookBeforeTask, HookAfterTask, HookBeforeStrm, HookAfterStrm, HookBeforeDownload, HookAfterDownload}
+
+// IsFileHookPoint 判断是否为文件级触发点
+func IsFileHookPoint(point string) bool {
+	return point != HookBeforeTask && point != HookAfterTask
+}
+
+// IsValidHookFailure 判断钩子失败处理策略是否适用于该触发点
+func IsValidHookFailure(point, onFailure string) bool {
+	switch onFailure {
+	case "", HookFailureIgnore, HookFailureFailTask:
+		return true
+	case HookFailureFailFile:
+		return IsFileHookPoint(point)
+	}
+	return false
+}
+
+// Value 实现 driver.Valuer
+func (h TaskHooks) Value() (driver.Value, error) {
+	if h.IsEmpty() {
+		return "", nil
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 sql.Scanner
+func (h *TaskHooks) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*h = TaskHooks{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("无法解析钩子配置: %T", value)
+	}
+	*h = TaskHooks{}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, h)
+}
@@ -0,0 +1,23 @@
+package task
+
+// 错过定时执行（如服务停机期间）后的补偿策略
+const (
+	// MisfirePolicySkip 不补偿，等待下一次调度
+	MisfirePolicySkip = "skip"
+	// MisfirePolicyOnce 无论错过多少次，启动后只补偿执行一次
+	MisfirePolicyOnce = "once"
+	// MisfirePolicyLimit 按错过的次数补偿执行，最多 MisfireLimit 次
+	MisfirePolicyLimit = "limit"
+)
+
+// MaxMisfireLimit 补偿执行次数的上限
+const MaxMisfireLimit = 100
+
+// IsValidMisfirePolicy 判断补偿策略是否有效
+func IsValidMisfirePolicy(policy string) bool {
+	switch policy {
+	case MisfirePolicySkip, MisfirePolicyOnce, MisfirePolicyLimit:
+		return true
+	}
+	return false
+}
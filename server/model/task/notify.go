@@ -0,0 +1,56 @@
+package task
+
+import "strings"
+
+// 任务通知事件
+const (
+	// NotifyEventCompleted 任务执行成功（包含以下三种成功情况）
+	NotifyEventCompleted = "completed"
+	// NotifyEventFailed 任务执行失败
+	NotifyEventFailed = "failed"
+	// NotifyEventPartialFailed 任务执行成功但存在处理失败的文件
+	NotifyEventPartialFailed = "partialFailed"
+	// NotifyEventNoNew 任务执行成功但没有生成新文件
+	NotifyEventNoNew = "noNew"
+	// NotifyEventNewMedia 任务执行成功且生成了新文件
+	NotifyEventNewMedia = "newMedia"
+	// NotifyEventNone 不发送任何通知
+	NotifyEventNone = "none"
+)
+
+// DefaultNotifyEvents 默认通知事件，与引入通知规则前的行为保持一致
+const DefaultNotifyEvents = NotifyEventCompleted + "," + NotifyEventFailed
+
+// IsValidNotifyEvent 判断通知事件是否有效
+func IsValidNotifyEvent(event string) bool {
+	switch event {
+	case NotifyEventCompleted, NotifyEventFailed, NotifyEventPartialFailed, NotifyEventNoNew, NotifyEventNewMedia, NotifyEventNone:
+		return true
+	}
+	return false
+}
+
+// NotifyEventList 获取任务配置的通知事件列表
+func (t *Task) NotifyEventList() []string {
+	return splitNotifyList(t.NotifyEvents)
+}
+
+// NotifyChannelList 获取任务配置的通知渠道列表，为空表示使用默认渠道
+func (t *Task) NotifyChannelList() []string {
+	return splitNotifyList(t.NotifyChannels)
+}
+
+// splitNotifyList 拆分逗号分隔的配置并去除空白与重复项
+func splitNotifyList(value string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	return items
+}
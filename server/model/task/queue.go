@@ -0,0 +1,81 @@
+package task
+
+import "time"
+
+// 任务触发来源
+const (
+	// TriggerCron 定时调度触发
+	TriggerCron = "cron"
+	// TriggerManual 在界面或机器人中手动执行
+	TriggerManual = "manual"
+	// TriggerWebhook 文件变更通知触发
+	TriggerWebhook = "webhook"
+	// TriggerAPI 通过接口提交，如局部扫描
+	TriggerAPI = "api"
+	// TriggerCatchUp 服务启动后补偿停机期间错过的定时执行
+	TriggerCatchUp = "catchup"
+	// TriggerChain 由其他任务完成后的后续动作触发
+	TriggerChain = "chain"
+	// TriggerRetry 重试某次运行中的失败文件
+	TriggerRetry = "retry"
+)
+
+// 各触发来源的队列优先级，手动执行和文件变更触发排在定时调度之前
+const (
+	PriorityCron    = 0
+	PriorityAPI     = 10
+	PriorityManual  = 20
+	PriorityWebhook = 20
+)
+
+// TriggerPriority 获取触发来源对应的队列优先级
+func TriggerPriority(trigger string) int {
+	switch trigger {
+	case TriggerManual, TriggerRetry:
+		return PriorityManual
+	case TriggerWebhook:
+		return PriorityWebhook
+	case TriggerAPI, TriggerChain:
+		return PriorityAPI
+	default:
+		return PriorityCron
+	}
+}
+
+// 队列条目类型
+const (
+	// QueueKindScan 完整扫描或局部扫描，会创建任务日志并执行通知和后续动作
+	QueueKindScan = "scan"
+	// QueueKindFile 处理文件变更通知中新增的单个文件或目录，不创建任务日志
+	QueueKindFile = "file"
+	// QueueKindRetry 重试某次运行中未解决的失败文件，结果记录到新的任务日志中
+	QueueKindRetry = "retry"
+)
+
+// 队列条目状态
+const (
+	QueueStatusPending = "pending"
+	QueueStatusRunning = "running"
+)
+
+// QueueEntry 任务执行队列条目，持久化到数据库，服务重启后继续执行
+type QueueEntry struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time  `json:"enqueuedAt"` // 加入队列的时间
+	UpdatedAt time.Time  `json:"updatedAt"`
+	TaskID    uint       `json:"taskId" gorm:"not null;index"`
+	Kind      string     `json:"kind" gorm:"not null;default:scan;type:varchar(20)"` // 条目类型：scan / file / retry
+	SubPath   string     `json:"subPath" gorm:"type:varchar(1024)"`                  // 局部扫描的子路径，为空表示完整扫描；file 类型为新增文件或目录的路径
+	IsDir     bool       `json:"isDir" gorm:"not null;default:false"`                // file 类型的路径是否为目录
+	TaskLogID uint       `json:"taskLogId" gorm:"not null;default:0"`                // retry 类型要重试的任务日志ID
+	Trigger   string     `json:"trigger" gorm:"not null;type:varchar(20)"`           // 触发来源：cron / manual / webhook / api / catchup / chain / retry
+	Priority  int        `json:"priority" gorm:"not null;default:0"`                 // 优先级，数值越大越先执行
+	Position  int64      `json:"position" gorm:"not null;default:0;index"`           // 同优先级内的排列顺序，数值越小越先执行
+	Status    string     `json:"status" gorm:"not null;index;type:varchar(20)"`      // pending / running
+	StartedAt *time.Time `json:"startedAt" gorm:"default:null"`
+}
+
+// TableName 表名
+func (QueueEntry) TableName() string {
+	return "task_queue"
+}
@@ -1,40 +1,65 @@
 package request
 
-import "github.com/MccRay-s/alist2strm/model/common/request"
+import (
+	"github.com/MccRay-s/alist2strm/model/common/request"
+	"github.com/MccRay-s/alist2strm/model/task"
+)
 
 // TaskCreateReq 任务创建请求
 type TaskCreateReq struct {
-	Name               string `json:"name" binding:"required" validate:"required,min=1,max=100" example:"任务名称"`
-	MediaType          string `json:"mediaType" binding:"required" validate:"required,oneof=movie tv" example:"movie"`
-	ConfigType         string `json:"configType" binding:"required" validate:"required,oneof=alist clouddrive local" example:"alist"`
-	SourcePath         string `json:"sourcePath" binding:"required" validate:"required" example:"源路径"`
-	TargetPath         string `json:"targetPath" binding:"required" validate:"required" example:"目标路径"`
-	FileSuffix         string `json:"fileSuffix" binding:"required" validate:"required" example:"文件后缀"`
-	Overwrite          bool   `json:"overwrite" example:"是否覆盖"`
-	Enabled            bool   `json:"enabled" example:"是否启用"`
-	Cron               string `json:"cron" validate:"omitempty" example:"定时任务表达式"`
-	DownloadMetadata   bool   `json:"downloadMetadata" example:"是否下载刮削数据"`
-	DownloadSubtitle   bool   `json:"downloadSubtitle" example:"是否下载字幕"`
-	MetadataExtensions string `json:"metadataExtensions" example:"刮削数据文件扩展名"`
-	SubtitleExtensions string `json:"subtitleExtensions" example:"字幕文件扩展名"`
+	Name               string           `json:"name" binding:"required" validate:"required,min=1,max=100" example:"任务名称"`
+	MediaType          string           `json:"mediaType" binding:"required" validate:"required,oneof=movie tv" example:"movie"`
+	ConfigType         string           `json:"configType" binding:"required" validate:"required,oneof=alist clouddrive local" example:"alist"`
+	SourcePath         string           `json:"sourcePath" binding:"required" validate:"required" example:"源路径"`
+	TargetPath         string           `json:"targetPath" binding:"required" validate:"required" example:"目标路径"`
+	FileSuffix         string           `json:"fileSuffix" binding:"required" validate:"required" example:"文件后缀"`
+	Overwrite          bool             `json:"overwrite" example:"是否覆盖"`
+	Enabled            bool             `json:"enabled" example:"是否启用"`
+	Cron               string           `json:"cron" validate:"omitempty" example:"定时任务表达式"`
+	DownloadMetadata   bool             `json:"downloadMetadata" example:"是否下载刮削数据"`
+	DownloadSubtitle   bool             `json:"downloadSubtitle" example:"是否下载字幕"`
+	MetadataExtensions string           `json:"metadataExtensions" example:"刮削数据文件扩展名"`
+	SubtitleExtensions string           `json:"subtitleExtensions" example:"字幕文件扩展名"`
+	NotifyEvents       string           `json:"notifyEvents" example:"failed,newMedia"`     // 需要通知的事件，为空时默认 completed,failed
+	NotifyChannels     string           `json:"notifyChannels" example:"telegram,bark"`     // 通知渠道，为空时使用默认渠道
+	NotifyMinGenerated int              `json:"notifyMinGenerated" example:"1" minimum:"0"` // 成功运行时生成文件数达到该值才通知
+	RunOnlyIfIdle      bool             `json:"runOnlyIfIdle" example:"false"`              // 定时触发时仅在执行队列空闲时执行
+	Timezone           string           `json:"timezone" example:"Asia/Shanghai"`           // 定时调度使用的时区，为空时使用全局设置
+	JitterSeconds      int              `json:"jitterSeconds" example:"300" minimum:"0"`    // 触发时随机延迟的最大秒数，为 0 时使用全局设置
+	BlackoutWindows    string           `json:"blackoutWindows" example:"19:00-23:00"`      // 禁止执行时段，多个时段用逗号分隔
+	MisfirePolicy      string           `json:"misfirePolicy" example:"once"`               // 错过定时执行后的补偿策略：skip / once / limit，默认 skip
+	MisfireLimit       int              `json:"misfireLimit" example:"3" minimum:"0"`       // 补偿策略为 limit 时最多补偿执行的次数
+	PostActions        task.PostActions `json:"postActions"`                                // 任务完成后的后续动作
+	Hooks              task.TaskHooks   `json:"hooks"`                                      // 钩子命令
 }
 
 // TaskUpdateReq 任务更新请求
 type TaskUpdateReq struct {
-	ID                 uint   `json:"-"` // 通过路径参数传递，不参与JSON绑定和验证
-	Name               string `json:"name,omitempty" validate:"omitempty,min=1,max=100" example:"任务名称"`
-	MediaType          string `json:"mediaType,omitempty" validate:"omitempty,oneof=movie tv" example:"movie"`
-	ConfigType         string `json:"configType" validate:"required,oneof=alist clouddrive local" example:"alist"`
-	SourcePath         string `json:"sourcePath,omitempty" example:"源路径"`
-	TargetPath         string `json:"targetPath,omitempty" example:"目标路径"`
-	FileSuffix         string `json:"fileSuffix,omitempty" example:"文件后缀"`
-	Overwrite          *bool  `json:"overwrite,omitempty" example:"是否覆盖"`
-	Enabled            *bool  `json:"enabled,omitempty" example:"是否启用"`
-	Cron               string `json:"cron,omitempty" example:"定时任务表达式"`
-	DownloadMetadata   *bool  `json:"downloadMetadata,omitempty" example:"是否下载刮削数据"`
-	DownloadSubtitle   *bool  `json:"downloadSubtitle,omitempty" example:"是否下载字幕"`
-	MetadataExtensions string `json:"metadataExtensions,omitempty" example:"刮削数据文件扩展名"`
-	SubtitleExtensions string `json:"subtitleExtensions,omitempty" example:"字幕文件扩展名"`
+	ID                 uint              `json:"-"` // 通过路径参数传递，不参与JSON绑定和验证
+	Name               string            `json:"name,omitempty" validate:"omitempty,min=1,max=100" example:"任务名称"`
+	MediaType          string            `json:"mediaType,omitempty" validate:"omitempty,oneof=movie tv" example:"movie"`
+	ConfigType         string            `json:"configType" validate:"required,oneof=alist clouddrive local" example:"alist"`
+	SourcePath         string            `json:"sourcePath,omitempty" example:"源路径"`
+	TargetPath         string            `json:"targetPath,omitempty" example:"目标路径"`
+	FileSuffix         string            `json:"fileSuffix,omitempty" example:"文件后缀"`
+	Overwrite          *bool             `json:"overwrite,omitempty" example:"是否覆盖"`
+	Enabled            *bool             `json:"enabled,omitempty" example:"是否启用"`
+	Cron               string            `json:"cron,omitempty" example:"定时任务表达式"`
+	DownloadMetadata   *bool             `json:"downloadMetadata,omitempty" example:"是否下载刮削数据"`
+	DownloadSubtitle   *bool             `json:"downloadSubtitle,omitempty" example:"是否下载字幕"`
+	MetadataExtensions string            `json:"metadataExtensions,omitempty" example:"刮削数据文件扩展名"`
+	SubtitleExtensions string            `json:"subtitleExtensions,omitempty" example:"字幕文件扩展名"`
+	NotifyEvents       string            `json:"notifyEvents,omitempty" example:"failed,newMedia"`     // 需要通知的事件，none 表示不通知
+	NotifyChannels     *string           `json:"notifyChannels,omitempty" example:"telegram,bark"`     // 通知渠道，传空字符串恢复为默认渠道
+	NotifyMinGenerated *int              `json:"notifyMinGenerated,omitempty" example:"1" minimum:"0"` // 成功运行时生成文件数达到该值才通知
+	RunOnlyIfIdle      *bool             `json:"runOnlyIfIdle,omitempty" example:"false"`              // 定时触发时仅在执行队列空闲时执行
+	Timezone           *string           `json:"timezone,omitempty" example:"Asia/Shanghai"`           // 定时调度使用的时区，传空字符串恢复为全局设置
+	JitterSeconds      *int              `json:"jitterSeconds,omitempty" example:"300" minimum:"0"`    // 触发时随机延迟的最大秒数，为 0 时使用全局设置
+	BlackoutWindows    *string           `json:"blackoutWindows,omitempty" example:"19:00-23:00"`      // 禁止执行时段，传空字符串清除
+	MisfirePolicy      string            `json:"misfirePolicy,omitempty" example:"once"`               // 错过定时执行后的补偿策略：skip / once / limit
+	MisfireLimit       *int              `json:"misfireLimit,omitempty" example:"3" minimum:"0"`       // 补偿策略为 limit 时最多补偿执行的次数
+	PostActions        *task.PostActions `json:"postActions,omitempty"`                                // 任务完成后的后续动作，传空数组清除
+	Hooks              *task.TaskHooks   `json:"hooks,omitempty"`                                      // 钩子命令，传空对象清除
 }
 
 // TaskInfoReq 任务信息查询请求
@@ -69,3 +94,23 @@ type TaskExecuteReq struct {
 type TaskStatusReq struct {
 	request.GetById
 }
+
+// TaskRescanReq 局部重新扫描请求
+type TaskRescanReq struct {
+	TaskID uint   `json:"taskId" example:"1"`                                            // 任务ID，为空时根据路径自动匹配任务
+	Path   string `json:"path" binding:"required" validate:"required" example:"/电视剧/某剧"` // 需要扫描的子路径，指定任务时可为相对任务源路径的路径
+}
+
+// TaskQueueReorderReq 调整执行队列顺序请求，按 IDs 的顺序重新排列等待中的条目
+type TaskQueueReorderReq struct {
+	IDs []uint `json:"ids" binding:"required,min=1"`
+}
+
+// TaskSchedulePreviewReq 调度预览请求
+type TaskSchedulePreviewReq struct {
+	Cron            string `json:"cron" binding:"required" example:"every 6h"`         // 调度表达式，支持 5/6 字段 Cron、CRON_TZ 前缀和 every 间隔
+	Timezone        string `json:"timezone" example:"Asia/Shanghai"`                   // 时区，为空时使用全局设置
+	JitterSeconds   int    `json:"jitterSeconds" example:"300"`                        // 随机延迟的最大秒数，为 0 时使用全局设置
+	BlackoutWindows string `json:"blackoutWindows" example:"19:00-23:00"`              // 任务的禁止执行时段，与全局时段共同生效
+	Count           int    `json:"count" binding:"omitempty,min=1,max=50" example:"5"` // 返回的触发次数，默认 5
+}
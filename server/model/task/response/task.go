@@ -1,27 +1,42 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/task"
+)
 
 // TaskInfo 任务信息响应
 type TaskInfo struct {
-	ID                 uint       `json:"id"`
-	ConfigType         string     `json:"configType"`
-	CreatedAt          time.Time  `json:"createdAt"`
-	UpdatedAt          time.Time  `json:"updatedAt"`
-	Name               string     `json:"name"`
-	MediaType          string     `json:"mediaType"`
-	SourcePath         string     `json:"sourcePath"`
-	TargetPath         string     `json:"targetPath"`
-	FileSuffix         string     `json:"fileSuffix"`
-	Overwrite          bool       `json:"overwrite"`
-	Enabled            bool       `json:"enabled"`
-	Cron               string     `json:"cron"`
-	Running            bool       `json:"running"`
-	LastRunAt          *time.Time `json:"lastRunAt"`
-	DownloadMetadata   bool       `json:"downloadMetadata"`
-	DownloadSubtitle   bool       `json:"downloadSubtitle"`
-	MetadataExtensions string     `json:"metadataExtensions"`
-	SubtitleExtensions string     `json:"subtitleExtensions"`
+	ID                 uint             `json:"id"`
+	ConfigType         string           `json:"configType"`
+	CreatedAt          time.Time        `json:"createdAt"`
+	UpdatedAt          time.Time        `json:"updatedAt"`
+	Name               string           `json:"name"`
+	MediaType          string           `json:"mediaType"`
+	SourcePath         string           `json:"sourcePath"`
+	TargetPath         string           `json:"targetPath"`
+	FileSuffix         string           `json:"fileSuffix"`
+	Overwrite          bool             `json:"overwrite"`
+	Enabled            bool             `json:"enabled"`
+	Cron               string           `json:"cron"`
+	Running            bool             `json:"running"`
+	LastRunAt          *time.Time       `json:"lastRunAt"`
+	DownloadMetadata   bool             `json:"downloadMetadata"`
+	DownloadSubtitle   bool             `json:"downloadSubtitle"`
+	MetadataExtensions string           `json:"metadataExtensions"`
+	SubtitleExtensions string           `json:"subtitleExtensions"`
+	NotifyEvents       string           `json:"notifyEvents"`
+	NotifyChannels     string           `json:"notifyChannels"`
+	NotifyMinGenerated int              `json:"notifyMinGenerated"`
+	RunOnlyIfIdle      bool             `json:"runOnlyIfIdle"`
+	Timezone           string           `json:"timezone"`
+	JitterSeconds      int              `json:"jitterSeconds"`
+	BlackoutWindows    string           `json:"blackoutWindows"`
+	MisfirePolicy      string           `json:"misfirePolicy"`
+	MisfireLimit       int              `json:"misfireLimit"`
+	PostActions        task.PostActions `json:"postActions"`
+	Hooks              task.TaskHooks   `json:"hooks"`
 }
 
 // TaskListResp 任务列表响应
@@ -36,6 +51,7 @@ type TaskListResp struct {
 type TaskExecuteResp struct {
 	TaskID         uint   `json:"taskId"`         // 任务ID
 	TaskName       string `json:"taskName"`       // 任务名称
+	TaskLogID      uint   `json:"taskLogId"`      // 本次执行创建的任务日志ID
 	IsSync         bool   `json:"isSync"`         // 是否同步执行
 	Status         string `json:"status"`         // 执行状态: running, completed, failed, skipped
 	StartTime      string `json:"startTime"`      // 开始时间
@@ -75,3 +91,45 @@ type TaskStatsResp struct {
 	SuccessCount    int64 `json:"successCount"`    // 成功执行次数
 	FailedCount     int64 `json:"failedCount"`     // 失败执行次数
 }
+
+// TaskRescanItem 单个任务的局部扫描提交结果
+type TaskRescanItem struct {
+	TaskID   uint   `json:"taskId"`   // 任务ID
+	TaskName string `json:"taskName"` // 任务名称
+	SubPath  string `json:"subPath"`  // 实际扫描的源路径
+	Status   string `json:"status"`   // 提交状态: queued, skipped
+	Message  string `json:"message"`  // 说明信息
+}
+
+// TaskRescanResp 局部重新扫描响应
+type TaskRescanResp struct {
+	Items []TaskRescanItem `json:"items"` // 各匹配任务的提交结果
+}
+
+// TaskQueueItem 执行队列条目
+type TaskQueueItem struct {
+	ID         uint       `json:"id"`
+	TaskID     uint       `json:"taskId"`
+	TaskName   string     `json:"taskName"`
+	Kind       string     `json:"kind"`
+	SubPath    string     `json:"subPath"`
+	TaskLogID  uint       `json:"taskLogId,omitempty"` // retry 类型要重试的任务日志ID
+	Trigger    string     `json:"trigger"`
+	Priority   int        `json:"priority"`
+	Status     string     `json:"status"`
+	EnqueuedAt time.Time  `json:"enqueuedAt"`
+	StartedAt  *time.Time `json:"startedAt"`
+}
+
+// TaskScheduleFireTime 调度预览中的一次触发
+type TaskScheduleFireTime struct {
+	FireTime   time.Time  `json:"fireTime"`
+	DeferredTo *time.Time `json:"deferredTo,omitempty"` // 处于禁止执行时段时推迟到的时间
+}
+
+// TaskSchedulePreviewResp 调度预览响应
+type TaskSchedulePreviewResp struct {
+	Timezone      string                 `json:"timezone"`
+	JitterSeconds int                    `json:"jitterSeconds"` // 每次触发会在该范围内随机延迟
+	Items         []TaskScheduleFireTime `json:"items"`
+}
@@ -16,24 +16,35 @@ type TaskStats struct {
 
 // Task 任务模型
 type Task struct {
-	ID                 uint       `json:"id" gorm:"primaryKey"`
-	CreatedAt          time.Time  `json:"createdAt"`
-	UpdatedAt          time.Time  `json:"updatedAt"`
-	Name               string     `json:"name" gorm:"type:VARCHAR(255);not null" validate:"required"`
-	MediaType          string     `json:"mediaType" gorm:"type:VARCHAR(50);not null;default:movie"`  // 媒体类型：movie/tv
-	ConfigType         string     `json:"configType" gorm:"type:VARCHAR(10);not null;default:alist"` // 配置类型：alist/cloudrive/local
-	SourcePath         string     `json:"sourcePath" gorm:"type:VARCHAR(255);not null" validate:"required"`
-	TargetPath         string     `json:"targetPath" gorm:"type:VARCHAR(255);not null" validate:"required"`
-	FileSuffix         string     `json:"fileSuffix" gorm:"type:VARCHAR(255);not null" validate:"required"`
-	Overwrite          bool       `json:"overwrite" gorm:"type:TINYINT(1);not null;default:0"`
-	Enabled            bool       `json:"enabled" gorm:"type:TINYINT(1);not null;default:1"`
-	Cron               string     `json:"cron" gorm:"type:VARCHAR(255)"`
-	Running            bool       `json:"running" gorm:"type:TINYINT(1);not null;default:0"`
-	LastRunAt          *time.Time `json:"lastRunAt"`
-	DownloadMetadata   bool       `json:"downloadMetadata" gorm:"type:TINYINT(1);not null;default:0"`      // 是否下载刮削数据
-	DownloadSubtitle   bool       `json:"downloadSubtitle" gorm:"type:TINYINT(1);not null;default:0"`      // 是否下载字幕
-	MetadataExtensions string     `json:"metadataExtensions" gorm:"type:VARCHAR(255);default:nfo,jpg,png"` // 刮削数据文件扩展名
-	SubtitleExtensions string     `json:"subtitleExtensions" gorm:"type:VARCHAR(255);default:srt,ass,ssa"` // 字幕文件扩展名
+	ID                 uint        `json:"id" gorm:"primaryKey"`
+	CreatedAt          time.Time   `json:"createdAt"`
+	UpdatedAt          time.Time   `json:"updatedAt"`
+	Name               string      `json:"name" gorm:"type:VARCHAR(255);not null" validate:"required"`
+	MediaType          string      `json:"mediaType" gorm:"type:VARCHAR(50);not null;default:movie"`  // 媒体类型：movie/tv
+	ConfigType         string      `json:"configType" gorm:"type:VARCHAR(10);not null;default:alist"` // 配置类型：alist/cloudrive/local
+	SourcePath         string      `json:"sourcePath" gorm:"type:VARCHAR(255);not null" validate:"required"`
+	TargetPath         string      `json:"targetPath" gorm:"type:VARCHAR(255);not null" validate:"required"`
+	FileSuffix         string      `json:"fileSuffix" gorm:"type:VARCHAR(255);not null" validate:"required"`
+	Overwrite          bool        `json:"overwrite" gorm:"type:TINYINT(1);not null;default:0"`
+	Enabled            bool        `json:"enabled" gorm:"type:TINYINT(1);not null;default:1"`
+	Cron               string      `json:"cron" gorm:"type:VARCHAR(255)"`
+	Running            bool        `json:"running" gorm:"type:TINYINT(1);not null;default:0"`
+	LastRunAt          *time.Time  `json:"lastRunAt"`                                                       // 最后一次完整扫描的开始时间，用于计算错过的定时执行
+	DownloadMetadata   bool        `json:"downloadMetadata" gorm:"type:TINYINT(1);not null;default:0"`      // 是否下载刮削数据
+	DownloadSubtitle   bool        `json:"downloadSubtitle" gorm:"type:TINYINT(1);not null;default:0"`      // 是否下载字幕
+	MetadataExtensions string      `json:"metadataExtensions" gorm:"type:VARCHAR(255);default:nfo,jpg,png"` // 刮削数据文件扩展名
+	SubtitleExtensions string      `json:"subtitleExtensions" gorm:"type:VARCHAR(255);default:srt,ass,ssa"` // 字幕文件扩展名
+	NotifyEvents       string      `json:"notifyEvents" gorm:"type:VARCHAR(255);default:completed,failed"`  // 需要通知的事件，逗号分隔，none 表示不通知
+	NotifyChannels     string      `json:"notifyChannels" gorm:"type:VARCHAR(255)"`                         // 通知渠道，逗号分隔，为空时使用默认渠道
+	NotifyMinGenerated int         `json:"notifyMinGenerated" gorm:"not null;default:0"`                    // 成功运行时生成文件数达到该值才通知
+	RunOnlyIfIdle      bool        `json:"runOnlyIfIdle" gorm:"type:TINYINT(1);not null;default:0"`         // 定时触发时仅在执行队列空闲时执行，否则跳过本次调度
+	Timezone           string      `json:"timezone" gorm:"type:VARCHAR(64)"`                                // 定时调度使用的时区，为空时使用全局设置
+	JitterSeconds      int         `json:"jitterSeconds" gorm:"not null;default:0"`                         // 触发时随机延迟的最大秒数，为 0 时使用全局设置
+	BlackoutWindows    string      `json:"blackoutWindows" gorm:"type:VARCHAR(255)"`                        // 禁止执行时段，如 19:00-23:00，与全局时段共同生效
+	MisfirePolicy      string      `json:"misfirePolicy" gorm:"type:VARCHAR(20);not null;default:skip"`     // 错过定时执行后的补偿策略：skip / once / limit
+	MisfireLimit       int         `json:"misfireLimit" gorm:"not null;default:0"`                          // 补偿策略为 limit 时最多补偿执行的次数
+	PostActions        PostActions `json:"postActions" gorm:"type:text"`                                    // 任务完成后的后续动作
+	Hooks              TaskHooks   `json:"hooks" gorm:"type:text"`                                          // 任务生命周期及每个文件处理前后执行的钩子命令
 }
 
 // TableName 表名
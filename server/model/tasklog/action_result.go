@@ -0,0 +1,59 @@
+package tasklog
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// 后续动作执行结果状态
+const (
+	ActionStatusSuccess = "success"
+	ActionStatusFailed  = "failed"
+	ActionStatusSkipped = "skipped"
+)
+
+// ActionResult 任务完成后一个后续动作的执行结果
+type ActionResult struct {
+	Type     string `json:"type"`               // 动作类型
+	Target   string `json:"target,omitempty"`   // 动作目标，如任务名称、媒体库、请求地址或命令
+	Status   string `json:"status"`             // success / failed / skipped
+	Message  string `json:"message,omitempty"`  // 结果说明或命令输出
+	Duration int64  `json:"duration,omitempty"` // 耗时，毫秒
+}
+
+// ActionResults 后续动作执行结果列表，以 JSON 形式保存在任务日志表中
+type ActionResults []ActionResult
+
+// Value 实现 driver.Valuer
+func (r ActionResults) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 sql.Scanner
+func (r *ActionResults) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("无法解析后续动作结果: %T", value)
+	}
+	if len(data) == 0 {
+		*r = nil
+		return nil
+	}
+	return json.Unmarshal(data, r)
+}
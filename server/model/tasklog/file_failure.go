@@ -0,0 +1,35 @@
+package tasklog
+
+import (
+	"time"
+)
+
+// FileFailure 失败阶段常量
+const (
+	FailurePhaseList     = "list"
+	FailurePhaseStrm     = "strm"
+	FailurePhaseDownload = "download"
+)
+
+// FileFailure 单次运行中处理失败的文件记录
+type FileFailure struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	TaskID       uint      `json:"taskId" gorm:"not null;index"`
+	TaskLogID    uint      `json:"taskLogId" gorm:"not null;index"`
+	Phase        string    `json:"phase" gorm:"not null;type:varchar(20)"`        // 失败阶段：list / strm / download
+	SourcePath   string    `json:"sourcePath" gorm:"not null;type:varchar(1024)"` // 源文件路径（list 阶段为目录路径）
+	TargetPath   string    `json:"targetPath" gorm:"type:varchar(1024)"`
+	FileName     string    `json:"fileName" gorm:"type:varchar(255)"`
+	FileSize     int64     `json:"fileSize" gorm:"not null;default:0"`
+	FileType     string    `json:"fileType" gorm:"type:varchar(20)"`
+	ErrorMessage string    `json:"errorMessage" gorm:"type:text"`
+	Resolved     bool      `json:"resolved" gorm:"type:TINYINT(1);not null;default:0"` // 重试成功后标记为已解决
+	RetryLogID   *uint     `json:"retryLogId" gorm:"default:null"`                     // 最近一次重试所属的任务日志
+}
+
+// TableName 表名
+func (FileFailure) TableName() string {
+	return "file_failures"
+}
@@ -0,0 +1,62 @@
+package tasklog
+
+import (
+	"time"
+)
+
+// 运行日志级别
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// 运行日志事件类型
+const (
+	LogEventRun      = "run"      // 任务开始和结束
+	LogEventScan     = "scan"     // 扫描目录
+	LogEventSkip     = "skip"     // 跳过文件
+	LogEventStrm     = "strm"     // 生成 STRM 文件
+	LogEventDownload = "download" // 下载元数据和字幕文件
+	LogEventHook     = "hook"     // 执行钩子命令
+)
+
+// LogEntry 单次运行过程中的结构化日志条目
+type LogEntry struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+	TaskID    uint      `json:"taskId" gorm:"not null;index"`
+	TaskLogID uint      `json:"taskLogId" gorm:"not null;index"`
+	Level     string    `json:"level" gorm:"not null;type:varchar(10)"` // 日志级别：debug / info / warn / error
+	Event     string    `json:"event" gorm:"not null;type:varchar(20)"` // 事件类型：run / scan / skip / strm / download / hook
+	Path      string    `json:"path" gorm:"type:varchar(1024)"`         // 相关的文件或目录路径
+	Message   string    `json:"message" gorm:"type:text"`
+}
+
+// TableName 表名
+func (LogEntry) TableName() string {
+	return "task_log_entries"
+}
+
+// LogLevels 按严重程度从低到高排列的日志级别
+var LogLevels = []string{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError}
+
+// LogLevelRank 获取日志级别的严重程度，级别无效时返回 -1
+func LogLevelRank(level string) int {
+	for i, l := range LogLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// LevelsAtLeast 获取不低于指定级别的全部日志级别
+func LevelsAtLeast(level string) []string {
+	rank := LogLevelRank(level)
+	if rank < 0 {
+		return LogLevels
+	}
+	return LogLevels[rank:]
+}
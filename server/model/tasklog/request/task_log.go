@@ -46,3 +46,19 @@ type TaskLogListReq struct {
 type FileProcessingStatsReq struct {
 	TimeRange string `json:"timeRange" form:"timeRange" binding:"omitempty,oneof=day month year" example:"day"` // 时间范围: day, month, year
 }
+
+// FileFailureListReq 运行失败文件列表查询请求
+type FileFailureListReq struct {
+	request.PageInfo
+	TaskLogID uint   `json:"-"`                                                                                  // 通过路径参数传递
+	Phase     string `json:"phase" form:"phase" binding:"omitempty,oneof=list strm download" example:"download"` // 失败阶段筛选
+	Resolved  *bool  `json:"resolved" form:"resolved" example:"false"`                                           // 是否已解决筛选
+}
+
+// LogEntryListReq 运行日志条目查询请求
+type LogEntryListReq struct {
+	request.PageInfo
+	TaskLogID uint   `json:"-"`                                                                                            // 通过路径参数传递
+	Level     string `json:"level" form:"level" binding:"omitempty,oneof=debug info warn error" example:"warn"`            // 最低日志级别，返回该级别及以上的条目
+	Event     string `json:"event" form:"event" binding:"omitempty,oneof=run scan skip strm download hook" example:"skip"` // 事件类型筛选
+}
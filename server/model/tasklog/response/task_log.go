@@ -37,3 +37,52 @@ type FileProcessingStatsResp struct {
 	MetadataDownloaded int64 `json:"metadataDownloaded"` // 下载的元数据文件数
 	SubtitleDownloaded int64 `json:"subtitleDownloaded"` // 下载的字幕文件数
 }
+
+// FileFailureListResp 运行失败文件列表响应
+type FileFailureListResp struct {
+	List     []tasklog.FileFailure `json:"list"`
+	Total    int64                 `json:"total"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"pageSize"`
+}
+
+// RetryFailuresResp 重试失败文件响应
+type RetryFailuresResp struct {
+	TaskLogID  uint `json:"taskLogId"`  // 被重试的任务日志ID，重试结果在执行时记录到新的任务日志中
+	RetryCount int  `json:"retryCount"` // 待重试的失败记录数
+}
+
+// TaskLogProgressResp 正在执行的任务运行进度快照
+type TaskLogProgressResp struct {
+	TaskLogID              uint      `json:"taskLogId"`
+	TaskID                 uint      `json:"taskId"`
+	Status                 string    `json:"status"`
+	ScopePath              string    `json:"scopePath"`            // 局部扫描的源子路径
+	CurrentDir             string    `json:"currentDir"`           // 正在扫描的源目录
+	StartTime              time.Time `json:"startTime"`            // 开始时间
+	Elapsed                int64     `json:"elapsed"`              // 已运行时间，秒
+	TotalFiles             int       `json:"totalFiles"`           // 已扫描到的文件数
+	ProcessedFiles         int       `json:"processedFiles"`       // 已处理的文件数
+	GeneratedFile          int       `json:"generatedFile"`        // 已生成的 STRM 文件数
+	SkipFile               int       `json:"skipFile"`             // 已跳过的文件数
+	MetadataDownloaded     int       `json:"metadataDownloaded"`   // 已下载的元数据文件数
+	SubtitleDownloaded     int       `json:"subtitleDownloaded"`   // 已下载的字幕文件数
+	FailedCount            int       `json:"failedCount"`          // 处理失败的文件数
+	StrmQueueSize          int       `json:"strmQueueSize"`        // 等待生成 STRM 的文件数
+	DownloadQueueSize      int       `json:"downloadQueueSize"`    // 等待下载的元数据和字幕文件数
+	FilesPerSecond         float64   `json:"filesPerSecond"`       // 运行以来的平均处理速度，文件/秒
+	RecentFilesPerSecond   float64   `json:"recentFilesPerSecond"` // 距上次推送的处理速度，文件/秒
+	ScanFinished           bool      `json:"scanFinished"`
+	StrmProcessingDone     bool      `json:"strmProcessingDone"`
+	DownloadProcessingDone bool      `json:"downloadProcessingDone"`
+	Cancelling             bool      `json:"cancelling"`
+	UpdatedAt              time.Time `json:"updatedAt"` // 快照时间
+}
+
+// LogEntryListResp 运行日志条目列表响应
+type LogEntryListResp struct {
+	List     []tasklog.LogEntry `json:"list"`
+	Total    int64              `json:"total"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"pageSize"`
+}
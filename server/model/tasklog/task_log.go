@@ -14,24 +14,28 @@ const (
 
 // TaskLog 任务日志模型
 type TaskLog struct {
-	ID                 uint       `json:"id" gorm:"primaryKey"`
-	CreatedAt          time.Time  `json:"createdAt"`
-	UpdatedAt          time.Time  `json:"updatedAt"`
-	TaskID             uint       `json:"taskId" gorm:"not null;index"`
-	Status             string     `json:"status" gorm:"not null"`
-	Message            string     `json:"message" gorm:"type:text"`
-	StartTime          time.Time  `json:"startTime" gorm:"not null"`
-	EndTime            *time.Time `json:"endTime" gorm:"default:null"`
-	Duration           int64      `json:"duration" gorm:"not null;default:0"` // 持续时间，单位为秒
-	TotalFile          int        `json:"totalFile" gorm:"not null;default:0"`
-	GeneratedFile      int        `json:"generatedFile" gorm:"not null;default:0"`
-	SkipFile           int        `json:"skipFile" gorm:"not null;default:0"`
-	OverwriteFile      int        `json:"overwriteFile" gorm:"not null;default:0"`
-	MetadataCount      int        `json:"metadataCount" gorm:"not null;default:0"`      // 处理的元数据文件总数
-	SubtitleCount      int        `json:"subtitleCount" gorm:"not null;default:0"`      // 处理的字幕文件总数
-	MetadataDownloaded int        `json:"metadataDownloaded" gorm:"not null;default:0"` // 下载的元数据文件数
-	SubtitleDownloaded int        `json:"subtitleDownloaded" gorm:"not null;default:0"` // 下载的字幕文件数
-	FailedCount        int        `json:"failedCount" gorm:"not null;default:0"`        // 处理失败的文件数
+	ID                 uint          `json:"id" gorm:"primaryKey"`
+	CreatedAt          time.Time     `json:"createdAt"`
+	UpdatedAt          time.Time     `json:"updatedAt"`
+	TaskID             uint          `json:"taskId" gorm:"not null;index"`
+	Status             string        `json:"status" gorm:"not null"`
+	Message            string        `json:"message" gorm:"type:text"`
+	StartTime          time.Time     `json:"startTime" gorm:"not null"`
+	EndTime            *time.Time    `json:"endTime" gorm:"default:null"`
+	Duration           int64         `json:"duration" gorm:"not null;default:0"` // 持续时间，单位为秒
+	TotalFile          int           `json:"totalFile" gorm:"not null;default:0"`
+	GeneratedFile      int           `json:"generatedFile" gorm:"not null;default:0"`
+	SkipFile           int           `json:"skipFile" gorm:"not null;default:0"`
+	OverwriteFile      int           `json:"overwriteFile" gorm:"not null;default:0"`
+	MetadataCount      int           `json:"metadataCount" gorm:"not null;default:0"`        // 处理的元数据文件总数
+	SubtitleCount      int           `json:"subtitleCount" gorm:"not null;default:0"`        // 处理的字幕文件总数
+	MetadataDownloaded int           `json:"metadataDownloaded" gorm:"not null;default:0"`   // 下载的元数据文件数
+	SubtitleDownloaded int           `json:"subtitleDownloaded" gorm:"not null;default:0"`   // 下载的字幕文件数
+	FailedCount        int           `json:"failedCount" gorm:"not null;default:0"`          // 处理失败的文件数
+	ScopePath          string        `json:"scopePath" gorm:"type:varchar(1024);default:''"` // 局部扫描的源子路径，为空表示完整扫描
+	Trigger            string        `json:"trigger" gorm:"type:varchar(20);default:''"`     // 触发来源：cron / manual / webhook / api / catchup / chain
+	ActionResults      ActionResults `json:"actionResults" gorm:"type:text"`                 // 任务完成后各后续动作的执行结果
+	HookOutput         string        `json:"hookOutput" gorm:"type:text"`                    // 钩子命令的输出及执行错误
 }
 
 // TableName 表名
@@ -0,0 +1,18 @@
+package request
+
+// TrashListReq 回收站分页查询请求
+type TrashListReq struct {
+	Page     int    `json:"page" form:"page" binding:"required,min=1"`
+	PageSize int    `json:"pageSize" form:"pageSize" binding:"required,min=1,max=100"`
+	TaskID   *uint  `json:"taskId" form:"taskId"`
+	Status   string `json:"status" form:"status"`   // trashed / restored，默认全部
+	Keyword  string `json:"keyword" form:"keyword"` // 可搜索文件名、原始路径
+}
+
+// TrashRestoreReq 回收站恢复请求
+// IDs 与 Directory 至少提供一个；Directory 会恢复原始路径位于该目录下的全部条目
+type TrashRestoreReq struct {
+	IDs       []uint `json:"ids"`
+	Directory string `json:"directory"`
+	TaskID    *uint  `json:"taskId"` // 按目录恢复时可限定任务
+}
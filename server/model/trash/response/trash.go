@@ -0,0 +1,18 @@
+package response
+
+import "github.com/MccRay-s/alist2strm/model/trash"
+
+// TrashListResp 回收站分页列表响应
+type TrashListResp struct {
+	List  []*trash.TrashItem `json:"list"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Size  int                `json:"size"`
+}
+
+// TrashRestoreResp 回收站恢复结果响应
+type TrashRestoreResp struct {
+	Restored int      `json:"restored"`
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors"`
+}
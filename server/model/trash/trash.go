@@ -0,0 +1,32 @@
+package trash
+
+import (
+	"time"
+)
+
+// TrashItem 状态常量
+const (
+	TrashStatusTrashed  = "trashed"
+	TrashStatusRestored = "restored"
+)
+
+// TrashItem 回收站条目模型
+type TrashItem struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	TaskID       uint       `json:"taskId" gorm:"not null;index"`
+	SourcePath   string     `json:"sourcePath" gorm:"type:varchar(1024)"`                  // 触发删除的源文件路径
+	OriginalPath string     `json:"originalPath" gorm:"not null;index;type:varchar(1024)"` // 目标文件原始路径
+	TrashPath    string     `json:"trashPath" gorm:"not null;type:varchar(1024)"`          // 回收站内的存放路径
+	FileName     string     `json:"fileName" gorm:"not null;type:varchar(255)"`
+	FileSize     int64      `json:"fileSize" gorm:"not null;default:0"`
+	Status       string     `json:"status" gorm:"not null;index;type:varchar(20)"`
+	ExpireAt     time.Time  `json:"expireAt" gorm:"not null;index"` // 过期时间，过期后自动清除
+	RestoredAt   *time.Time `json:"restoredAt" gorm:"default:null"`
+}
+
+// TableName 表名
+func (TrashItem) TableName() string {
+	return "trash_items"
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/MccRay-s/alist2strm/database"
+	"github.com/MccRay-s/alist2strm/model/tasklog"
+	taskLogRequest "github.com/MccRay-s/alist2strm/model/tasklog/request"
+)
+
+type FileFailureRepository struct{}
+
+// 包级别的全局实例
+var FileFailure = &FileFailureRepository{}
+
+// Create 创建失败记录
+func (r *FileFailureRepository) Create(failure *tasklog.FileFailure) error {
+	return database.DB.Create(failure).Error
+}
+
+// ListByTaskLogID 分页获取某次运行的失败记录
+func (r *FileFailureRepository) ListByTaskLogID(req *taskLogRequest.FileFailureListReq) ([]tasklog.FileFailure, int64, error) {
+	var failures []tasklog.FileFailure
+	var total int64
+
+	query := database.DB.Model(&tasklog.FileFailure{}).Where("task_log_id = ?", req.TaskLogID)
+	if req.Phase != "" {
+		query = query.Where("phase = ?", req.Phase)
+	}
+	if req.Resolved != nil {
+		query = query.Where("resolved = ?", *req.Resolved)
+	}
+	if req.Keyword != "" {
+		keyword := "%" + req.Keyword + "%"
+		query = query.Where("source_path LIKE ? OR error_message LIKE ?", keyword, keyword)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Scopes(req.Paginate()).Order("id ASC").Find(&failures).Error
+	return failures, total, err
+}
+
+// GetUnresolvedByTaskLogID 获取某次运行中尚未解决的全部失败记录
+func (r *FileFailureRepository) GetUnresolvedByTaskLogID(taskLogID uint) ([]tasklog.FileFailure, error) {
+	var failures []tasklog.FileFailure
+	err := database.DB.Where("task_log_id = ? AND resolved = ?", taskLogID, false).Order("id ASC").Find(&failures).Error
+	return failures, err
+}
+
+// MarkRetried 记录失败项的重试结果
+func (r *FileFailureRepository) MarkRetried(id uint, retryLogID uint, resolved bool) error {
+	return database.DB.Model(&tasklog.FileFailure{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"retry_log_id": retryLogID,
+		"resolved":     resolved,
+	}).Error
+}
+
+// DeleteByTaskID 删除任务的全部失败记录
+func (r *FileFailureRepository) DeleteByTaskID(taskID uint) error {
+	return database.DB.Where("task_id = ?", taskID).Delete(&tasklog.FileFailure{}).Error
+}
@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/MccRay-s/alist2strm/database"
 	"github.com/MccRay-s/alist2strm/model/filehistory"
 	fileHistoryRequest "github.com/MccRay-s/alist2strm/model/filehistory/request"
+	"gorm.io/gorm"
 )
 
 type FileHistoryRepository struct{}
@@ -91,6 +94,26 @@ func (r *FileHistoryRepository) UpdateByID(id uint, updateFields map[string]inte
 	return database.DB.Model(&filehistory.FileHistory{}).Where("id = ?", id).Updates(updateFields).Error
 }
 
+// MoveByID 将文件历史记录迁移到新的源路径，新位置已有同一文件的记录时先删除该记录再更新，避免违反 idx_file_path_size 唯一索引
+func (r *FileHistoryRepository) MoveByID(history *filehistory.FileHistory, sourcePath, fileName, targetFilePath string) error {
+	if history == nil || history.ID == 0 {
+		return nil
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id <> ? AND source_path = ? AND file_name = ? AND file_size = ? AND file_type = ?",
+			history.ID, sourcePath, fileName, history.FileSize, history.FileType).
+			Delete(&filehistory.FileHistory{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&filehistory.FileHistory{}).Where("id = ?", history.ID).Updates(map[string]interface{}{
+			"source_path":      sourcePath,
+			"file_name":        fileName,
+			"target_file_path": targetFilePath,
+		}).Error
+	})
+}
+
 // GetByFileAttributes 根据文件路径、名称、大小和类型获取文件历史记录
 func (r *FileHistoryRepository) GetByFileAttributes(sourcePath, fileName string, fileSize int64, fileType string) (*filehistory.FileHistory, error) {
 	if sourcePath == "" || fileName == "" {
@@ -107,3 +130,40 @@ func (r *FileHistoryRepository) GetByFileAttributes(sourcePath, fileName string,
 
 	return &fileHistory, nil
 }
+
+// FindByTaskIDInBatches 分批遍历任务的文件历史记录
+func (r *FileHistoryRepository) FindByTaskIDInBatches(taskID uint, batchSize int, fn func(records []*filehistory.FileHistory) error) error {
+	var records []*filehistory.FileHistory
+	return database.DB.Where("task_id = ?", taskID).FindInBatches(&records, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(records)
+	}).Error
+}
+
+// FindMoveCandidates 查找任务中可能是同一文件移动前的历史记录
+// 有 hash 时按 hash 匹配，否则按文件名和大小匹配
+func (r *FileHistoryRepository) FindMoveCandidates(taskID uint, hash, fileName string, fileSize int64, fileType, excludeSourcePath string) ([]*filehistory.FileHistory, error) {
+	var records []*filehistory.FileHistory
+	query := database.DB.Where("task_id = ? AND file_type = ? AND source_path <> ?", taskID, fileType, excludeSourcePath)
+	if hash != "" {
+		query = query.Where("hash = ?", hash)
+	} else {
+		query = query.Where("file_name = ? AND file_size = ?", fileName, fileSize)
+	}
+	err := query.Order("updated_at DESC").Limit(10).Find(&records).Error
+	return records, err
+}
+
+// ListStrmCreatedBetween 获取在指定时间段内新生成的 STRM 文件路径，最多返回 limit 条，同时返回总数
+func (r *FileHistoryRepository) ListStrmCreatedBetween(start, end time.Time, limit int) ([]string, int64, error) {
+	query := database.DB.Model(&filehistory.FileHistory{}).
+		Where("is_strm = ? AND created_at >= ? AND created_at < ?", true, start, end)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var paths []string
+	err := query.Order("created_at ASC").Limit(limit).Pluck("target_file_path", &paths).Error
+	return paths, total, err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MccRay-s/alist2strm/database"
+	"github.com/MccRay-s/alist2strm/model/health"
+	healthRequest "github.com/MccRay-s/alist2strm/model/health/request"
+)
+
+type HealthRepository struct{}
+
+// 包级别的全局实例
+var Health = &HealthRepository{}
+
+// Create 创建健康检查记录
+func (r *HealthRepository) Create(check *health.HealthCheck) error {
+	return database.DB.Create(check).Error
+}
+
+// List 分页获取健康检查记录
+func (r *HealthRepository) List(req *healthRequest.HealthHistoryReq) ([]*health.HealthCheck, int64, error) {
+	var checks []*health.HealthCheck
+	var total int64
+
+	query := database.DB.Model(&health.HealthCheck{})
+
+	if req.Source != "" {
+		query = query.Where("source = ?", req.Source)
+	}
+	if req.Healthy != nil {
+		query = query.Where("healthy = ?", *req.Healthy)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&checks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return checks, total, nil
+}
+
+// DeleteBefore 删除指定时间之前的健康检查记录，返回删除的条数
+func (r *HealthRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := database.DB.Where("created_at < ?", before).Delete(&health.HealthCheck{})
+	return result.RowsAffected, result.Error
+}
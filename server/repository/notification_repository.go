@@ -8,6 +8,7 @@ import (
 	"github.com/MccRay-s/alist2strm/database"
 	"github.com/MccRay-s/alist2strm/model/configs"
 	"github.com/MccRay-s/alist2strm/model/notification"
+	notificationRequest "github.com/MccRay-s/alist2strm/model/notification/request"
 	"github.com/MccRay-s/alist2strm/utils"
 	"gorm.io/gorm"
 )
@@ -69,18 +70,18 @@ func (r *NotificationRepository) UpdateSettings(settings *notification.Settings)
 	}
 
 	config, err := Config.GetByCode("NOTIFICATION_SETTINGS")
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// 配置不存在，创建新配置
-			newConfig := &configs.Config{
-				Name:  "通知系统配置",
-				Code:  "NOTIFICATION_SETTINGS",
-				Value: string(jsonBytes),
-			}
-			return Config.Create(newConfig)
-		}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return err
 	}
+	if config == nil {
+		// 配置不存在，创建新配置
+		newConfig := &configs.Config{
+			Name:  "通知系统配置",
+			Code:  "NOTIFICATION_SETTINGS",
+			Value: string(jsonBytes),
+		}
+		return Config.Create(newConfig)
+	}
 
 	// 更新现有配置
 	config.Value = string(jsonBytes)
@@ -125,12 +126,15 @@ func (r *NotificationRepository) GetPendingNotifications(limit int) ([]*notifica
 
 	// 获取状态为待处理的通知，或者状态为失败但重试时间已到的通知
 	// 只处理状态为pending或者有next_retry_time且时间已到的通知
-	err := database.DB.Where("status = ? OR (status = ? AND next_retry_time IS NOT NULL AND next_retry_time <= ?)",
+	query := database.DB.Where("status = ? OR (status = ? AND next_retry_time IS NOT NULL AND next_retry_time <= ?)",
 		notification.StatusPending, notification.StatusFailed, now).
 		// 优先按重试时间排序，确保到时间的重试任务被优先处理
-		Order("CASE WHEN next_retry_time IS NOT NULL THEN next_retry_time ELSE created_at END ASC").
-		Limit(limit).
-		Find(&notifications).Error
+		Order("CASE WHEN next_retry_time IS NOT NULL THEN next_retry_time ELSE created_at END ASC")
+	// limit 为 0 表示获取全部，GORM 的 Limit(0) 会返回空结果，因此仅在大于 0 时限制数量
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&notifications).Error
 
 	// 记录查询到的通知数量和状态
 	if err == nil {
@@ -201,6 +205,23 @@ func (r *NotificationRepository) RequeueNotification(id uint, retryCount int, ne
 	return err
 }
 
+// DeferNotification 延迟发送通知（用于免打扰时段），不增加重试次数
+func (r *NotificationRepository) DeferNotification(id uint, nextRetryTime time.Time) error {
+	return database.DB.Model(&notification.Queue{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          notification.StatusPending,
+		"next_retry_time": nextRetryTime,
+	}).Error
+}
+
+// HasQueueSince 检查指定时间之后是否已创建过某类模板的通知（包含已删除的记录）
+func (r *NotificationRepository) HasQueueSince(templateType notification.TemplateType, since time.Time) (bool, error) {
+	var count int64
+	err := database.DB.Unscoped().Model(&notification.Queue{}).
+		Where("template_type = ? AND created_at >= ?", string(templateType), since).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // UpdateNotificationForRetry 更新通知为重试状态（为保持兼容性，保留此方法）
 func (r *NotificationRepository) UpdateNotificationForRetry(id uint, retryCount int, nextRetryTime time.Time, errorMsg string) error {
 	// 直接调用新的RequeueNotification方法
@@ -247,3 +268,69 @@ func (r *NotificationRepository) HasPendingNotifications() (bool, error) {
 
 	return count > 0, nil
 }
+
+// ListQueue 分页获取通知队列
+func (r *NotificationRepository) ListQueue(req *notificationRequest.NotificationQueueListReq) ([]notification.Queue, int64, error) {
+	var notifications []notification.Queue
+	var total int64
+
+	query := database.DB.Model(&notification.Queue{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.ChannelType != "" {
+		query = query.Where("channel_type = ?", req.ChannelType)
+	}
+	if req.TemplateType != "" {
+		query = query.Where("template_type = ?", req.TemplateType)
+	}
+	if req.Keyword != "" {
+		keyword := "%" + req.Keyword + "%"
+		query = query.Where("payload LIKE ? OR error_message LIKE ?", keyword, keyword)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Scopes(req.Paginate()).Order("id DESC").Find(&notifications).Error
+	return notifications, total, err
+}
+
+// GetQueueByID 根据ID获取通知
+func (r *NotificationRepository) GetQueueByID(id uint) (*notification.Queue, error) {
+	var notif notification.Queue
+	err := database.DB.First(&notif, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &notif, nil
+}
+
+// GetQueueByStatus 获取指定状态的全部通知
+func (r *NotificationRepository) GetQueueByStatus(status notification.Status) ([]*notification.Queue, error) {
+	var notifications []*notification.Queue
+	err := database.DB.Where("status = ?", status).Order("id ASC").Find(&notifications).Error
+	return notifications, err
+}
+
+// ResetForRetry 将通知重置为待处理状态并清空重试计数，用于手动重试
+func (r *NotificationRepository) ResetForRetry(id uint) error {
+	return database.DB.Model(&notification.Queue{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          notification.StatusPending,
+		"retry_count":     0,
+		"next_retry_time": nil,
+		"error_message":   "",
+	}).Error
+}
+
+// DeleteQueue 删除通知
+func (r *NotificationRepository) DeleteQueue(id uint) error {
+	return database.DB.Delete(&notification.Queue{}, id).Error
+}
+
+// DeleteQueueByStatus 删除指定状态的全部通知，返回删除数量
+func (r *NotificationRepository) DeleteQueueByStatus(status notification.Status) (int64, error) {
+	result := database.DB.Where("status = ?", status).Delete(&notification.Queue{})
+	return result.RowsAffected, result.Error
+}
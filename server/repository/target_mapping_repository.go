@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/database"
+	"github.com/MccRay-s/alist2strm/model/filehistory"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TargetMappingRepository struct{}
+
+// 包级别的全局实例
+var TargetMapping = &TargetMappingRepository{}
+
+// Upsert 创建或更新源文件的目标路径映射
+func (r *TargetMappingRepository) Upsert(mapping *filehistory.TargetMapping) error {
+	mapping.UpdatedAt = time.Now()
+	return database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "task_id"}, {Name: "source_path"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target_path", "file_type", "updated_at"}),
+	}).Create(mapping).Error
+}
+
+// GetBySourcePath 根据任务ID和源文件路径获取映射
+func (r *TargetMappingRepository) GetBySourcePath(taskID uint, sourcePath string) (*filehistory.TargetMapping, error) {
+	var mapping filehistory.TargetMapping
+	err := database.DB.Where("task_id = ? AND source_path = ?", taskID, sourcePath).First(&mapping).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// ListBySourceDir 获取源路径位于指定目录下的所有映射
+func (r *TargetMappingRepository) ListBySourceDir(taskID uint, sourceDir string) ([]*filehistory.TargetMapping, error) {
+	var mappings []*filehistory.TargetMapping
+	prefix := strings.TrimRight(sourceDir, "/")
+	err := database.DB.Where("task_id = ? AND source_path LIKE ? ESCAPE '\\'", taskID, escapeLike(prefix)+"/%").
+		Order("id ASC").Find(&mappings).Error
+	return mappings, err
+}
+
+// likeEscaper 转义 LIKE 模式中的通配符，配合 ESCAPE '\' 使用
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike 转义路径中的 % 和 _，避免按目录前缀匹配时误匹配名称相近的其他目录
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// ListByTaskID 获取任务的全部映射
+func (r *TargetMappingRepository) ListByTaskID(taskID uint) ([]*filehistory.TargetMapping, error) {
+	var mappings []*filehistory.TargetMapping
+	err := database.DB.Where("task_id = ?", taskID).Order("id ASC").Find(&mappings).Error
+	return mappings, err
+}
+
+// CountByTaskID 统计任务的映射数量
+func (r *TargetMappingRepository) CountByTaskID(taskID uint) (int64, error) {
+	var count int64
+	err := database.DB.Model(&filehistory.TargetMapping{}).Where("task_id = ?", taskID).Count(&count).Error
+	return count, err
+}
+
+// Delete 删除映射
+func (r *TargetMappingRepository) Delete(id uint) error {
+	return database.DB.Delete(&filehistory.TargetMapping{}, id).Error
+}
+
+// DeleteByTaskID 删除任务的全部映射
+func (r *TargetMappingRepository) DeleteByTaskID(taskID uint) error {
+	return database.DB.Where("task_id = ?", taskID).Delete(&filehistory.TargetMapping{}).Error
+}
+
+// DeleteByTargetPath 删除指向指定目标文件的映射
+func (r *TargetMappingRepository) DeleteByTargetPath(taskID uint, targetPath string) error {
+	return database.DB.Where("task_id = ? AND target_path = ?", taskID, targetPath).Delete(&filehistory.TargetMapping{}).Error
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MccRay-s/alist2strm/database"
+	"github.com/MccRay-s/alist2strm/model/tasklog"
+	taskLogRequest "github.com/MccRay-s/alist2strm/model/tasklog/request"
+	"gorm.io/gorm"
+)
+
+type TaskLogEntryRepository struct{}
+
+// 包级别的全局实例
+var TaskLogEntry = &TaskLogEntryRepository{}
+
+// logEntryBatchSize 批量写入和导出运行日志时每批的条目数
+const logEntryBatchSize = 500
+
+// CreateBatch 批量写入运行日志条目
+func (r *TaskLogEntryRepository) CreateBatch(entries []tasklog.LogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return database.DB.CreateInBatches(entries, logEntryBatchSize).Error
+}
+
+// ListByTaskLogID 分页获取某次运行的日志条目
+func (r *TaskLogEntryRepository) ListByTaskLogID(req *taskLogRequest.LogEntryListReq) ([]tasklog.LogEntry, int64, error) {
+	var entries []tasklog.LogEntry
+	var total int64
+
+	query := r.filter(req.TaskLogID, req.Level)
+	if req.Event != "" {
+		query = query.Where("event = ?", req.Event)
+	}
+	if req.Keyword != "" {
+		keyword := "%" + req.Keyword + "%"
+		query = query.Where("path LIKE ? OR message LIKE ?", keyword, keyword)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Scopes(req.Paginate()).Order("id ASC").Find(&entries).Error
+	return entries, total, err
+}
+
+// EachByTaskLogID 按写入顺序分批遍历某次运行中不低于指定级别的日志条目
+func (r *TaskLogEntryRepository) EachByTaskLogID(taskLogID uint, level string, fn func(entries []tasklog.LogEntry) error) error {
+	var entries []tasklog.LogEntry
+	return r.filter(taskLogID, level).FindInBatches(&entries, logEntryBatchSize, func(tx *gorm.DB, batch int) error {
+		return fn(entries)
+	}).Error
+}
+
+// filter 按运行和最低日志级别筛选
+func (r *TaskLogEntryRepository) filter(taskLogID uint, level string) *gorm.DB {
+	query := database.DB.Model(&tasklog.LogEntry{}).Where("task_log_id = ?", taskLogID)
+	if level != "" {
+		query = query.Where("level IN ?", tasklog.LevelsAtLeast(level))
+	}
+	return query
+}
+
+// DeleteByTaskID 删除任务的全部运行日志
+func (r *TaskLogEntryRepository) DeleteByTaskID(taskID uint) error {
+	return database.DB.Where("task_id = ?", taskID).Delete(&tasklog.LogEntry{}).Error
+}
+
+// DeleteBefore 删除指定时间之前的运行日志，返回删除的条目数
+func (r *TaskLogEntryRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := database.DB.Where("created_at < ?", before).Delete(&tasklog.LogEntry{})
+	return result.RowsAffected, result.Error
+}
@@ -121,6 +121,16 @@ func (r *TaskLogRepository) GetLatestByTaskID(taskID uint, limit int) ([]tasklog
 	return logs, total, nil
 }
 
+// ListFinishedBetween 获取在指定时间段内结束的任务日志（成功或失败），按结束时间升序
+func (r *TaskLogRepository) ListFinishedBetween(start, end time.Time) ([]tasklog.TaskLog, error) {
+	var logs []tasklog.TaskLog
+	err := database.DB.Where("status IN ? AND end_time >= ? AND end_time < ?",
+		[]string{tasklog.TaskLogStatusCompleted, tasklog.TaskLogStatusFailed}, start, end).
+		Order("end_time ASC").
+		Find(&logs).Error
+	return logs, err
+}
+
 // UpdateEndTime 更新任务日志结束时间和持续时间
 func (r *TaskLogRepository) UpdateEndTime(id uint, endTime time.Time, duration int64) error {
 	updates := map[string]interface{}{
@@ -130,6 +140,18 @@ func (r *TaskLogRepository) UpdateEndTime(id uint, endTime time.Time, duration i
 	return database.DB.Model(&tasklog.TaskLog{}).Where("id = ?", id).Updates(updates).Error
 }
 
+// FailRunningByTaskID 将任务仍处于运行状态的日志标记为失败，用于服务重启后清理被中断的执行记录
+func (r *TaskLogRepository) FailRunningByTaskID(taskID uint, message string, endTime time.Time) (int64, error) {
+	result := database.DB.Model(&tasklog.TaskLog{}).
+		Where("task_id = ? AND status = ?", taskID, tasklog.TaskLogStatusRunning).
+		Updates(map[string]interface{}{
+			"status":   tasklog.TaskLogStatusFailed,
+			"message":  message,
+			"end_time": endTime,
+		})
+	return result.RowsAffected, result.Error
+}
+
 // GetRunningLogByTaskID 获取任务正在运行的日志
 func (r *TaskLogRepository) GetRunningLogByTaskID(taskID uint) (*tasklog.TaskLog, error) {
 	var tl tasklog.TaskLog
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/database"
+	"github.com/MccRay-s/alist2strm/model/task"
+	"gorm.io/gorm"
+)
+
+type TaskQueueRepository struct{}
+
+// 包级别的全局实例
+var TaskQueue = &TaskQueueRepository{}
+
+// queueOrder 队列执行顺序：优先级高的在前，同优先级按排列顺序和加入时间
+const queueOrder = "priority DESC, position ASC, id ASC"
+
+// Create 添加队列条目，排在同优先级条目的最后
+func (r *TaskQueueRepository) Create(entry *task.QueueEntry) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var maxPosition int64
+		if err := tx.Model(&task.QueueEntry{}).Select("COALESCE(MAX(position), 0)").Scan(&maxPosition).Error; err != nil {
+			return err
+		}
+		entry.Position = maxPosition + 1
+		return tx.Create(entry).Error
+	})
+}
+
+// GetByID 根据ID获取队列条目
+func (r *TaskQueueRepository) GetByID(id uint) (*task.QueueEntry, error) {
+	var entry task.QueueEntry
+	err := database.DB.Where("id = ?", id).First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List 按执行顺序获取全部队列条目，正在执行的条目排在最前
+func (r *TaskQueueRepository) List() ([]task.QueueEntry, error) {
+	var entries []task.QueueEntry
+	err := database.DB.Order("CASE WHEN status = '" + task.QueueStatusRunning + "' THEN 0 ELSE 1 END").Order(queueOrder).Find(&entries).Error
+	return entries, err
+}
+
+// ListPending 按执行顺序获取等待中的队列条目
+func (r *TaskQueueRepository) ListPending() ([]task.QueueEntry, error) {
+	var entries []task.QueueEntry
+	err := database.DB.Where("status = ?", task.QueueStatusPending).Order(queueOrder).Find(&entries).Error
+	return entries, err
+}
+
+// NextPending 获取下一个待执行的队列条目
+func (r *TaskQueueRepository) NextPending() (*task.QueueEntry, error) {
+	var entry task.QueueEntry
+	err := database.DB.Where("status = ?", task.QueueStatusPending).Order(queueOrder).First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CountPending 获取等待中的队列条目数
+func (r *TaskQueueRepository) CountPending() (int64, error) {
+	var count int64
+	err := database.DB.Model(&task.QueueEntry{}).Where("status = ?", task.QueueStatusPending).Count(&count).Error
+	return count, err
+}
+
+// MarkRunning 将队列条目标记为正在执行
+func (r *TaskQueueRepository) MarkRunning(id uint, startedAt time.Time) error {
+	return database.DB.Model(&task.QueueEntry{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     task.QueueStatusRunning,
+		"started_at": startedAt,
+	}).Error
+}
+
+// UpdateTrigger 更新等待中条目的触发来源和优先级，用于合并重复的触发
+func (r *TaskQueueRepository) UpdateTrigger(id uint, trigger string, priority int) error {
+	return database.DB.Model(&task.QueueEntry{}).Where("id = ? AND status = ?", id, task.QueueStatusPending).Updates(map[string]interface{}{
+		"trigger":  trigger,
+		"priority": priority,
+	}).Error
+}
+
+// ResetRunning 将正在执行的条目恢复为等待状态，用于服务重启后继续执行被中断的任务
+func (r *TaskQueueRepository) ResetRunning() ([]task.QueueEntry, error) {
+	var entries []task.QueueEntry
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("status = ?", task.QueueStatusRunning).Find(&entries).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Model(&task.QueueEntry{}).Where("status = ?", task.QueueStatusRunning).Updates(map[string]interface{}{
+			"status":     task.QueueStatusPending,
+			"started_at": nil,
+		}).Error
+	})
+	return entries, err
+}
+
+// Delete 删除队列条目
+func (r *TaskQueueRepository) Delete(id uint) error {
+	return database.DB.Delete(&task.QueueEntry{}, id).Error
+}
+
+// DeletePendingByTaskID 删除任务所有等待中的队列条目，返回删除的条数
+func (r *TaskQueueRepository) DeletePendingByTaskID(taskID uint) (int64, error) {
+	result := database.DB.Where("task_id = ? AND status = ?", taskID, task.QueueStatusPending).Delete(&task.QueueEntry{})
+	return result.RowsAffected, result.Error
+}
+
+// ExistsPendingByTaskID 检查任务是否有等待中的队列条目
+func (r *TaskQueueRepository) ExistsPendingByTaskID(taskID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&task.QueueEntry{}).Where("task_id = ? AND status = ?", taskID, task.QueueStatusPending).Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateOrder 按给定顺序重新设置等待中条目的排列顺序，并保存各条目的优先级
+func (r *TaskQueueRepository) UpdateOrder(entries []task.QueueEntry) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		for i, entry := range entries {
+			if err := tx.Model(&task.QueueEntry{}).Where("id = ? AND status = ?", entry.ID, task.QueueStatusPending).
+				Updates(map[string]interface{}{"position": i + 1, "priority": entry.Priority}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -143,17 +143,8 @@ func (r *TaskRepository) ListAll(req *taskRequest.TaskAllReq) ([]task.Task, erro
 
 // UpdateRunningStatus 更新任务运行状态
 func (r *TaskRepository) UpdateRunningStatus(id uint, running bool) error {
-	updates := map[string]interface{}{
-		"running": running,
-	}
-
-	// 如果设置为运行状态，更新最后运行时间
-	if running {
-		now := time.Now()
-		updates["last_run_at"] = &now
-	}
-
-	return database.DB.Model(&task.Task{}).Where("id = ?", id).Updates(updates).Error
+	// 最后执行时间仅在完整扫描时由 UpdateLastRunAt 更新，局部扫描和失败重试不计入
+	return database.DB.Model(&task.Task{}).Where("id = ?", id).Update("running", running).Error
 }
 
 // ResetRunningStatus 重置所有任务运行状态
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/database"
+	"github.com/MccRay-s/alist2strm/model/trash"
+	trashRequest "github.com/MccRay-s/alist2strm/model/trash/request"
+	"gorm.io/gorm"
+)
+
+type TrashRepository struct{}
+
+// 包级别的全局实例
+var Trash = &TrashRepository{}
+
+// Create 创建回收站条目
+func (r *TrashRepository) Create(item *trash.TrashItem) error {
+	return database.DB.Create(item).Error
+}
+
+// GetByID 根据ID获取回收站条目
+func (r *TrashRepository) GetByID(id uint) (*trash.TrashItem, error) {
+	var item trash.TrashItem
+	err := database.DB.Where("id = ?", id).First(&item).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// List 分页获取回收站条目
+func (r *TrashRepository) List(req *trashRequest.TrashListReq) ([]*trash.TrashItem, int64, error) {
+	var items []*trash.TrashItem
+	var total int64
+
+	query := database.DB.Model(&trash.TrashItem{})
+
+	if req.TaskID != nil {
+		query = query.Where("task_id = ?", *req.TaskID)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Keyword != "" {
+		keyword := "%" + req.Keyword + "%"
+		query = query.Where("file_name LIKE ? OR original_path LIKE ?", keyword, keyword)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// ListTrashedByIDs 根据ID列表获取仍在回收站中的条目
+func (r *TrashRepository) ListTrashedByIDs(ids []uint) ([]*trash.TrashItem, error) {
+	var items []*trash.TrashItem
+	err := database.DB.Where("id IN ? AND status = ?", ids, trash.TrashStatusTrashed).
+		Order("id ASC").Find(&items).Error
+	return items, err
+}
+
+// ListTrashedByDirectory 获取原始路径位于指定目录下且仍在回收站中的条目
+func (r *TrashRepository) ListTrashedByDirectory(directory string, taskID *uint) ([]*trash.TrashItem, error) {
+	var items []*trash.TrashItem
+	prefix := strings.TrimRight(directory, "/\\")
+	query := database.DB.Where("status = ? AND (original_path = ? OR original_path LIKE ? ESCAPE '\\')",
+		trash.TrashStatusTrashed, prefix, escapeLike(prefix)+"/%")
+	if taskID != nil {
+		query = query.Where("task_id = ?", *taskID)
+	}
+	err := query.Order("id ASC").Find(&items).Error
+	return items, err
+}
+
+// ListExpired 获取已过期的回收站条目
+func (r *TrashRepository) ListExpired(now time.Time, limit int) ([]*trash.TrashItem, error) {
+	var items []*trash.TrashItem
+	err := database.DB.Where("expire_at < ?", now).Order("expire_at ASC").Limit(limit).Find(&items).Error
+	return items, err
+}
+
+// MarkRestored 标记条目已恢复
+func (r *TrashRepository) MarkRestored(id uint, restoredAt time.Time) error {
+	return database.DB.Model(&trash.TrashItem{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":      trash.TrashStatusRestored,
+		"restored_at": restoredAt,
+	}).Error
+}
+
+// Delete 删除回收站条目
+func (r *TrashRepository) Delete(id uint) error {
+	return database.DB.Delete(&trash.TrashItem{}, id).Error
+}
@@ -67,16 +67,22 @@ func SetupRoutes() *gin.Engine {
 			// 任务相关路由
 			task := auth.Group("/task")
 			{
-				task.POST("/", controller.Task.Create)                     // 创建任务
-				task.GET("/:id", controller.Task.GetTaskInfo)              // 获取指定任务信息
-				task.PUT("/:id", controller.Task.UpdateTask)               // 更新任务信息
-				task.DELETE("/:id", controller.Task.DeleteTask)            // 删除任务
-				task.GET("/list", controller.Task.GetTaskList)             // 获取任务列表（分页）
-				task.GET("/all", controller.Task.GetAllTasks)              // 获取所有任务（不分页）
-				task.GET("/stats", controller.Task.GetTaskStats)           // 获取任务统计数据
-				task.PUT("/:id/toggle", controller.Task.ToggleTaskEnabled) // 切换任务启用状态
-				task.PUT("/:id/reset", controller.Task.ResetTaskStatus)    // 重置任务运行状态
-				task.POST("/:id/execute", controller.Task.ExecuteTask)     // 执行任务（支持同步/异步）
+				task.POST("/", controller.Task.Create)                           // 创建任务
+				task.GET("/:id", controller.Task.GetTaskInfo)                    // 获取指定任务信息
+				task.PUT("/:id", controller.Task.UpdateTask)                     // 更新任务信息
+				task.DELETE("/:id", controller.Task.DeleteTask)                  // 删除任务
+				task.GET("/list", controller.Task.GetTaskList)                   // 获取任务列表（分页）
+				task.GET("/all", controller.Task.GetAllTasks)                    // 获取所有任务（不分页）
+				task.GET("/stats", controller.Task.GetTaskStats)                 // 获取任务统计数据
+				task.PUT("/:id/toggle", controller.Task.ToggleTaskEnabled)       // 切换任务启用状态
+				task.PUT("/:id/reset", controller.Task.ResetTaskStatus)          // 重置任务运行状态
+				task.POST("/:id/execute", controller.Task.ExecuteTask)           // 执行任务（支持同步/异步）
+				task.POST("/:id/cancel", controller.Task.CancelTask)             // 取消正在执行或排队中的任务
+				task.POST("/rescan", controller.Task.RescanPath)                 // 局部重新扫描指定路径
+				task.POST("/schedule/preview", controller.Task.PreviewSchedule)  // 预览调度表达式接下来的触发时间
+				task.GET("/queue", controller.Task.GetQueue)                     // 获取执行队列
+				task.PUT("/queue/order", controller.Task.ReorderQueue)           // 调整执行队列顺序
+				task.DELETE("/queue/:entryId", controller.Task.RemoveQueueEntry) // 从执行队列移除条目
 			}
 
 			// 任务日志相关路由
@@ -85,6 +91,18 @@ func SetupRoutes() *gin.Engine {
 				taskLog.GET("/:id", controller.TaskLogControllerInstance.GetTaskLogInfo)                      // 获取指定任务日志信息
 				taskLog.GET("/", controller.TaskLogControllerInstance.GetTaskLogList)                         // 获取任务日志列表（分页）
 				taskLog.GET("/stats/processing", controller.TaskLogControllerInstance.GetFileProcessingStats) // 获取文件处理统计数据
+				taskLog.GET("/:id/failures", controller.TaskLogControllerInstance.GetFileFailureList)         // 获取该次运行的失败文件列表
+				taskLog.POST("/:id/retry-failures", controller.TaskLogControllerInstance.RetryFailures)       // 仅重试该次运行的失败文件
+				taskLog.GET("/:id/progress", controller.TaskLogControllerInstance.StreamProgress)             // 通过 SSE 推送该次运行的实时进度
+				taskLog.GET("/:id/entries", controller.TaskLogControllerInstance.GetLogEntryList)             // 分页获取该次运行的日志条目
+				taskLog.GET("/:id/entries/download", controller.TaskLogControllerInstance.DownloadLogEntries) // 下载该次运行的日志
+			}
+
+			// 任务和配置导入导出相关路由
+			backup := auth.Group("/backup")
+			{
+				backup.GET("/export", controller.Backup.Export)  // 导出任务和配置（JSON / YAML）
+				backup.POST("/import", controller.Backup.Import) // 导入任务和配置，支持冲突策略和仅校验模式
 			}
 
 			// 文件历史相关路由
@@ -95,6 +113,35 @@ func SetupRoutes() *gin.Engine {
 				fileHistory.GET("/:id", fileHistoryController.GetFileHistoryInfo) // 获取文件历史详情
 			}
 
+			// 回收站相关路由
+			trash := auth.Group("/trash")
+			{
+				trash.GET("/", controller.Trash.GetTrashList)    // 获取回收站分页列表
+				trash.POST("/restore", controller.Trash.Restore) // 恢复回收站条目（按ID或目录）
+			}
+
+			// 源站健康检查相关路由
+			health := auth.Group("/health")
+			{
+				health.GET("/status", controller.Health.GetStatus)   // 获取各源站当前状态
+				health.POST("/check", controller.Health.CheckNow)    // 立即检查所有源站
+				health.GET("/history", controller.Health.GetHistory) // 获取检查记录分页列表
+			}
+
+			// 通知相关路由
+			notification := auth.Group("/notification")
+			{
+				notification.GET("/settings", controller.Notification.GetSettings)                 // 获取通知设置
+				notification.PUT("/settings", controller.Notification.UpdateSettings)              // 更新通知设置
+				notification.POST("/template/preview", controller.Notification.PreviewTemplate)    // 校验并预览通知模板
+				notification.POST("/test", controller.Notification.SendTest)                       // 向指定渠道发送测试通知
+				notification.GET("/queue", controller.Notification.GetQueueList)                   // 获取通知队列列表（分页）
+				notification.POST("/queue/retry-failed", controller.Notification.RetryFailedQueue) // 重试全部发送失败的通知
+				notification.POST("/queue/:id/retry", controller.Notification.RetryQueueItem)      // 重试指定通知
+				notification.DELETE("/queue/:id", controller.Notification.DeleteQueueItem)         // 删除指定通知
+				notification.DELETE("/queue", controller.Notification.CleanQueue)                  // 按状态批量删除通知
+			}
+
 			// AList 相关路由
 			alist := auth.Group("/alist")
 			{
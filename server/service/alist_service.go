@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -77,6 +78,12 @@ func (s *AListService) OnConfigUpdate(code string) error {
 	return nil
 }
 
+// ErrSourcePathNotFound 列目录时源路径不存在，AList、CloudDrive 和本地文件列表在路径不存在时均返回包装了该错误的错误
+var ErrSourcePathNotFound = errors.New("源路径不存在")
+
+// alistObjectNotFound AList 接口在路径不存在时返回的错误信息
+const alistObjectNotFound = "object not found"
+
 // 包级别的全局实例
 var (
 	alistServiceInstance *AListService
@@ -355,6 +362,9 @@ func (c *AListClient) ListFiles(dirPath string) ([]AListFile, error) {
 		}
 
 		if listResp.Code != 200 {
+			if listResp.Code == http.StatusNotFound || strings.Contains(strings.ToLower(listResp.Message), alistObjectNotFound) {
+				return nil, fmt.Errorf("API错误: %s: %w", listResp.Message, ErrSourcePathNotFound)
+			}
 			return nil, fmt.Errorf("API错误: %s", listResp.Message)
 		}
 
@@ -0,0 +1,790 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/backup"
+	backupRequest "github.com/MccRay-s/alist2strm/model/backup/request"
+	backupResponse "github.com/MccRay-s/alist2strm/model/backup/response"
+	"github.com/MccRay-s/alist2strm/model/configs"
+	configRequest "github.com/MccRay-s/alist2strm/model/configs/request"
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"github.com/MccRay-s/alist2strm/model/task"
+	taskRequest "github.com/MccRay-s/alist2strm/model/task/request"
+	"github.com/MccRay-s/alist2strm/repository"
+	"github.com/MccRay-s/alist2strm/utils"
+	"gopkg.in/yaml.v3"
+)
+
+type BackupService struct{}
+
+// 包级别的全局实例
+var Backup = &BackupService{}
+
+// secretKeys 导出时需要脱敏的配置键，包括 AList、CloudDrive、Emby 以及各通知渠道的密钥
+var secretKeys = map[string]bool{
+	"password":   true,
+	"token":      true,
+	"embyToken":  true,
+	"botToken":   true,
+	"appToken":   true,
+	"corpSecret": true,
+	"secret":     true,
+	"sendKey":    true,
+	"deviceKey":  true,
+	"webhookUrl": true,
+	"url":        true, // 通用 Webhook 渠道的地址中常带有访问令牌
+	"headers":    true,
+}
+
+// redactedSecretMessage 导入的密钥为占位值且没有可保留的原值时的提示
+const redactedSecretMessage = "部分密钥在导出时已脱敏，需要重新填写"
+
+// taskImportPlan 单个任务的导入计划
+type taskImportPlan struct {
+	item     *backupResponse.ImportItem
+	docName  string
+	task     *task.Task
+	actions  []backup.PostActionSpec // 完整的后续动作，按名称关联的任务在写入后解析为ID
+	existing bool
+}
+
+// configImportPlan 单个配置的导入计划
+type configImportPlan struct {
+	item     *backupResponse.ImportItem
+	config   *configs.Config
+	existing bool
+}
+
+// Export 导出全部任务和配置
+func (s *BackupService) Export(req *backupRequest.ExportReq) (*backup.Document, error) {
+	doc := &backup.Document{
+		Version:    backup.Version,
+		ExportedAt: time.Now(),
+		Redacted:   req.Redact,
+		Tasks:      []backup.TaskSpec{},
+		Configs:    []backup.ConfigSpec{},
+	}
+
+	tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(tasks))
+	for _, t := range tasks {
+		names[t.ID] = t.Name
+	}
+	// 按创建顺序导出，导入时任务也按相同顺序创建
+	for i := len(tasks) - 1; i >= 0; i-- {
+		doc.Tasks = append(doc.Tasks, taskSpecOf(&tasks[i], names, req.Redact))
+	}
+
+	for _, code := range backup.ConfigCodes {
+		config, err := repository.Config.GetByCode(code)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			continue
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(config.Value), &value); err != nil {
+			utils.Warn("配置值不是有效的 JSON，已跳过导出", "code", code, "error", err.Error())
+			continue
+		}
+		if req.Redact {
+			redactSecrets(value)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		doc.Configs = append(doc.Configs, backup.ConfigSpec{Code: config.Code, Name: config.Name, Value: data})
+	}
+
+	return doc, nil
+}
+
+// taskSpecOf 将任务转换为导出的任务定义
+func taskSpecOf(t *task.Task, names map[uint]string, redact bool) backup.TaskSpec {
+	spec := backup.TaskSpec{
+		Name:               t.Name,
+		MediaType:          t.MediaType,
+		ConfigType:         t.ConfigType,
+		SourcePath:         t.SourcePath,
+		TargetPath:         t.TargetPath,
+		FileSuffix:         t.FileSuffix,
+		Overwrite:          t.Overwrite,
+		Enabled:            t.Enabled,
+		Cron:               t.Cron,
+		DownloadMetadata:   t.DownloadMetadata,
+		DownloadSubtitle:   t.DownloadSubtitle,
+		MetadataExtensions: t.MetadataExtensions,
+		SubtitleExtensions: t.SubtitleExtensions,
+		NotifyEvents:       t.NotifyEvents,
+		NotifyChannels:     t.NotifyChannels,
+		NotifyMinGenerated: t.NotifyMinGenerated,
+		RunOnlyIfIdle:      t.RunOnlyIfIdle,
+		Timezone:           t.Timezone,
+		JitterSeconds:      t.JitterSeconds,
+		BlackoutWindows:    t.BlackoutWindows,
+		MisfirePolicy:      t.MisfirePolicy,
+		MisfireLimit:       t.MisfireLimit,
+		PostActions:        []backup.PostActionSpec{},
+		Hooks:              t.Hooks,
+	}
+	if redact {
+		spec.Hooks = redactHooks(t.Hooks)
+	}
+
+	for _, action := range t.PostActions {
+		actionSpec := backup.PostActionSpec{PostAction: action}
+		if action.Type == task.ActionTypeTask {
+			actionSpec.TaskName = names[action.TaskID]
+		}
+		if redact {
+			// Webhook 地址、请求头和命令中常带有访问令牌，与配置中的密钥一样脱敏
+			if len(action.Headers) > 0 {
+				headers := make(map[string]string, len(action.Headers))
+				for key := range action.Headers {
+					headers[key] = backup.RedactedValue
+				}
+				actionSpec.Headers = headers
+			}
+			if action.URL != "" {
+				actionSpec.URL = backup.RedactedValue
+			}
+			if action.Command != "" {
+				actionSpec.Command = backup.RedactedValue
+			}
+		}
+		spec.PostActions = append(spec.PostActions, actionSpec)
+	}
+	return spec
+}
+
+// hookSlots 按触发点顺序返回各钩子字段的引用，便于统一处理
+func hookSlots(h *task.TaskHooks) []**task.Hook {
+	return []**task.Hook{&h.BeforeTask, &h.AfterTask, &h.BeforeStrm, &h.AfterStrm, &h.BeforeDownload, &h.AfterDownload}
+}
+
+// redactHooks 复制钩子配置并将命令替换为占位值
+func redactHooks(hooks task.TaskHooks) task.TaskHooks {
+	redacted := hooks
+	for _, slot := range hookSlots(&redacted) {
+		if *slot == nil || (*slot).Command == "" {
+			continue
+		}
+		hook := **slot
+		hook.Command = backup.RedactedValue
+		*slot = &hook
+	}
+	return redacted
+}
+
+// redactSecrets 将配置值中的密钥替换为占位值
+func redactSecrets(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if str, ok := child.(string); ok && secretKeys[key] && str != "" {
+				v[key] = backup.RedactedValue
+				continue
+			}
+			redactSecrets(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactSecrets(child)
+		}
+	}
+}
+
+// restoreSecrets 将导入配置中的占位值替换为已保存的密钥，返回无法恢复的密钥数量
+func restoreSecrets(value, existing interface{}) int {
+	missing := 0
+	switch v := value.(type) {
+	case map[string]interface{}:
+		existingMap, _ := existing.(map[string]interface{})
+		for key, child := range v {
+			if str, ok := child.(string); ok && str == backup.RedactedValue {
+				if old, ok := existingMap[key].(string); ok && old != backup.RedactedValue {
+					v[key] = old
+				} else {
+					v[key] = ""
+					missing++
+				}
+				continue
+			}
+			missing += restoreSecrets(child, existingMap[key])
+		}
+	case []interface{}:
+		existingList, _ := existing.([]interface{})
+		for i, child := range v {
+			var old interface{}
+			if i < len(existingList) {
+				old = existingList[i]
+			}
+			missing += restoreSecrets(child, old)
+		}
+	}
+	return missing
+}
+
+// Encode 将导出文档编码为指定格式
+func (s *BackupService) Encode(doc *backup.Document, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if format != backup.FormatYAML {
+		return data, nil
+	}
+
+	// 先转换为通用结构再编码为 YAML，使字段名与 JSON 保持一致
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(value)
+}
+
+// Decode 解析导入文档
+func (s *BackupService) Decode(data []byte, format string) (*backup.Document, error) {
+	if format == backup.FormatYAML {
+		var value interface{}
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("YAML 文档解析失败: %w", err)
+		}
+		converted, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("YAML 文档解析失败: %w", err)
+		}
+		data = converted
+	}
+
+	var doc backup.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("文档解析失败: %w", err)
+	}
+	if doc.Version == 0 {
+		return nil, errors.New("文档缺少版本号，不是有效的导出文档")
+	}
+	if doc.Version > backup.Version {
+		return nil, fmt.Errorf("文档版本 %d 高于当前支持的版本 %d", doc.Version, backup.Version)
+	}
+	return &doc, nil
+}
+
+// Import 导入任务和配置，所有条目通过校验后才会写入数据
+func (s *BackupService) Import(doc *backup.Document, req *backupRequest.ImportReq) (*backupResponse.ImportResp, error) {
+	conflict := req.Conflict
+	if conflict == "" {
+		conflict = backup.ConflictSkip
+	}
+
+	resp := &backupResponse.ImportResp{
+		DryRun:  req.DryRun,
+		Valid:   true,
+		Tasks:   make([]backupResponse.ImportItem, len(doc.Tasks)),
+		Configs: make([]backupResponse.ImportItem, len(doc.Configs)),
+	}
+
+	configPlans, err := s.planConfigs(doc.Configs, conflict, resp)
+	if err != nil {
+		return nil, err
+	}
+	taskPlans, existingTasks, err := s.planTasks(doc.Tasks, conflict, resp)
+	if err != nil {
+		return nil, err
+	}
+	if req.DryRun || !resp.Valid {
+		return resp, nil
+	}
+
+	// 先写入配置，任务执行可能依赖这些配置
+	for _, plan := range configPlans {
+		if plan.existing {
+			err = Config.UpdateConfig(&configRequest.ConfigUpdateReq{ID: plan.config.ID, Value: plan.config.Value})
+		} else {
+			err = Config.Create(&configRequest.ConfigCreateReq{Name: plan.config.Name, Code: plan.config.Code, Value: plan.config.Value})
+		}
+		if err != nil {
+			plan.item.Action = backup.ActionFailed
+			plan.item.Message = err.Error()
+			utils.Error("导入配置失败", "code", plan.config.Code, "error", err.Error())
+		}
+	}
+
+	s.applyTasks(taskPlans, existingTasks)
+	resp.Applied = true
+	utils.Info("导入任务和配置完成", "tasks", len(taskPlans), "configs", len(configPlans), "conflict", conflict)
+	return resp, nil
+}
+
+// planConfigs 校验导入的配置并生成导入计划
+func (s *BackupService) planConfigs(specs []backup.ConfigSpec, conflict string, resp *backupResponse.ImportResp) ([]*configImportPlan, error) {
+	var plans []*configImportPlan
+	seen := make(map[string]bool)
+
+	for i, spec := range specs {
+		item := &resp.Configs[i]
+		item.Name = spec.Code
+
+		invalid := func(message string) {
+			item.Action = backup.ActionInvalid
+			item.Message = message
+			resp.Valid = false
+		}
+		if !backup.IsValidConfigCode(spec.Code) {
+			invalid("不支持导入的配置代码，支持: " + strings.Join(backup.ConfigCodes, ", "))
+			continue
+		}
+		if seen[spec.Code] {
+			invalid("文档中存在重复的配置")
+			continue
+		}
+		seen[spec.Code] = true
+
+		var value map[string]interface{}
+		if err := json.Unmarshal(spec.Value, &value); err != nil || value == nil {
+			invalid("配置值必须是对象")
+			continue
+		}
+		if err := validateConfigValue(spec.Code, spec.Value); err != nil {
+			invalid("配置值无效: " + err.Error())
+			continue
+		}
+
+		existing, err := repository.Config.GetByCode(spec.Code)
+		if err != nil {
+			return nil, err
+		}
+		plan := &configImportPlan{item: item, existing: existing != nil}
+		var existingValue interface{}
+		switch {
+		case existing == nil:
+			item.Action = backup.ActionCreate
+			name := spec.Name
+			if name == "" {
+				name = spec.Code
+			}
+			plan.config = &configs.Config{Name: name, Code: spec.Code}
+		case conflict == backup.ConflictOverwrite:
+			item.Action = backup.ActionOverwrite
+			plan.config = existing
+			_ = json.Unmarshal([]byte(existing.Value), &existingValue)
+		default:
+			item.Action = backup.ActionSkip
+			item.Message = "配置已存在"
+			if conflict == backup.ConflictRename {
+				item.Message = "配置已存在，配置不支持重命名"
+			}
+			continue
+		}
+
+		if restoreSecrets(value, existingValue) > 0 {
+			item.Message = redactedSecretMessage
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		plan.config.Value = string(data)
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// validateConfigValue 按配置代码对应的结构校验配置值
+func validateConfigValue(code string, data []byte) error {
+	var target interface{}
+	switch code {
+	case "ALIST":
+		target = &AListConfig{}
+	case "CLOUD_DRIVE":
+		target = &CloudDriveConfig{}
+	case "STRM":
+		target = &StrmConfig{}
+	case "EMBY":
+		target = &configs.EmbyConfig{}
+	case "NOTIFICATION_SETTINGS":
+		target = &notification.Settings{}
+	default:
+		return nil
+	}
+	return json.Unmarshal(data, target)
+}
+
+// planTasks 校验导入的任务并生成导入计划，同时返回按名称索引的现有任务
+func (s *BackupService) planTasks(specs []backup.TaskSpec, conflict string, resp *backupResponse.ImportResp) ([]*taskImportPlan, map[string]*task.Task, error) {
+	tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{})
+	if err != nil {
+		return nil, nil, err
+	}
+	existingTasks := make(map[string]*task.Task, len(tasks))
+	usedNames := make(map[string]bool, len(tasks)+len(specs))
+	for i := range tasks {
+		existingTasks[tasks[i].Name] = &tasks[i]
+		usedNames[tasks[i].Name] = true
+	}
+	docNames := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		docNames[strings.TrimSpace(spec.Name)] = true
+		usedNames[strings.TrimSpace(spec.Name)] = true
+	}
+
+	var plans []*taskImportPlan
+	seen := make(map[string]bool)
+	finalNames := make(map[string]string, len(specs)) // 文档中的任务名称对应导入后的名称
+	for i, spec := range specs {
+		item := &resp.Tasks[i]
+		name := strings.TrimSpace(spec.Name)
+		item.Name = name
+
+		invalid := func(message string) {
+			item.Action = backup.ActionInvalid
+			item.Message = message
+			resp.Valid = false
+		}
+		if name == "" {
+			invalid("任务名称不能为空")
+			continue
+		}
+		if seen[name] {
+			invalid("文档中存在重名任务")
+			continue
+		}
+		seen[name] = true
+
+		existing := existingTasks[name]
+		plan := &taskImportPlan{item: item, docName: name, task: &task.Task{}}
+		switch {
+		case existing == nil:
+			item.Action = backup.ActionCreate
+		case conflict == backup.ConflictOverwrite:
+			item.Action = backup.ActionOverwrite
+			current := *existing
+			plan.task = &current
+			plan.existing = true
+		case conflict == backup.ConflictRename:
+			item.Action = backup.ActionRename
+			item.NewName = uniqueTaskName(name, usedNames)
+			usedNames[item.NewName] = true
+		default:
+			// 跳过的任务同样校验，避免文档中的错误被忽略
+			item.Action = backup.ActionSkip
+			current := *existing
+			plan.task = &current
+		}
+
+		finalName := name
+		if item.NewName != "" {
+			finalName = item.NewName
+		}
+		if applyTaskSpec(plan, spec, finalName, existing) {
+			item.Message = redactedSecretMessage
+		}
+		if err := validateImportedTask(plan, docNames, existingTasks); err != nil {
+			invalid(err.Error())
+			continue
+		}
+		finalNames[name] = finalName
+		if item.Action == backup.ActionSkip {
+			item.Message = "已存在同名任务"
+			continue
+		}
+		plans = append(plans, plan)
+	}
+
+	checkImportChainCycles(plans, existingTasks, finalNames, resp)
+	return plans, existingTasks, nil
+}
+
+// checkImportChainCycles 按导入后的任务名称构建后续动作的触发关系，将形成循环触发的任务标记为无效
+func checkImportChainCycles(plans []*taskImportPlan, existingTasks map[string]*task.Task, finalNames map[string]string, resp *backupResponse.ImportResp) {
+	names := make(map[uint]string, len(existingTasks))
+	for name, t := range existingTasks {
+		names[t.ID] = name
+	}
+
+	// 现有任务的触发关系，被覆盖的任务以导入的后续动作为准
+	edges := make(map[string][]string, len(existingTasks)+len(plans))
+	for name, t := range existingTasks {
+		for _, action := range t.PostActions {
+			if action.Type == task.ActionTypeTask {
+				edges[name] = append(edges[name], names[action.TaskID])
+			}
+		}
+	}
+	for _, plan := range plans {
+		edges[plan.task.Name] = nil
+		for _, action := range plan.actions {
+			if action.Type != task.ActionTypeTask {
+				continue
+			}
+			next := names[action.TaskID]
+			if action.TaskName != "" {
+				next = action.TaskName
+				if name, ok := finalNames[action.TaskName]; ok {
+					next = name
+				}
+			}
+			edges[plan.task.Name] = append(edges[plan.task.Name], next)
+		}
+	}
+
+	for _, plan := range plans {
+		if plan.item.Action == backup.ActionInvalid {
+			continue
+		}
+		cycle := findCycle(plan.task.Name, edges)
+		if cycle == nil {
+			continue
+		}
+		message := "后续动作形成循环触发: " + strings.Join(cycle, " → ")
+		for _, other := range plans {
+			for _, name := range cycle {
+				if other.task.Name == name && other.item.Action != backup.ActionInvalid {
+					other.item.Action = backup.ActionInvalid
+					other.item.Message = message
+				}
+			}
+		}
+		resp.Valid = false
+	}
+}
+
+// uniqueTaskName 生成不与现有任务及文档中任务重名的名称
+func uniqueTaskName(name string, used map[string]bool) string {
+	for i := 2; ; i++ {
+		candidate := name + " (" + strconv.Itoa(i) + ")"
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+// applyTaskSpec 将导入的任务定义写入计划中的任务，返回是否存在无法恢复的已脱敏字段
+func applyTaskSpec(plan *taskImportPlan, spec backup.TaskSpec, name string, existing *task.Task) bool {
+	t := plan.task
+	t.Name = name
+	t.MediaType = spec.MediaType
+	t.ConfigType = spec.ConfigType
+	t.SourcePath = spec.SourcePath
+	t.TargetPath = spec.TargetPath
+	t.FileSuffix = spec.FileSuffix
+	t.Overwrite = spec.Overwrite
+	t.Enabled = spec.Enabled
+	t.Cron = spec.Cron
+	t.DownloadMetadata = spec.DownloadMetadata
+	t.DownloadSubtitle = spec.DownloadSubtitle
+	t.MetadataExtensions = spec.MetadataExtensions
+	t.SubtitleExtensions = spec.SubtitleExtensions
+	t.NotifyEvents = spec.NotifyEvents
+	t.NotifyChannels = spec.NotifyChannels
+	t.NotifyMinGenerated = spec.NotifyMinGenerated
+	t.RunOnlyIfIdle = spec.RunOnlyIfIdle
+	t.Timezone = spec.Timezone
+	t.JitterSeconds = spec.JitterSeconds
+	t.BlackoutWindows = spec.BlackoutWindows
+	t.MisfirePolicy = spec.MisfirePolicy
+	t.MisfireLimit = spec.MisfireLimit
+	t.Hooks = spec.Hooks
+
+	// 与创建任务时相同的默认值
+	if t.MediaType == "" {
+		t.MediaType = "movie"
+	}
+	if t.ConfigType == "" {
+		t.ConfigType = "alist"
+	}
+	if t.MetadataExtensions == "" {
+		t.MetadataExtensions = "nfo,jpg,png"
+	}
+	if t.SubtitleExtensions == "" {
+		t.SubtitleExtensions = "srt,ass,ssa"
+	}
+	if t.NotifyEvents == "" {
+		t.NotifyEvents = task.DefaultNotifyEvents
+	}
+	if t.MisfirePolicy == "" {
+		t.MisfirePolicy = task.MisfirePolicySkip
+	}
+
+	// 已脱敏的钩子命令沿用同名任务中相同触发点的原值
+	missing := false
+	var existingHooks task.TaskHooks
+	if existing != nil {
+		existingHooks = existing.Hooks
+	}
+	oldSlots := hookSlots(&existingHooks)
+	for i, slot := range hookSlots(&t.Hooks) {
+		if *slot == nil || (*slot).Command != backup.RedactedValue {
+			continue
+		}
+		(*slot).Command = ""
+		if old := *oldSlots[i]; old != nil {
+			(*slot).Command = old.Command
+		}
+		if (*slot).Command == "" {
+			missing = true
+		}
+	}
+
+	// 已脱敏的请求头、Webhook 地址和命令沿用同名任务中相同位置同类型动作的原值
+	plan.actions = make([]backup.PostActionSpec, len(spec.PostActions))
+	for i, action := range spec.PostActions {
+		var old *task.PostAction
+		if existing != nil && i < len(existing.PostActions) && existing.PostActions[i].Type == action.Type {
+			old = &existing.PostActions[i]
+		}
+		if action.URL == backup.RedactedValue {
+			action.URL = ""
+			if old != nil {
+				action.URL = old.URL
+			}
+			missing = missing || action.URL == ""
+		}
+		if action.Command == backup.RedactedValue {
+			action.Command = ""
+			if old != nil {
+				action.Command = old.Command
+			}
+			missing = missing || action.Command == ""
+		}
+		if len(action.Headers) > 0 {
+			headers := make(map[string]string, len(action.Headers))
+			for key, value := range action.Headers {
+				if value == backup.RedactedValue {
+					value = ""
+					if old != nil {
+						value = old.Headers[key]
+					}
+					if value == "" {
+						missing = true
+					}
+				}
+				headers[key] = value
+			}
+			action.Headers = headers
+		}
+		plan.actions[i] = action
+	}
+	t.PostActions = postActionsWithoutNamedTasks(plan.actions)
+	return missing
+}
+
+// postActionsWithoutNamedTasks 去除按名称关联任务的后续动作，这些动作需要在任务写入后才能解析出任务ID
+func postActionsWithoutNamedTasks(actions []backup.PostActionSpec) task.PostActions {
+	var result task.PostActions
+	for _, action := range actions {
+		if action.Type == task.ActionTypeTask && action.TaskName != "" {
+			continue
+		}
+		result = append(result, action.PostAction)
+	}
+	return result
+}
+
+// validateImportedTask 校验导入的任务，字段规则与创建任务的请求一致
+func validateImportedTask(plan *taskImportPlan, docNames map[string]bool, existingTasks map[string]*task.Task) error {
+	t := plan.task
+	if len([]rune(t.Name)) > 100 {
+		return errors.New("任务名称不能超过 100 个字符")
+	}
+	if t.MediaType != "movie" && t.MediaType != "tv" {
+		return fmt.Errorf("媒体类型无效: %s", t.MediaType)
+	}
+	if t.ConfigType != "alist" && t.ConfigType != "clouddrive" && t.ConfigType != "local" {
+		return fmt.Errorf("配置类型无效: %s", t.ConfigType)
+	}
+	if t.SourcePath == "" || t.TargetPath == "" || t.FileSuffix == "" {
+		return errors.New("源路径、目标路径和文件后缀不能为空")
+	}
+
+	for i, action := range plan.actions {
+		if action.Type != task.ActionTypeTask || action.TaskName == "" {
+			continue
+		}
+		if action.TaskName == plan.docName {
+			return fmt.Errorf("第 %d 个后续动作不能触发任务自身", i+1)
+		}
+		if !docNames[action.TaskName] && existingTasks[action.TaskName] == nil {
+			return fmt.Errorf("第 %d 个后续动作触发的任务 %s 不存在", i+1, action.TaskName)
+		}
+	}
+
+	if err := validateNotifyRule(t); err != nil {
+		return err
+	}
+	if err := validateSchedule(t); err != nil {
+		return err
+	}
+	if err := validatePostActions(t); err != nil {
+		return err
+	}
+	return validateHooks(t)
+}
+
+// applyTasks 写入任务，解析按名称关联的后续动作并注册到调度器
+func (s *BackupService) applyTasks(plans []*taskImportPlan, existingTasks map[string]*task.Task) {
+	taskIDs := make(map[string]uint, len(existingTasks)+len(plans))
+	for name, t := range existingTasks {
+		taskIDs[name] = t.ID
+	}
+
+	var written []*taskImportPlan
+	for _, plan := range plans {
+		var err error
+		if plan.existing {
+			err = repository.Task.Update(plan.task)
+		} else {
+			err = repository.Task.Create(plan.task)
+		}
+		if err != nil {
+			plan.item.Action = backup.ActionFailed
+			plan.item.Message = err.Error()
+			utils.Error("导入任务失败", "name", plan.task.Name, "error", err.Error())
+			continue
+		}
+		taskIDs[plan.docName] = plan.task.ID
+		written = append(written, plan)
+	}
+
+	for _, plan := range written {
+		if len(postActionsWithoutNamedTasks(plan.actions)) != len(plan.actions) {
+			actions := make(task.PostActions, 0, len(plan.actions))
+			for _, action := range plan.actions {
+				if action.Type == task.ActionTypeTask && action.TaskName != "" {
+					action.TaskID = taskIDs[action.TaskName]
+					if action.TaskID == 0 {
+						continue
+					}
+				}
+				actions = append(actions, action.PostAction)
+			}
+			plan.task.PostActions = actions
+			if err := repository.Task.Update(plan.task); err != nil {
+				plan.item.Message = "关联后续动作失败: " + err.Error()
+				utils.Error("导入任务的后续动作失败", "task_id", plan.task.ID, "error", err.Error())
+			}
+		}
+
+		// 注册到调度器，未启用或未设置 cron 的任务由调度器自行忽略
+		scheduler := GetTaskScheduler()
+		register := scheduler.AddTask
+		if plan.existing {
+			register = scheduler.UpdateTask
+		}
+		if err := register(plan.task); err != nil {
+			utils.Warn("导入的任务注册到调度器失败", "task_id", plan.task.ID, "error", err.Error())
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MccRay-s/alist2strm/model/backup"
+	"github.com/MccRay-s/alist2strm/model/task"
+)
+
+func TestRedactAndRestoreSecrets(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       map[string]interface{}
+		existing    map[string]interface{}
+		redacted    map[string]interface{}
+		restored    map[string]interface{}
+		wantMissing int
+	}{
+		{
+			name:        "顶层密钥",
+			value:       map[string]interface{}{"url": "http://alist:5244", "token": "abc", "enabled": true},
+			existing:    map[string]interface{}{"url": "http://alist:5244", "token": "abc", "enabled": true},
+			redacted:    map[string]interface{}{"url": backup.RedactedValue, "token": backup.RedactedValue, "enabled": true},
+			restored:    map[string]interface{}{"url": "http://alist:5244", "token": "abc", "enabled": true},
+			wantMissing: 0,
+		},
+		{
+			name:        "空值不脱敏",
+			value:       map[string]interface{}{"password": "", "username": "admin"},
+			redacted:    map[string]interface{}{"password": "", "username": "admin"},
+			restored:    map[string]interface{}{"password": "", "username": "admin"},
+			wantMissing: 0,
+		},
+		{
+			name: "嵌套的渠道配置",
+			value: map[string]interface{}{
+				"channels": map[string]interface{}{
+					"telegram": map[string]interface{}{"botToken": "bot:1", "chatId": "42"},
+					"bark":     map[string]interface{}{"deviceKey": "device"},
+				},
+			},
+			existing: map[string]interface{}{
+				"channels": map[string]interface{}{
+					"telegram": map[string]interface{}{"botToken": "bot:1", "chatId": "42"},
+					"bark":     map[string]interface{}{"deviceKey": "device"},
+				},
+			},
+			redacted: map[string]interface{}{
+				"channels": map[string]interface{}{
+					"telegram": map[string]interface{}{"botToken": backup.RedactedValue, "chatId": "42"},
+					"bark":     map[string]interface{}{"deviceKey": backup.RedactedValue},
+				},
+			},
+			restored: map[string]interface{}{
+				"channels": map[string]interface{}{
+					"telegram": map[string]interface{}{"botToken": "bot:1", "chatId": "42"},
+					"bark":     map[string]interface{}{"deviceKey": "device"},
+				},
+			},
+			wantMissing: 0,
+		},
+		{
+			name:        "列表中的密钥",
+			value:       map[string]interface{}{"servers": []interface{}{map[string]interface{}{"secret": "a"}, map[string]interface{}{"secret": "b"}}},
+			existing:    map[string]interface{}{"servers": []interface{}{map[string]interface{}{"secret": "a"}}},
+			redacted:    map[string]interface{}{"servers": []interface{}{map[string]interface{}{"secret": backup.RedactedValue}, map[string]interface{}{"secret": backup.RedactedValue}}},
+			restored:    map[string]interface{}{"servers": []interface{}{map[string]interface{}{"secret": "a"}, map[string]interface{}{"secret": ""}}},
+			wantMissing: 1,
+		},
+		{
+			name:        "没有已保存的配置",
+			value:       map[string]interface{}{"sendKey": "key", "webhookUrl": "https://example.com/hook"},
+			redacted:    map[string]interface{}{"sendKey": backup.RedactedValue, "webhookUrl": backup.RedactedValue},
+			restored:    map[string]interface{}{"sendKey": "", "webhookUrl": ""},
+			wantMissing: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redactSecrets(tt.value)
+			if !reflect.DeepEqual(tt.value, tt.redacted) {
+				t.Fatalf("redactSecrets() = %v, want %v", tt.value, tt.redacted)
+			}
+			var existing interface{}
+			if tt.existing != nil {
+				existing = tt.existing
+			}
+			if missing := restoreSecrets(tt.value, existing); missing != tt.wantMissing {
+				t.Errorf("restoreSecrets() missing = %d, want %d", missing, tt.wantMissing)
+			}
+			if !reflect.DeepEqual(tt.value, tt.restored) {
+				t.Errorf("restoreSecrets() = %v, want %v", tt.value, tt.restored)
+			}
+		})
+	}
+}
+
+// secretTask 构造带有各类敏感字段的任务
+func secretTask() *task.Task {
+	return &task.Task{
+		Name:       "电影",
+		SourcePath: "/media/movies",
+		TargetPath: "/strm/movies",
+		FileSuffix: "mkv",
+		PostActions: task.PostActions{
+			{Type: task.ActionTypeWebhook, URL: "https://example.com/hook?token=abc", Headers: map[string]string{"Authorization": "Bearer abc"}},
+			{Type: task.ActionTypeCommand, Command: "curl -H 'X-Token: abc' http://localhost"},
+			{Type: task.ActionTypeEmbyRefresh, LibraryIDs: []string{"1"}},
+		},
+		Hooks: task.TaskHooks{
+			BeforeTask: &task.Hook{Command: "mount-check --token abc", Timeout: 10},
+			AfterStrm:  &task.Hook{Command: "notify {{.TargetPath}}", OnFailure: task.HookFailureFailFile},
+		},
+	}
+}
+
+func TestTaskSpecOfRedact(t *testing.T) {
+	source := secretTask()
+
+	plain := taskSpecOf(source, nil, false)
+	if !reflect.DeepEqual(plain.Hooks, source.Hooks) {
+		t.Errorf("未脱敏导出的钩子 = %+v, want %+v", plain.Hooks, source.Hooks)
+	}
+	for i, action := range plain.PostActions {
+		if !reflect.DeepEqual(action.PostAction, source.PostActions[i]) {
+			t.Errorf("未脱敏导出的动作[%d] = %+v, want %+v", i, action.PostAction, source.PostActions[i])
+		}
+	}
+
+	spec := taskSpecOf(source, nil, true)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "Webhook 地址", got: spec.PostActions[0].URL, want: backup.RedactedValue},
+		{name: "Webhook 请求头", got: spec.PostActions[0].Headers["Authorization"], want: backup.RedactedValue},
+		{name: "动作命令", got: spec.PostActions[1].Command, want: backup.RedactedValue},
+		{name: "未设置的命令保持为空", got: spec.PostActions[0].Command, want: ""},
+		{name: "前置钩子命令", got: spec.Hooks.BeforeTask.Command, want: backup.RedactedValue},
+		{name: "生成后钩子命令", got: spec.Hooks.AfterStrm.Command, want: backup.RedactedValue},
+		{name: "原任务的地址不变", got: source.PostActions[0].URL, want: "https://example.com/hook?token=abc"},
+		{name: "原任务的请求头不变", got: source.PostActions[0].Headers["Authorization"], want: "Bearer abc"},
+		{name: "原任务的钩子命令不变", got: source.Hooks.BeforeTask.Command, want: "mount-check --token abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+	if spec.Hooks.BeforeTask.Timeout != 10 || spec.Hooks.AfterStrm.OnFailure != task.HookFailureFailFile {
+		t.Errorf("脱敏后钩子的其他字段应保持不变: %+v %+v", spec.Hooks.BeforeTask, spec.Hooks.AfterStrm)
+	}
+	if !reflect.DeepEqual(spec.PostActions[2].LibraryIDs, []string{"1"}) {
+		t.Errorf("脱敏后动作的其他字段应保持不变: %+v", spec.PostActions[2])
+	}
+}
+
+func TestTaskSpecImportRoundTrip(t *testing.T) {
+	withoutHook := secretTask()
+	withoutHook.Hooks.BeforeTask = nil
+	otherType := secretTask()
+	otherType.PostActions[0].Type = task.ActionTypeEmbyRefresh
+
+	tests := []struct {
+		name        string
+		format      string
+		existing    *task.Task
+		wantMissing bool
+		want        func(t *testing.T, got *task.Task)
+	}{
+		{
+			name:     "JSON 导入覆盖同名任务",
+			format:   backup.FormatJSON,
+			existing: secretTask(),
+			want: func(t *testing.T, got *task.Task) {
+				source := secretTask()
+				if !reflect.DeepEqual(got.PostActions, source.PostActions) {
+					t.Errorf("恢复后的动作 = %+v, want %+v", got.PostActions, source.PostActions)
+				}
+				if !reflect.DeepEqual(got.Hooks, source.Hooks) {
+					t.Errorf("恢复后的钩子 = %+v, want %+v", got.Hooks, source.Hooks)
+				}
+			},
+		},
+		{
+			name:     "YAML 导入覆盖同名任务",
+			format:   backup.FormatYAML,
+			existing: secretTask(),
+			want: func(t *testing.T, got *task.Task) {
+				source := secretTask()
+				if !reflect.DeepEqual(got.PostActions, source.PostActions) {
+					t.Errorf("恢复后的动作 = %+v, want %+v", got.PostActions, source.PostActions)
+				}
+				if !reflect.DeepEqual(got.Hooks, source.Hooks) {
+					t.Errorf("恢复后的钩子 = %+v, want %+v", got.Hooks, source.Hooks)
+				}
+			},
+		},
+		{
+			name:        "没有同名任务",
+			format:      backup.FormatJSON,
+			wantMissing: true,
+			want: func(t *testing.T, got *task.Task) {
+				if got.PostActions[0].URL != "" || got.PostActions[0].Headers["Authorization"] != "" || got.PostActions[1].Command != "" {
+					t.Errorf("无法恢复的值应清空: %+v", got.PostActions)
+				}
+				if got.Hooks.BeforeTask.Command != "" || got.Hooks.AfterStrm.Command != "" {
+					t.Errorf("无法恢复的钩子命令应清空: %+v %+v", got.Hooks.BeforeTask, got.Hooks.AfterStrm)
+				}
+			},
+		},
+		{
+			name:        "同名任务缺少对应钩子",
+			format:      backup.FormatJSON,
+			existing:    withoutHook,
+			wantMissing: true,
+			want: func(t *testing.T, got *task.Task) {
+				if got.Hooks.BeforeTask.Command != "" {
+					t.Errorf("前置钩子命令 = %q, want 空", got.Hooks.BeforeTask.Command)
+				}
+				if got.Hooks.AfterStrm.Command != "notify {{.TargetPath}}" {
+					t.Errorf("生成后钩子命令 = %q, want 已恢复", got.Hooks.AfterStrm.Command)
+				}
+			},
+		},
+		{
+			name:        "同位置动作类型不同",
+			format:      backup.FormatJSON,
+			existing:    otherType,
+			wantMissing: true,
+			want: func(t *testing.T, got *task.Task) {
+				if got.PostActions[0].URL != "" || got.PostActions[0].Headers["Authorization"] != "" {
+					t.Errorf("类型不同的动作不应沿用原值: %+v", got.PostActions[0])
+				}
+				if got.PostActions[1].Command != "curl -H 'X-Token: abc' http://localhost" {
+					t.Errorf("动作命令 = %q, want 已恢复", got.PostActions[1].Command)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &backup.Document{
+				Version:  backup.Version,
+				Redacted: true,
+				Tasks:    []backup.TaskSpec{taskSpecOf(secretTask(), nil, true)},
+			}
+			data, err := Backup.Encode(doc, tt.format)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			decoded, err := Backup.Decode(data, tt.format)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			plan := &taskImportPlan{task: &task.Task{}}
+			missing := applyTaskSpec(plan, decoded.Tasks[0], decoded.Tasks[0].Name, tt.existing)
+			if missing != tt.wantMissing {
+				t.Errorf("applyTaskSpec() missing = %v, want %v", missing, tt.wantMissing)
+			}
+			tt.want(t, plan.task)
+		})
+	}
+}
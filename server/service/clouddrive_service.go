@@ -9,6 +9,8 @@ import (
 	"github.com/MccRay-s/alist2strm/repository"
 	"github.com/MccRay-s/alist2strm/utils"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // CloudDriveConfig CloudDrive 配置结构
@@ -84,6 +86,45 @@ func (s *CloudDriveService) loadConfigAndInitClient() error {
 	return nil
 }
 
+// IsConfigured 检查是否已配置 CloudDrive
+func (s *CloudDriveService) IsConfigured() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config != nil
+}
+
+// CheckHealth 检查 CloudDrive 登录状态和账号状态，客户端未登录时先尝试重新登录
+func (s *CloudDriveService) CheckHealth() error {
+	s.mu.RLock()
+	client := s.client
+	s.mu.RUnlock()
+
+	if client == nil {
+		if err := s.loadConfigAndInitClient(); err != nil {
+			return err
+		}
+		s.mu.RLock()
+		client = s.client
+		s.mu.RUnlock()
+		if client == nil {
+			return fmt.Errorf("未配置 CloudDrive，请先完成配置")
+		}
+	}
+
+	systemInfo, err := client.GetSystemInfo()
+	if err != nil {
+		return fmt.Errorf("获取 CloudDrive 系统信息失败: %w", err)
+	}
+	if !systemInfo.IsLogin {
+		return fmt.Errorf("CloudDrive 未登录")
+	}
+
+	if _, err := client.GetAccountStatus(); err != nil {
+		return fmt.Errorf("获取 CloudDrive 账号状态失败: %w", err)
+	}
+	return nil
+}
+
 // ListFiles 获取指定目录下的文件列表
 func (s *CloudDriveService) ListFiles(path string) ([]AListFile, error) {
 	s.mu.RLock()
@@ -98,6 +139,9 @@ func (s *CloudDriveService) ListFiles(path string) ([]AListFile, error) {
 	// getImmediateSubFiles 已经处理了流式数据的接收
 	cloudDriveFiles, err := client.GetImmediateSubFiles(path, false, false)
 	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("从 CloudDrive 获取文件列表失败 [%s]: %w: %w", path, ErrSourcePathNotFound, err)
+		}
 		return nil, fmt.Errorf("从 CloudDrive 获取文件列表失败 [%s]: %w", path, err)
 	}
 	if cloudDriveFiles == nil {
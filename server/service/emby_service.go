@@ -529,6 +529,58 @@ func (s *EmbyService) GetImage(itemId, imageType string, tag string, maxWidth, m
 	return imageData, contentType, nil
 }
 
+// ErrEmbyItemNotFound Emby 中尚未找到对应的媒体（通常是还未完成入库）
+var ErrEmbyItemNotFound = errors.New("emby 中未找到对应的媒体")
+
+// IsConfigured 检查 Emby 是否已配置
+func (s *EmbyService) IsConfigured() bool {
+	_, err := s.getEmbyConfig()
+	return err == nil
+}
+
+// GetWebURL 获取 Emby 网页端地址，未配置时返回空字符串
+func (s *EmbyService) GetWebURL() string {
+	embyConfig, err := s.getEmbyConfig()
+	if err != nil {
+		return ""
+	}
+	return embyConfig.EmbyServer + "/web/index.html"
+}
+
+// GetPosterByLocalPaths 根据本地 STRM 文件路径在最近入库的媒体中查找海报
+// 剧集使用所属剧集的海报；媒体尚未入库时返回 ErrEmbyItemNotFound
+func (s *EmbyService) GetPosterByLocalPaths(localPaths []string) ([]byte, string, error) {
+	if len(localPaths) == 0 {
+		return nil, "", ErrEmbyItemNotFound
+	}
+
+	latestMedia, err := s.GetLatestMedia(50)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, media := range latestMedia {
+		if media.Path == "" {
+			continue
+		}
+		mediaPath := strings.TrimRight(filepath.ToSlash(media.Path), "/")
+		for _, localPath := range localPaths {
+			localPath = filepath.ToSlash(localPath)
+			if localPath != mediaPath && !strings.HasPrefix(localPath, mediaPath+"/") {
+				continue
+			}
+
+			itemID := media.ID
+			if media.SeriesId != "" {
+				itemID = media.SeriesId
+			}
+			return s.GetImage(itemID, "Primary", "", 600, 0, 90)
+		}
+	}
+
+	return nil, "", ErrEmbyItemNotFound
+}
+
 // EmbyQueryResult 表示Emby用户查询结果
 type EmbyQueryResult struct {
 	Items            []EmbyUser `json:"Items"`
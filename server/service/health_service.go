@@ -0,0 +1,354 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/config"
+	"github.com/MccRay-s/alist2strm/model/health"
+	healthRequest "github.com/MccRay-s/alist2strm/model/health/request"
+	healthResponse "github.com/MccRay-s/alist2strm/model/health/response"
+	"github.com/MccRay-s/alist2strm/model/notification"
+	taskRequest "github.com/MccRay-s/alist2strm/model/task/request"
+	"github.com/MccRay-s/alist2strm/repository"
+	"github.com/MccRay-s/alist2strm/utils"
+)
+
+const (
+	// healthCheckTimeout 单个源站检查的超时时间
+	healthCheckTimeout = 30 * time.Second
+	// minHealthCheckInterval 最小检查间隔
+	minHealthCheckInterval = 30 * time.Second
+)
+
+// healthSources 需要检查的源站，按显示顺序排列
+var healthSources = []string{health.SourceAList, health.SourceCloudDrive, health.SourceEmby}
+
+// sourceState 源站在内存中的检查状态
+type sourceState struct {
+	status              string
+	since               time.Time // 进入当前状态的时间
+	lastCheckAt         time.Time
+	latencyMs           int64
+	message             string
+	consecutiveFailures int
+	firstFailureAt      time.Time // 本轮连续失败的开始时间
+}
+
+type HealthService struct {
+	mu        sync.RWMutex
+	states    map[string]*sourceState
+	checkMu   sync.Mutex // 避免定时检查与手动检查同时进行
+	startOnce sync.Once
+}
+
+// 包级别的全局实例
+var Health = &HealthService{states: make(map[string]*sourceState)}
+
+// getHealthConfig 获取健康检查配置
+func (s *HealthService) getHealthConfig() config.HealthCheckConfig {
+	cfg := config.HealthCheckConfig{Enabled: true, Interval: 300, FailureThreshold: 2, RetentionDays: 7}
+	if config.GlobalConfig != nil {
+		cfg = config.GlobalConfig.HealthCheck
+	}
+	if time.Duration(cfg.Interval)*time.Second < minHealthCheckInterval {
+		cfg.Interval = int(minHealthCheckInterval / time.Second)
+	}
+	if cfg.FailureThreshold < 1 {
+		cfg.FailureThreshold = 1
+	}
+	if cfg.RetentionDays < 1 {
+		cfg.RetentionDays = 7
+	}
+	return cfg
+}
+
+// StartMonitor 启动后台健康检查
+func (s *HealthService) StartMonitor() {
+	cfg := s.getHealthConfig()
+	if !cfg.Enabled {
+		utils.Info("源站健康检查已禁用")
+		return
+	}
+
+	s.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(time.Duration(cfg.Interval) * time.Second)
+			defer ticker.Stop()
+
+			for {
+				s.CheckAll()
+				if purged, err := repository.Health.DeleteBefore(time.Now().AddDate(0, 0, -cfg.RetentionDays)); err != nil {
+					utils.Error("清理过期健康检查记录失败", "error", err.Error())
+				} else if purged > 0 {
+					utils.Info("已清理过期健康检查记录", "count", purged)
+				}
+				<-ticker.C
+			}
+		}()
+		utils.Info("源站健康检查已启动", "interval", cfg.Interval, "failure_threshold", cfg.FailureThreshold)
+	})
+}
+
+// CheckAll 立即检查所有源站并返回最新状态
+func (s *HealthService) CheckAll() *healthResponse.HealthStatusResp {
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, source := range healthSources {
+		wg.Add(1)
+		go func(source string) {
+			defer wg.Done()
+			s.checkSource(source)
+		}(source)
+	}
+	wg.Wait()
+
+	return s.GetStatus()
+}
+
+// GetStatus 获取各源站当前状态
+func (s *HealthService) GetStatus() *healthResponse.HealthStatusResp {
+	cfg := s.getHealthConfig()
+	resp := &healthResponse.HealthStatusResp{
+		Enabled:  cfg.Enabled,
+		Interval: cfg.Interval,
+		Sources:  make([]healthResponse.SourceStatus, 0, len(healthSources)),
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, source := range healthSources {
+		status := healthResponse.SourceStatus{
+			Source: source,
+			Name:   health.SourceName(source),
+			Status: health.StatusUnknown,
+		}
+		if state, exists := s.states[source]; exists {
+			status.Status = state.status
+			status.LatencyMs = state.latencyMs
+			status.Message = state.message
+			status.ConsecutiveFailures = state.consecutiveFailures
+			if !state.since.IsZero() {
+				since := state.since
+				status.Since = &since
+			}
+			if !state.lastCheckAt.IsZero() {
+				lastCheckAt := state.lastCheckAt
+				status.LastCheckAt = &lastCheckAt
+			}
+		}
+		resp.Sources = append(resp.Sources, status)
+	}
+	return resp
+}
+
+// GetHistory 分页获取健康检查记录
+func (s *HealthService) GetHistory(req *healthRequest.HealthHistoryReq) (*healthResponse.HealthHistoryResp, error) {
+	checks, total, err := repository.Health.List(req)
+	if err != nil {
+		utils.Error("获取健康检查记录失败", "error", err.Error())
+		return nil, fmt.Errorf("获取健康检查记录失败: %w", err)
+	}
+
+	return &healthResponse.HealthHistoryResp{
+		List:  checks,
+		Total: total,
+		Page:  req.Page,
+		Size:  req.PageSize,
+	}, nil
+}
+
+// IsSourceHealthy 判断任务配置类型对应的源站是否可用，尚未判定为异常的源站视为可用
+func (s *HealthService) IsSourceHealthy(configType string) (bool, string) {
+	if configType != health.SourceAList && configType != health.SourceCloudDrive {
+		return true, ""
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, exists := s.states[configType]
+	if !exists || state.status != health.StatusDown {
+		return true, ""
+	}
+	return false, state.message
+}
+
+// checkSource 检查单个源站，记录结果并在状态变化时发送通知
+func (s *HealthService) checkSource(source string) {
+	configured, probe := s.probe(source)
+	if !configured {
+		s.mu.Lock()
+		if state, exists := s.states[source]; !exists || state.status != health.StatusUnconfigured {
+			s.states[source] = &sourceState{status: health.StatusUnconfigured, since: time.Now()}
+		}
+		s.mu.Unlock()
+		return
+	}
+
+	start := time.Now()
+	err := runHealthProbe(probe)
+	latency := time.Since(start).Milliseconds()
+
+	check := &health.HealthCheck{Source: source, Healthy: err == nil, LatencyMs: latency}
+	if err != nil {
+		check.Message = err.Error()
+	}
+	if createErr := repository.Health.Create(check); createErr != nil {
+		utils.Error("保存健康检查记录失败", "source", source, "error", createErr.Error())
+	}
+
+	threshold := s.getHealthConfig().FailureThreshold
+	now := time.Now()
+
+	s.mu.Lock()
+	state, exists := s.states[source]
+	if !exists {
+		state = &sourceState{status: health.StatusUnknown, since: now}
+		s.states[source] = state
+	}
+	previous := state.status
+	downSince := state.since
+	state.lastCheckAt = now
+	state.latencyMs = latency
+
+	if err == nil {
+		state.message = ""
+		state.consecutiveFailures = 0
+		if previous != health.StatusUp {
+			state.status = health.StatusUp
+			state.since = now
+		}
+	} else {
+		state.message = err.Error()
+		if state.consecutiveFailures == 0 {
+			state.firstFailureAt = now
+		}
+		state.consecutiveFailures++
+		if state.consecutiveFailures >= threshold && previous != health.StatusDown {
+			state.status = health.StatusDown
+			state.since = state.firstFailureAt
+		}
+	}
+	current := *state
+	s.mu.Unlock()
+
+	switch {
+	case previous != health.StatusDown && current.status == health.StatusDown:
+		utils.Warn("源站状态异常", "source", source, "failures", current.consecutiveFailures, "error", current.message)
+		s.notify(notification.TemplateTypeSourceDown, source, &current, time.Time{})
+	case previous == health.StatusDown && current.status == health.StatusUp:
+		utils.Info("源站已恢复", "source", source, "latency_ms", latency, "down_since", downSince.Format("2006-01-02 15:04:05"))
+		s.notify(notification.TemplateTypeSourceRecovered, source, &current, downSince)
+	case err != nil:
+		utils.Warn("源站健康检查失败", "source", source, "failures", current.consecutiveFailures, "error", current.message)
+	}
+}
+
+// probe 返回源站是否已配置以及对应的检查方法
+func (s *HealthService) probe(source string) (bool, func() error) {
+	switch source {
+	case health.SourceAList:
+		alistService := GetAListService()
+		if alistService == nil || !alistService.IsConfigured() {
+			return false, nil
+		}
+		return true, alistService.TestConnection
+	case health.SourceCloudDrive:
+		cloudDriveService := GetCloudDriveService()
+		if cloudDriveService == nil || !cloudDriveService.IsConfigured() {
+			return false, nil
+		}
+		return true, cloudDriveService.CheckHealth
+	case health.SourceEmby:
+		if !Emby.IsConfigured() {
+			return false, nil
+		}
+		return true, func() error {
+			result, err := Emby.TestConnection()
+			if err != nil {
+				return err
+			}
+			if !result.Connected {
+				return errors.New(result.Error)
+			}
+			return nil
+		}
+	default:
+		return false, nil
+	}
+}
+
+// runHealthProbe 执行检查，超时视为失败
+func runHealthProbe(probe func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- probe()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(healthCheckTimeout):
+		return fmt.Errorf("检查超时（%s）", healthCheckTimeout)
+	}
+}
+
+// notify 发送源站异常或恢复通知
+func (s *HealthService) notify(templateType notification.TemplateType, source string, state *sourceState, downSince time.Time) {
+	event := &notification.SourceHealthEvent{
+		Source:     source,
+		SourceName: health.SourceName(source),
+		Message:    state.message,
+		Failures:   state.consecutiveFailures,
+		LatencyMs:  state.latencyMs,
+	}
+	if templateType == notification.TemplateTypeSourceDown {
+		event.DownSince = state.since.Format("2006-01-02 15:04:05")
+		event.AffectedTasks = affectedTaskNames(source)
+	} else {
+		event.DownSince = downSince.Format("2006-01-02 15:04:05")
+		event.DownDuration = formatHealthDuration(state.lastCheckAt.Sub(downSince))
+	}
+
+	if err := GetNotificationService().SendSourceHealthNotification(templateType, event); err != nil {
+		utils.Error("发送源站状态通知失败", "source", source, "error", err.Error())
+	}
+}
+
+// affectedTaskNames 获取使用该源站的已启用任务名称
+func affectedTaskNames(source string) []string {
+	enabled := true
+	tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{Enabled: &enabled})
+	if err != nil {
+		utils.Error("获取已启用任务失败", "error", err.Error())
+		return nil
+	}
+
+	var names []string
+	for _, t := range tasks {
+		if t.ConfigType == source {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+// formatHealthDuration 格式化异常持续时长
+func formatHealthDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	seconds := int(d.Seconds()) % 60
+	switch {
+	case hours > 0:
+		return fmt.Sprintf("%d小时%d分", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%d分%d秒", minutes, seconds)
+	default:
+		return fmt.Sprintf("%d秒", seconds)
+	}
+}
@@ -0,0 +1,123 @@
+package notification_channel
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+// BarkChannel Bark 推送渠道
+type BarkChannel struct {
+	*BaseChannel
+	serverURL    string
+	deviceKey    string
+	group        string
+	sound        string
+	icon         string
+	successLevel string
+	failureLevel string
+}
+
+// GetType 获取渠道类型
+func (c *BarkChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypeBark
+}
+
+// NewBarkChannel 创建 Bark 推送渠道
+func NewBarkChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &BarkChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeBark)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	deviceKey := channelConfig.Config["deviceKey"]
+	if deviceKey == "" {
+		logger.Warn("Bark 配置不完整，通知功能已禁用")
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	serverURL := strings.TrimRight(channelConfig.Config["serverUrl"], "/")
+	if serverURL == "" {
+		serverURL = "https://api.day.app"
+	}
+
+	// 级别可选值: active, timeSensitive, passive, critical
+	successLevel := channelConfig.Config["successLevel"]
+	if successLevel == "" {
+		successLevel = "active"
+	}
+	failureLevel := channelConfig.Config["failureLevel"]
+	if failureLevel == "" {
+		failureLevel = "timeSensitive"
+	}
+
+	channel.serverURL = serverURL
+	channel.deviceKey = deviceKey
+	channel.group = channelConfig.Config["group"]
+	channel.sound = channelConfig.Config["sound"]
+	channel.icon = channelConfig.Config["icon"]
+	channel.successLevel = successLevel
+	channel.failureLevel = failureLevel
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *BarkChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("bark 通知渠道未启用")
+	}
+
+	templateContent := c.templateContent(templateType, string(notification.ChannelTypeBark))
+	if templateContent == "" {
+		return fmt.Errorf("未找到模板: %s", templateType)
+	}
+
+	message, err := renderTemplate("bark", templateContent, data)
+	if err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	payload := map[string]string{
+		"device_key": c.deviceKey,
+		"title":      templateTitle(templateType),
+		"body":       message,
+		"level":      pickByTemplate(templateType, c.successLevel, c.failureLevel),
+	}
+	if c.group != "" {
+		payload["group"] = c.group
+	}
+	if c.sound != "" {
+		payload["sound"] = c.sound
+	}
+	if c.icon != "" {
+		payload["icon"] = c.icon
+	}
+
+	respBody, err := postJSON(c.serverURL+"/push", payload)
+	if err != nil {
+		return fmt.Errorf("发送 Bark 推送失败: %w", err)
+	}
+
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("解析 Bark 响应失败: %w", err)
+	}
+	if result.Code != 200 {
+		return fmt.Errorf("bark API错误: %s (%d)", result.Message, result.Code)
+	}
+
+	return nil
+}
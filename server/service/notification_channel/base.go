@@ -1,6 +1,14 @@
 package notification_channel
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
 	"github.com/MccRay-s/alist2strm/model/notification"
 	"go.uber.org/zap"
 )
@@ -34,3 +42,104 @@ func NewBaseChannel(logger *zap.Logger, settings *notification.Settings) *BaseCh
 		settings: settings,
 	}
 }
+
+// templateContent 按模板类型和模板键获取模板内容，未配置时回退到默认模板
+func (c *BaseChannel) templateContent(templateType notification.TemplateType, key string) string {
+	if templateConfig, exists := c.settings.Templates[string(templateType)]; exists {
+		if content := templateConfig.Get(key); content != "" {
+			return content
+		}
+	}
+	return notification.DefaultSettings().Templates[string(templateType)].Get(key)
+}
+
+// renderTemplate 渲染文本模板
+func renderTemplate(name, templateContent string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Parse(templateContent)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// templateTitle 获取模板类型对应的通知标题
+func templateTitle(templateType notification.TemplateType) string {
+	switch templateType {
+	case notification.TemplateTypeTaskComplete:
+		return "任务完成通知 ✅"
+	case notification.TemplateTypeTaskFailed:
+		return "任务失败通知 ❌"
+	case notification.TemplateTypeNewMedia:
+		return "新增媒体通知 🎬"
+	case notification.TemplateTypeDigest:
+		return "通知摘要 📊"
+	case notification.TemplateTypeSourceDown:
+		return "源站异常告警 🚨"
+	case notification.TemplateTypeSourceRecovered:
+		return "源站已恢复 ✅"
+	default:
+		return "alist2strm 通知"
+	}
+}
+
+// posterOf 获取通知数据中已获取的海报图片
+func posterOf(data interface{}) ([]byte, string) {
+	if taskData, ok := data.(*notification.TaskNotificationData); ok && len(taskData.Poster) > 0 {
+		return taskData.Poster, taskData.PosterType
+	}
+	return nil, ""
+}
+
+// pickByTemplate 根据模板类型在成功与失败两个取值之间选择，用于优先级/级别映射
+func pickByTemplate(templateType notification.TemplateType, successValue, failureValue string) string {
+	if templateType.IsCritical() {
+		return failureValue
+	}
+	return successValue
+}
+
+// postJSON 以 JSON 格式发送 POST 请求，返回响应体；非 2xx 状态码视为失败
+func postJSON(apiURL string, payload interface{}) ([]byte, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("JSON编码失败: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doRequest(req)
+}
+
+// doRequest 发送 HTTP 请求并返回响应体；非 2xx 状态码视为失败
+func doRequest(req *http.Request) ([]byte, error) {
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return body, fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	return body, nil
+}
@@ -0,0 +1,125 @@
+package notification_channel
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+// DingtalkChannel 钉钉自定义机器人通知渠道
+type DingtalkChannel struct {
+	*BaseChannel
+	webhookURL string
+	secret     string
+}
+
+// GetType 获取渠道类型
+func (c *DingtalkChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypeDingtalk
+}
+
+// NewDingtalkChannel 创建钉钉通知渠道
+func NewDingtalkChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &DingtalkChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeDingtalk)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	webhookURL := channelConfig.Config["webhookUrl"]
+	if webhookURL == "" {
+		logger.Warn("钉钉配置不完整，通知功能已禁用")
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	channel.webhookURL = webhookURL
+	channel.secret = channelConfig.Config["secret"]
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *DingtalkChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("钉钉通知渠道未启用")
+	}
+
+	templateContent := c.templateContent(templateType, string(notification.ChannelTypeDingtalk))
+	if templateContent == "" {
+		return fmt.Errorf("未找到模板: %s", templateType)
+	}
+
+	message, err := renderTemplate("dingtalk", templateContent, data)
+	if err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	apiURL, err := c.signedURL()
+	if err != nil {
+		return err
+	}
+
+	title := templateTitle(templateType)
+	payload := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": title,
+			"text":  "### " + title + "\n\n" + message,
+		},
+	}
+
+	respBody, err := postJSON(apiURL, payload)
+	if err != nil {
+		return fmt.Errorf("发送钉钉消息失败: %w", err)
+	}
+
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("解析钉钉API响应失败: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("钉钉API错误: %s (%d)", result.ErrMsg, result.ErrCode)
+	}
+
+	return nil
+}
+
+// signedURL 配置了加签密钥时，在 Webhook 地址后附加时间戳和签名
+func (c *DingtalkChannel) signedURL() (string, error) {
+	if c.secret == "" {
+		return c.webhookURL, nil
+	}
+
+	parsed, err := url.Parse(c.webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("钉钉 Webhook 地址无效: %w", err)
+	}
+
+	// 签名为 HmacSHA256(timestamp + "\n" + secret) 的 Base64 编码，时间戳单位为毫秒
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(c.secret))
+	mac.Write([]byte(timestamp + "\n" + c.secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	query := parsed.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", sign)
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
@@ -0,0 +1,105 @@
+package notification_channel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+const (
+	// discordColorSuccess 成功通知的 Embed 颜色（绿色）
+	discordColorSuccess = 0x2ECC71
+	// discordColorFailure 失败通知的 Embed 颜色（红色）
+	discordColorFailure = 0xE74C3C
+)
+
+// DiscordChannel Discord Webhook 通知渠道
+type DiscordChannel struct {
+	*BaseChannel
+	webhookURL string
+	username   string
+	avatarURL  string
+}
+
+// GetType 获取渠道类型
+func (c *DiscordChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypeDiscord
+}
+
+// NewDiscordChannel 创建 Discord 通知渠道
+func NewDiscordChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &DiscordChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeDiscord)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	webhookURL := channelConfig.Config["webhookUrl"]
+	if webhookURL == "" {
+		logger.Warn("Discord 配置不完整，通知功能已禁用")
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	channel.webhookURL = webhookURL
+	channel.username = channelConfig.Config["username"]
+	channel.avatarURL = channelConfig.Config["avatarUrl"]
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *DiscordChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("discord 通知渠道未启用")
+	}
+
+	templateContent := c.templateContent(templateType, string(notification.ChannelTypeDiscord))
+	if templateContent == "" {
+		return fmt.Errorf("未找到模板: %s", templateType)
+	}
+
+	message, err := renderTemplate("discord", templateContent, data)
+	if err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	color := discordColorSuccess
+	if templateType.IsCritical() {
+		color = discordColorFailure
+	}
+
+	// Discord Embed 描述最多 4096 个字符
+	description := []rune(message)
+	if len(description) > 4096 {
+		description = append(description[:4093], []rune("...")...)
+	}
+
+	payload := map[string]interface{}{
+		"embeds": []map[string]interface{}{
+			{
+				"title":       templateTitle(templateType),
+				"description": string(description),
+				"color":       color,
+				"timestamp":   time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+	if c.username != "" {
+		payload["username"] = c.username
+	}
+	if c.avatarURL != "" {
+		payload["avatar_url"] = c.avatarURL
+	}
+
+	if _, err := postJSON(c.webhookURL, payload); err != nil {
+		return fmt.Errorf("发送 Discord 消息失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package notification_channel
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	htmlTemplate "html/template"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+const (
+	// EmailSecurityNone 不加密
+	EmailSecurityNone = "none"
+	// EmailSecurityStartTLS 使用 STARTTLS 升级连接
+	EmailSecurityStartTLS = "starttls"
+	// EmailSecurityTLS 直接使用 TLS 连接（通常为 465 端口）
+	EmailSecurityTLS = "tls"
+
+	// defaultEmailSubject 默认邮件主题模板
+	defaultEmailSubject = "[alist2strm] {{.TaskName}} - {{.Status}}"
+)
+
+// EmailChannel SMTP 邮件通知渠道
+type EmailChannel struct {
+	*BaseChannel
+	host          string
+	port          string
+	username      string
+	password      string
+	from          string
+	to            []string
+	security      string
+	subject       string
+	skipVerifyTLS bool
+}
+
+// GetType 获取渠道类型
+func (c *EmailChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypeEmail
+}
+
+// NewEmailChannel 创建邮件通知渠道
+func NewEmailChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &EmailChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeEmail)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	host := channelConfig.Config["host"]
+	from := channelConfig.Config["from"]
+	var recipients []string
+	for _, addr := range strings.FieldsFunc(channelConfig.Config["to"], func(r rune) bool { return r == ',' || r == ';' }) {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			recipients = append(recipients, addr)
+		}
+	}
+
+	// 检查必要参数
+	if host == "" || from == "" || len(recipients) == 0 {
+		logger.Warn("邮件配置不完整，通知功能已禁用",
+			zap.String("host", host),
+			zap.String("from", from),
+			zap.Int("recipients", len(recipients)))
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	security := strings.ToLower(channelConfig.Config["security"])
+	switch security {
+	case EmailSecurityNone, EmailSecurityStartTLS, EmailSecurityTLS:
+	case "":
+		security = EmailSecurityStartTLS
+	default:
+		logger.Warn("邮件加密方式配置无效，通知功能已禁用", zap.String("security", security))
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	port := channelConfig.Config["port"]
+	if port == "" {
+		switch security {
+		case EmailSecurityTLS:
+			port = "465"
+		case EmailSecurityStartTLS:
+			port = "587"
+		default:
+			port = "25"
+		}
+	}
+
+	subject := channelConfig.Config["subject"]
+	if subject == "" {
+		subject = defaultEmailSubject
+	}
+
+	channel.host = host
+	channel.port = port
+	channel.username = channelConfig.Config["username"]
+	channel.password = channelConfig.Config["password"]
+	channel.from = from
+	channel.to = recipients
+	channel.security = security
+	channel.subject = subject
+	channel.skipVerifyTLS = channelConfig.Config["skipVerify"] == "true"
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *EmailChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("邮件通知渠道未启用")
+	}
+
+	textContent, htmlContent := c.getTemplateContent(templateType)
+	if textContent == "" && htmlContent == "" {
+		return fmt.Errorf("未找到模板: %s", templateType)
+	}
+
+	subject, err := renderTemplate("subject", c.subject, data)
+	if err != nil {
+		return fmt.Errorf("渲染邮件主题失败: %w", err)
+	}
+
+	var textBody, htmlBody string
+	if textContent != "" {
+		if textBody, err = renderTemplate("email", textContent, data); err != nil {
+			return fmt.Errorf("渲染模板失败: %w", err)
+		}
+	}
+	if htmlContent != "" {
+		if htmlBody, err = c.renderHTML(htmlContent, data); err != nil {
+			return fmt.Errorf("渲染 HTML 模板失败: %w", err)
+		}
+	}
+
+	message, err := c.buildMessage(strings.TrimSpace(subject), textBody, htmlBody)
+	if err != nil {
+		return err
+	}
+
+	return c.sendMail(message)
+}
+
+// getTemplateContent 获取纯文本与 HTML 模板内容，未配置时使用默认模板
+func (c *EmailChannel) getTemplateContent(templateType notification.TemplateType) (string, string) {
+	return c.templateContent(templateType, string(notification.ChannelTypeEmail)), c.templateContent(templateType, notification.TemplateKeyEmailHTML)
+}
+
+// renderHTML 渲染 HTML 模板，自动转义数据中的特殊字符
+func (c *EmailChannel) renderHTML(templateContent string, data interface{}) (string, error) {
+	tmpl, err := htmlTemplate.New("email_html").Parse(templateContent)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// buildMessage 构建 MIME 邮件内容，同时提供纯文本与 HTML 时使用 multipart/alternative
+func (c *EmailChannel) buildMessage(subject, textBody, htmlBody string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("From: " + c.from + "\r\n")
+	buf.WriteString("To: " + strings.Join(c.to, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	switch {
+	case textBody != "" && htmlBody != "":
+		boundary, err := randomBoundary()
+		if err != nil {
+			return nil, fmt.Errorf("生成邮件分隔符失败: %w", err)
+		}
+		buf.WriteString("Content-Type: multipart/alternative; boundary=\"" + boundary + "\"\r\n\r\n")
+		writeMIMEPart(&buf, boundary, "text/plain", textBody)
+		writeMIMEPart(&buf, boundary, "text/html", htmlBody)
+		buf.WriteString("--" + boundary + "--\r\n")
+	case htmlBody != "":
+		writeMIMEBody(&buf, "text/html", htmlBody)
+	default:
+		writeMIMEBody(&buf, "text/plain", textBody)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeMIMEPart 写入 multipart 中的一个部分
+func writeMIMEPart(buf *bytes.Buffer, boundary, contentType, body string) {
+	buf.WriteString("--" + boundary + "\r\n")
+	writeMIMEBody(buf, contentType, body)
+}
+
+// writeMIMEBody 以 base64 编码写入正文及其头部
+func writeMIMEBody(buf *bytes.Buffer, contentType, body string) {
+	buf.WriteString("Content-Type: " + contentType + "; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+}
+
+// randomBoundary 生成随机的 MIME 分隔符
+func randomBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "alist2strm-" + hex.EncodeToString(b), nil
+}
+
+// sendMail 通过 SMTP 发送邮件
+func (c *EmailChannel) sendMail(message []byte) error {
+	addr := net.JoinHostPort(c.host, c.port)
+	tlsConfig := &tls.Config{
+		ServerName:         c.host,
+		InsecureSkipVerify: c.skipVerifyTLS,
+	}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if c.security == EmailSecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("创建 SMTP 客户端失败: %w", err)
+	}
+	defer client.Close()
+
+	if c.security == EmailSecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP 服务器不支持 STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS 握手失败: %w", err)
+		}
+	}
+
+	if c.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.username, c.password, c.host)); err != nil {
+			return fmt.Errorf("SMTP 认证失败: %w", err)
+		}
+	}
+
+	if err := client.Mail(extractAddress(c.from)); err != nil {
+		return fmt.Errorf("设置发件人失败: %w", err)
+	}
+	for _, recipient := range c.to {
+		if err := client.Rcpt(extractAddress(recipient)); err != nil {
+			return fmt.Errorf("设置收件人 %s 失败: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("发送邮件内容失败: %w", err)
+	}
+	if _, err := writer.Write(message); err != nil {
+		writer.Close()
+		return fmt.Errorf("发送邮件内容失败: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("发送邮件内容失败: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// extractAddress 从 "名称 <地址>" 形式中提取邮箱地址
+func extractAddress(addr string) string {
+	if parsed, err := mail.ParseAddress(addr); err == nil {
+		return parsed.Address
+	}
+	return strings.TrimSpace(addr)
+}
@@ -0,0 +1,127 @@
+package notification_channel
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+// FeishuChannel 飞书/Lark 自定义机器人通知渠道
+type FeishuChannel struct {
+	*BaseChannel
+	webhookURL string
+	secret     string
+}
+
+// GetType 获取渠道类型
+func (c *FeishuChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypeFeishu
+}
+
+// NewFeishuChannel 创建飞书通知渠道
+func NewFeishuChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &FeishuChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeFeishu)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	webhookURL := channelConfig.Config["webhookUrl"]
+	if webhookURL == "" {
+		logger.Warn("飞书配置不完整，通知功能已禁用")
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	channel.webhookURL = webhookURL
+	channel.secret = channelConfig.Config["secret"]
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *FeishuChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("飞书通知渠道未启用")
+	}
+
+	templateContent := c.templateContent(templateType, string(notification.ChannelTypeFeishu))
+	if templateContent == "" {
+		return fmt.Errorf("未找到模板: %s", templateType)
+	}
+
+	message, err := renderTemplate("feishu", templateContent, data)
+	if err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	headerTemplate := "green"
+	if templateType.IsCritical() {
+		headerTemplate = "red"
+	}
+
+	payload := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"config": map[string]interface{}{
+				"wide_screen_mode": true,
+			},
+			"header": map[string]interface{}{
+				"template": headerTemplate,
+				"title": map[string]string{
+					"tag":     "plain_text",
+					"content": templateTitle(templateType),
+				},
+			},
+			"elements": []map[string]interface{}{
+				{
+					"tag":     "markdown",
+					"content": message,
+				},
+			},
+		},
+	}
+
+	if c.secret != "" {
+		timestamp, sign := c.sign()
+		payload["timestamp"] = timestamp
+		payload["sign"] = sign
+	}
+
+	respBody, err := postJSON(c.webhookURL, payload)
+	if err != nil {
+		return fmt.Errorf("发送飞书消息失败: %w", err)
+	}
+
+	var result struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("解析飞书API响应失败: %w", err)
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("飞书API错误: %s (%d)", result.Msg, result.Code)
+	}
+
+	return nil
+}
+
+// sign 生成签名校验所需的时间戳（秒）和签名
+func (c *FeishuChannel) sign() (string, string) {
+	// 飞书以 timestamp + "\n" + secret 作为 HmacSHA256 的密钥，对空消息签名后进行 Base64 编码
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+c.secret))
+	return timestamp, base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,115 @@
+package notification_channel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+// GotifyChannel Gotify 推送渠道
+type GotifyChannel struct {
+	*BaseChannel
+	serverURL       string
+	appToken        string
+	successPriority int
+	failurePriority int
+}
+
+// GetType 获取渠道类型
+func (c *GotifyChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypeGotify
+}
+
+// NewGotifyChannel 创建 Gotify 推送渠道
+func NewGotifyChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &GotifyChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeGotify)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	serverURL := strings.TrimRight(channelConfig.Config["serverUrl"], "/")
+	appToken := channelConfig.Config["appToken"]
+	if serverURL == "" || appToken == "" {
+		logger.Warn("Gotify 配置不完整，通知功能已禁用", zap.String("serverUrl", serverURL))
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	// 优先级取值 0-10，数值越大越重要
+	successPriority, err := strconv.Atoi(channelConfig.Config["successPriority"])
+	if err != nil {
+		successPriority = 5
+	}
+	failurePriority, err := strconv.Atoi(channelConfig.Config["failurePriority"])
+	if err != nil {
+		failurePriority = 8
+	}
+
+	channel.serverURL = serverURL
+	channel.appToken = appToken
+	channel.successPriority = successPriority
+	channel.failurePriority = failurePriority
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *GotifyChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("gotify 通知渠道未启用")
+	}
+
+	templateContent := c.templateContent(templateType, string(notification.ChannelTypeGotify))
+	if templateContent == "" {
+		return fmt.Errorf("未找到模板: %s", templateType)
+	}
+
+	message, err := renderTemplate("gotify", templateContent, data)
+	if err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	priority := c.successPriority
+	if templateType.IsCritical() {
+		priority = c.failurePriority
+	}
+
+	payload := map[string]interface{}{
+		"title":    templateTitle(templateType),
+		"message":  message,
+		"priority": priority,
+		"extras": map[string]interface{}{
+			"client::display": map[string]string{
+				"contentType": "text/markdown",
+			},
+		},
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("JSON编码失败: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.serverURL+"/message", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("创建 Gotify 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", c.appToken)
+
+	if _, err := doRequest(req); err != nil {
+		return fmt.Errorf("发送 Gotify 推送失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package notification_channel
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+// NtfyChannel ntfy 推送渠道
+type NtfyChannel struct {
+	*BaseChannel
+	serverURL       string
+	topic           string
+	token           string
+	username        string
+	password        string
+	successPriority string
+	failurePriority string
+}
+
+// GetType 获取渠道类型
+func (c *NtfyChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypeNtfy
+}
+
+// NewNtfyChannel 创建 ntfy 推送渠道
+func NewNtfyChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &NtfyChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeNtfy)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	topic := strings.Trim(channelConfig.Config["topic"], "/")
+	if topic == "" {
+		logger.Warn("ntfy 配置不完整，通知功能已禁用")
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	serverURL := strings.TrimRight(channelConfig.Config["serverUrl"], "/")
+	if serverURL == "" {
+		serverURL = "https://ntfy.sh"
+	}
+
+	// 优先级取值 1-5（min, low, default, high, urgent）
+	successPriority := channelConfig.Config["successPriority"]
+	if successPriority == "" {
+		successPriority = "3"
+	}
+	failurePriority := channelConfig.Config["failurePriority"]
+	if failurePriority == "" {
+		failurePriority = "5"
+	}
+
+	channel.serverURL = serverURL
+	channel.topic = topic
+	channel.token = channelConfig.Config["token"]
+	channel.username = channelConfig.Config["username"]
+	channel.password = channelConfig.Config["password"]
+	channel.successPriority = successPriority
+	channel.failurePriority = failurePriority
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *NtfyChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("ntfy 通知渠道未启用")
+	}
+
+	templateContent := c.templateContent(templateType, string(notification.ChannelTypeNtfy))
+	if templateContent == "" {
+		return fmt.Errorf("未找到模板: %s", templateType)
+	}
+
+	message, err := renderTemplate("ntfy", templateContent, data)
+	if err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.serverURL+"/"+c.topic, strings.NewReader(message))
+	if err != nil {
+		return fmt.Errorf("创建 ntfy 请求失败: %w", err)
+	}
+	// 标题包含中文，按 RFC 2047 编码后放入请求头
+	req.Header.Set("Title", mime.BEncoding.Encode("UTF-8", templateTitle(templateType)))
+	req.Header.Set("Priority", pickByTemplate(templateType, c.successPriority, c.failurePriority))
+	req.Header.Set("Tags", pickByTemplate(templateType, "white_check_mark", "x"))
+	req.Header.Set("Markdown", "yes")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	if _, err := doRequest(req); err != nil {
+		return fmt.Errorf("发送 ntfy 推送失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package notification_channel
+
+import "github.com/MccRay-s/alist2strm/model/notification"
+
+// RenderPreview 按渠道发送时实际使用的方式渲染模板，用于模板校验与预览
+func RenderPreview(templateKey, templateContent string, data interface{}) (string, error) {
+	switch templateKey {
+	case string(notification.ChannelTypeWebhook):
+		// Webhook 模板额外提供 json 函数
+		return (&WebhookChannel{}).renderTemplate(templateContent, data)
+	case notification.TemplateKeyEmailHTML:
+		// 邮件 HTML 正文使用 html/template 自动转义
+		return (&EmailChannel{}).renderHTML(templateContent, data)
+	default:
+		return renderTemplate(templateKey, templateContent, data)
+	}
+}
@@ -0,0 +1,102 @@
+package notification_channel
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+// pushPlusAPIURL PushPlus 发送接口地址
+const pushPlusAPIURL = "https://www.pushplus.plus/send"
+
+// PushPlusChannel PushPlus 推送渠道
+type PushPlusChannel struct {
+	*BaseChannel
+	token          string
+	topic          string
+	successChannel string
+	failureChannel string
+}
+
+// GetType 获取渠道类型
+func (c *PushPlusChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypePushPlus
+}
+
+// NewPushPlusChannel 创建 PushPlus 推送渠道
+func NewPushPlusChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &PushPlusChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypePushPlus)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	token := channelConfig.Config["token"]
+	if token == "" {
+		logger.Warn("PushPlus 配置不完整，通知功能已禁用")
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	channel.token = token
+	channel.topic = channelConfig.Config["topic"]
+	channel.successChannel = channelConfig.Config["successChannel"]
+	channel.failureChannel = channelConfig.Config["failureChannel"]
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *PushPlusChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("pushplus 通知渠道未启用")
+	}
+
+	templateContent := c.templateContent(templateType, string(notification.ChannelTypePushPlus))
+	if templateContent == "" {
+		return fmt.Errorf("未找到模板: %s", templateType)
+	}
+
+	message, err := renderTemplate("pushplus", templateContent, data)
+	if err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	payload := map[string]string{
+		"token":    c.token,
+		"title":    templateTitle(templateType),
+		"content":  message,
+		"template": "markdown",
+	}
+	if c.topic != "" {
+		payload["topic"] = c.topic
+	}
+	// PushPlus 没有优先级，通过按结果选择不同的发送渠道（wechat、cp、mail 等）实现分级
+	if pushChannel := pickByTemplate(templateType, c.successChannel, c.failureChannel); pushChannel != "" {
+		payload["channel"] = pushChannel
+	}
+
+	respBody, err := postJSON(pushPlusAPIURL, payload)
+	if err != nil {
+		return fmt.Errorf("发送 PushPlus 推送失败: %w", err)
+	}
+
+	var result struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("解析 PushPlus 响应失败: %w", err)
+	}
+	if result.Code != 200 {
+		return fmt.Errorf("pushplus API错误: %s (%d)", result.Msg, result.Code)
+	}
+
+	return nil
+}
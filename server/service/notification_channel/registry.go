@@ -0,0 +1,48 @@
+package notification_channel
+
+import (
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+// Factory 通知渠道构造函数
+type Factory func(logger *zap.Logger, settings *notification.Settings) Channel
+
+// channelFactories 渠道类型与构造函数的映射，按初始化顺序排列
+var channelFactories = []struct {
+	channelType notification.NotificationChannelType
+	factory     Factory
+}{
+	{notification.ChannelTypeTelegram, NewTelegramChannel},
+	{notification.ChannelTypeWework, NewWeworkChannel},
+	{notification.ChannelTypeWebhook, NewWebhookChannel},
+	{notification.ChannelTypeEmail, NewEmailChannel},
+	{notification.ChannelTypeDiscord, NewDiscordChannel},
+	{notification.ChannelTypeSlack, NewSlackChannel},
+	{notification.ChannelTypeDingtalk, NewDingtalkChannel},
+	{notification.ChannelTypeFeishu, NewFeishuChannel},
+	{notification.ChannelTypeBark, NewBarkChannel},
+	{notification.ChannelTypeNtfy, NewNtfyChannel},
+	{notification.ChannelTypeGotify, NewGotifyChannel},
+	{notification.ChannelTypeServerChan, NewServerChanChannel},
+	{notification.ChannelTypePushPlus, NewPushPlusChannel},
+}
+
+// SupportedChannelTypes 获取所有支持的渠道类型
+func SupportedChannelTypes() []notification.NotificationChannelType {
+	types := make([]notification.NotificationChannelType, 0, len(channelFactories))
+	for _, item := range channelFactories {
+		types = append(types, item.channelType)
+	}
+	return types
+}
+
+// NewChannel 根据渠道类型创建通知渠道，类型不支持时返回 false
+func NewChannel(channelType notification.NotificationChannelType, logger *zap.Logger, settings *notification.Settings) (Channel, bool) {
+	for _, item := range channelFactories {
+		if item.channelType == channelType {
+			return item.factory(logger, settings), true
+		}
+	}
+	return nil, false
+}
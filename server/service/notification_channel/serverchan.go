@@ -0,0 +1,104 @@
+package notification_channel
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+// serverChan3KeyPattern Server酱³ 的 SendKey 格式，形如 sctp{uid}t...
+var serverChan3KeyPattern = regexp.MustCompile(`^sctp(\d+)t`)
+
+// ServerChanChannel Server酱 推送渠道
+type ServerChanChannel struct {
+	*BaseChannel
+	sendKey        string
+	successChannel string
+	failureChannel string
+}
+
+// GetType 获取渠道类型
+func (c *ServerChanChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypeServerChan
+}
+
+// NewServerChanChannel 创建 Server酱 推送渠道
+func NewServerChanChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &ServerChanChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeServerChan)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	sendKey := channelConfig.Config["sendKey"]
+	if sendKey == "" {
+		logger.Warn("Server酱 配置不完整，通知功能已禁用")
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	channel.sendKey = sendKey
+	channel.successChannel = channelConfig.Config["successChannel"]
+	channel.failureChannel = channelConfig.Config["failureChannel"]
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *ServerChanChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("server酱 通知渠道未启用")
+	}
+
+	templateContent := c.templateContent(templateType, string(notification.ChannelTypeServerChan))
+	if templateContent == "" {
+		return fmt.Errorf("未找到模板: %s", templateType)
+	}
+
+	message, err := renderTemplate("serverchan", templateContent, data)
+	if err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	payload := map[string]string{
+		"title": templateTitle(templateType),
+		"desp":  message,
+	}
+	// Server酱 Turbo 没有优先级，通过按结果选择不同的消息通道实现分级
+	if pushChannel := pickByTemplate(templateType, c.successChannel, c.failureChannel); pushChannel != "" {
+		payload["channel"] = pushChannel
+	}
+
+	respBody, err := postJSON(c.apiURL(), payload)
+	if err != nil {
+		return fmt.Errorf("发送 Server酱 推送失败: %w", err)
+	}
+
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("解析 Server酱 响应失败: %w", err)
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("server酱 API错误: %s (%d)", result.Message, result.Code)
+	}
+
+	return nil
+}
+
+// apiURL 根据 SendKey 类型选择 Server酱 Turbo 或 Server酱³ 的接口地址
+func (c *ServerChanChannel) apiURL() string {
+	if matches := serverChan3KeyPattern.FindStringSubmatch(c.sendKey); matches != nil {
+		return fmt.Sprintf("https://%s.push.ft07.com/send/%s.send", matches[1], c.sendKey)
+	}
+	return fmt.Sprintf("https://sctapi.ftqq.com/%s.send", c.sendKey)
+}
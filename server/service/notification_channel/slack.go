@@ -0,0 +1,94 @@
+package notification_channel
+
+import (
+	"fmt"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+// SlackChannel Slack Incoming Webhook 通知渠道
+type SlackChannel struct {
+	*BaseChannel
+	webhookURL string
+}
+
+// GetType 获取渠道类型
+func (c *SlackChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypeSlack
+}
+
+// NewSlackChannel 创建 Slack 通知渠道
+func NewSlackChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &SlackChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeSlack)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	webhookURL := channelConfig.Config["webhookUrl"]
+	if webhookURL == "" {
+		logger.Warn("Slack 配置不完整，通知功能已禁用")
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	channel.webhookURL = webhookURL
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *SlackChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("slack 通知渠道未启用")
+	}
+
+	templateContent := c.templateContent(templateType, string(notification.ChannelTypeSlack))
+	if templateContent == "" {
+		return fmt.Errorf("未找到模板: %s", templateType)
+	}
+
+	message, err := renderTemplate("slack", templateContent, data)
+	if err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	// Slack section 区块文本最多 3000 个字符
+	sectionText := []rune(message)
+	if len(sectionText) > 3000 {
+		sectionText = append(sectionText[:2997], []rune("...")...)
+	}
+
+	title := templateTitle(templateType)
+	payload := map[string]interface{}{
+		"text": title,
+		"blocks": []map[string]interface{}{
+			{
+				"type": "header",
+				"text": map[string]interface{}{
+					"type":  "plain_text",
+					"text":  title,
+					"emoji": true,
+				},
+			},
+			{
+				"type": "section",
+				"text": map[string]interface{}{
+					"type": "mrkdwn",
+					"text": string(sectionText),
+				},
+			},
+		},
+	}
+
+	// Incoming Webhook 成功时返回纯文本 ok，失败时返回非 2xx 状态码
+	if _, err := postJSON(c.webhookURL, payload); err != nil {
+		return fmt.Errorf("发送 Slack 消息失败: %w", err)
+	}
+	return nil
+}
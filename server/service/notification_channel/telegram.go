@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"text/template"
@@ -91,17 +92,18 @@ func (c *TelegramChannel) Send(templateType notification.TemplateType, data inte
 		return fmt.Errorf("渲染模板失败: %w", err)
 	}
 
+	// 带海报的新增媒体通知以图片消息发送
+	if poster, _ := posterOf(data); poster != nil {
+		return c.sendPhoto(poster, message)
+	}
+
 	// 发送通知
 	return c.sendMessage(message)
 }
 
 // getTemplateContent 获取模板内容
 func (c *TelegramChannel) getTemplateContent(templateType notification.TemplateType) string {
-	templateConfig, exists := c.settings.Templates[string(templateType)]
-	if !exists {
-		return ""
-	}
-	return templateConfig.Telegram
+	return c.templateContent(templateType, string(notification.ChannelTypeTelegram))
 }
 
 // renderTemplate 渲染模板
@@ -159,3 +161,60 @@ func (c *TelegramChannel) sendMessage(message string) error {
 
 	return nil
 }
+
+// telegramCaptionLimit Telegram 图片说明的最大字符数
+const telegramCaptionLimit = 1024
+
+// sendPhoto 发送图片消息，消息内容超过图片说明长度限制时改为先发图片再发文字
+func (c *TelegramChannel) sendPhoto(photo []byte, message string) error {
+	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendPhoto", c.botToken)
+
+	caption := message
+	longMessage := len([]rune(message)) > telegramCaptionLimit
+	if longMessage {
+		caption = ""
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("chat_id", c.chatID)
+	if caption != "" {
+		writer.WriteField("caption", caption)
+		writer.WriteField("parse_mode", c.parseMode)
+	}
+	part, err := writer.CreateFormFile("photo", "poster.jpg")
+	if err != nil {
+		return fmt.Errorf("构建图片消息失败: %w", err)
+	}
+	if _, err := part.Write(photo); err != nil {
+		return fmt.Errorf("构建图片消息失败: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("构建图片消息失败: %w", err)
+	}
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	resp, err := client.Post(apiURL, writer.FormDataContentType(), &body)
+	if err != nil {
+		return fmt.Errorf("发送Telegram图片消息失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp struct {
+			Description string `json:"description"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errorResp); err == nil {
+			return fmt.Errorf("telegram API错误 (HTTP %d): %s", resp.StatusCode, errorResp.Description)
+		}
+		return fmt.Errorf("telegram API错误 (HTTP %d)", resp.StatusCode)
+	}
+
+	if longMessage {
+		return c.sendMessage(message)
+	}
+	return nil
+}
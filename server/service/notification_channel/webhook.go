@@ -0,0 +1,217 @@
+package notification_channel
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultWebhookSignatureHeader 默认的签名请求头
+	defaultWebhookSignatureHeader = "X-Alist2strm-Signature"
+	// defaultWebhookTimeout 默认请求超时时间（秒）
+	defaultWebhookTimeout = 10
+)
+
+// WebhookChannel 通用 HTTP Webhook 通知渠道
+type WebhookChannel struct {
+	*BaseChannel
+	url             string
+	method          string
+	contentType     string
+	headers         map[string]string
+	secret          string
+	signatureHeader string
+	timeout         time.Duration
+}
+
+// GetType 获取渠道类型
+func (c *WebhookChannel) GetType() notification.NotificationChannelType {
+	return notification.ChannelTypeWebhook
+}
+
+// NewWebhookChannel 创建通用 Webhook 通知渠道
+func NewWebhookChannel(logger *zap.Logger, settings *notification.Settings) Channel {
+	channel := &WebhookChannel{
+		BaseChannel: NewBaseChannel(logger, settings),
+	}
+
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeWebhook)]
+	if !exists || !channelConfig.Enabled {
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	webhookURL := channelConfig.Config["url"]
+	if webhookURL == "" {
+		logger.Warn("Webhook 配置不完整，通知功能已禁用")
+		channel.BaseChannel.enabled = false
+		return channel
+	}
+
+	// 请求头以 JSON 对象形式配置，例如 {"Authorization":"Bearer xxx"}
+	headers := make(map[string]string)
+	if rawHeaders := strings.TrimSpace(channelConfig.Config["headers"]); rawHeaders != "" {
+		if err := json.Unmarshal([]byte(rawHeaders), &headers); err != nil {
+			logger.Warn("Webhook 请求头配置解析失败，通知功能已禁用", zap.Error(err))
+			channel.BaseChannel.enabled = false
+			return channel
+		}
+	}
+
+	method := strings.ToUpper(channelConfig.Config["method"])
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	contentType := channelConfig.Config["contentType"]
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	signatureHeader := channelConfig.Config["signatureHeader"]
+	if signatureHeader == "" {
+		signatureHeader = defaultWebhookSignatureHeader
+	}
+
+	timeout := defaultWebhookTimeout
+	if rawTimeout := channelConfig.Config["timeout"]; rawTimeout != "" {
+		if value, err := strconv.Atoi(rawTimeout); err == nil && value > 0 {
+			timeout = value
+		}
+	}
+
+	channel.url = webhookURL
+	channel.method = method
+	channel.contentType = contentType
+	channel.headers = headers
+	channel.secret = channelConfig.Config["secret"]
+	channel.signatureHeader = signatureHeader
+	channel.timeout = time.Duration(timeout) * time.Second
+	channel.BaseChannel.enabled = true
+	return channel
+}
+
+// Send 发送通知
+func (c *WebhookChannel) Send(templateType notification.TemplateType, data interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("webhook 通知渠道未启用")
+	}
+
+	body, err := c.buildBody(templateType, data)
+	if err != nil {
+		return err
+	}
+
+	return c.sendRequest(body)
+}
+
+// buildBody 构建请求体，未配置模板时直接发送 JSON 格式的通知数据
+func (c *WebhookChannel) buildBody(templateType notification.TemplateType, data interface{}) ([]byte, error) {
+	templateContent := c.getTemplateContent(templateType)
+	if templateContent == "" {
+		body, err := json.Marshal(map[string]interface{}{
+			"event": templateType,
+			"data":  data,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("JSON编码失败: %w", err)
+		}
+		return body, nil
+	}
+
+	message, err := c.renderTemplate(templateContent, data)
+	if err != nil {
+		return nil, fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	body := []byte(message)
+	if strings.Contains(c.contentType, "json") && !json.Valid(body) {
+		return nil, fmt.Errorf("渲染后的请求体不是合法的 JSON")
+	}
+	return body, nil
+}
+
+// getTemplateContent 获取模板内容
+func (c *WebhookChannel) getTemplateContent(templateType notification.TemplateType) string {
+	return c.templateContent(templateType, string(notification.ChannelTypeWebhook))
+}
+
+// renderTemplate 渲染模板，提供 json 函数用于在 JSON 模板中安全输出字符串
+func (c *WebhookChannel) renderTemplate(templateContent string, data interface{}) (string, error) {
+	tmpl, err := template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			encoded, err := json.Marshal(v)
+			return string(encoded), err
+		},
+	}).Parse(templateContent)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// sign 使用 HMAC-SHA256 对请求体签名
+func (c *WebhookChannel) sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(c.secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendRequest 发送 HTTP 请求
+func (c *WebhookChannel) sendRequest(body []byte) error {
+	var reader io.Reader
+	if c.method != http.MethodGet {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(c.method, c.url, reader)
+	if err != nil {
+		return fmt.Errorf("创建 Webhook 请求失败: %w", err)
+	}
+
+	if reader != nil {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	if c.secret != "" {
+		req.Header.Set(c.signatureHeader, c.sign(body))
+	}
+
+	client := &http.Client{
+		Timeout: c.timeout,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送 Webhook 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook 响应错误 (HTTP %d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html"
+	"mime/multipart"
 	"net/http"
+	"strings"
 	"text/template"
 	"time"
 
@@ -97,17 +100,20 @@ func (c *WeworkChannel) Send(templateType notification.TemplateType, data interf
 		return fmt.Errorf("渲染模板失败: %w", err)
 	}
 
+	// 新增媒体通知优先以图文消息发送：有海报时使用 mpnews，有跳转链接时使用 news
+	if templateType == notification.TemplateTypeNewMedia {
+		if sent, err := c.sendNewsCard(templateType, data); sent || err != nil {
+			return err
+		}
+	}
+
 	// 发送通知
 	return c.sendMessage(message)
 }
 
 // getTemplateContent 获取模板内容
 func (c *WeworkChannel) getTemplateContent(templateType notification.TemplateType) string {
-	templateConfig, exists := c.settings.Templates[string(templateType)]
-	if !exists {
-		return ""
-	}
-	return templateConfig.Wework
+	return c.templateContent(templateType, string(notification.ChannelTypeWework))
 }
 
 // renderTemplate 渲染模板
@@ -169,6 +175,109 @@ func (c *WeworkChannel) getAccessToken() (string, error) {
 
 // sendMessage 发送消息
 func (c *WeworkChannel) sendMessage(message string) error {
+	return c.send("markdown", map[string]string{
+		"content": message,
+	})
+}
+
+// sendNewsCard 发送图文消息，既没有海报也没有跳转链接时返回 false，由调用方改为发送 Markdown 消息
+func (c *WeworkChannel) sendNewsCard(templateType notification.TemplateType, data interface{}) (bool, error) {
+	poster, _ := posterOf(data)
+	link := ""
+	if taskData, ok := data.(*notification.TaskNotificationData); ok {
+		link = taskData.Link
+	}
+	if poster == nil && link == "" {
+		return false, nil
+	}
+
+	templateContent := c.templateContent(templateType, notification.TemplateKeyWeworkNews)
+	if templateContent == "" {
+		return false, nil
+	}
+	text, err := renderTemplate("wework_news", templateContent, data)
+	if err != nil {
+		return true, fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	title := templateTitle(templateType)
+	if poster != nil {
+		mediaID, err := c.uploadImage(poster)
+		if err != nil {
+			return true, err
+		}
+		article := map[string]string{
+			"title":          title,
+			"thumb_media_id": mediaID,
+			"content":        strings.ReplaceAll(html.EscapeString(text), "\n", "<br/>"),
+			"digest":         truncateBytes(text, 500),
+		}
+		if link != "" {
+			article["content_source_url"] = link
+		}
+		return true, c.send("mpnews", map[string]interface{}{
+			"articles": []map[string]string{article},
+		})
+	}
+
+	return true, c.send("news", map[string]interface{}{
+		"articles": []map[string]string{
+			{
+				"title":       title,
+				"description": truncateBytes(text, 500),
+				"url":         link,
+			},
+		},
+	})
+}
+
+// uploadImage 上传临时图片素材，返回 media_id
+func (c *WeworkChannel) uploadImage(image []byte) (string, error) {
+	token, err := c.getAccessToken()
+	if err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("media", "poster.jpg")
+	if err != nil {
+		return "", fmt.Errorf("构建图片素材失败: %w", err)
+	}
+	if _, err := part.Write(image); err != nil {
+		return "", fmt.Errorf("构建图片素材失败: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("构建图片素材失败: %w", err)
+	}
+
+	apiURL := fmt.Sprintf("https://qyapi.weixin.qq.com/cgi-bin/media/upload?access_token=%s&type=image", token)
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	resp, err := client.Post(apiURL, writer.FormDataContentType(), &body)
+	if err != nil {
+		return "", fmt.Errorf("上传企业微信图片素材失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		MediaID string `json:"media_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("解析企业微信API响应失败: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return "", fmt.Errorf("企业微信API错误: %s (%d)", result.ErrMsg, result.ErrCode)
+	}
+
+	return result.MediaID, nil
+}
+
+// send 发送指定类型的应用消息
+func (c *WeworkChannel) send(msgType string, content interface{}) error {
 	// 获取访问令牌
 	token, err := c.getAccessToken()
 	if err != nil {
@@ -181,11 +290,9 @@ func (c *WeworkChannel) sendMessage(message string) error {
 	// 构建请求体
 	requestBody := map[string]interface{}{
 		"touser":  c.toUser,
-		"msgtype": "markdown",
+		"msgtype": msgType,
 		"agentid": c.agentID,
-		"markdown": map[string]string{
-			"content": message,
-		},
+		msgType:   content,
 	}
 
 	jsonData, err := json.Marshal(requestBody)
@@ -220,3 +327,18 @@ func (c *WeworkChannel) sendMessage(message string) error {
 
 	return nil
 }
+
+// truncateBytes 按字节数截断字符串，不会截断多字节字符
+func truncateBytes(text string, maxBytes int) string {
+	if len(text) <= maxBytes {
+		return text
+	}
+	cut := 0
+	for i := range text {
+		if i > maxBytes-len("...") {
+			break
+		}
+		cut = i
+	}
+	return text[:cut] + "..."
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"github.com/MccRay-s/alist2strm/model/tasklog"
+	"github.com/MccRay-s/alist2strm/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	// digestCheckInterval 检查摘要发送时间的间隔
+	digestCheckInterval = time.Minute
+	// maxDigestFailures 摘要中最多列出的失败记录数
+	maxDigestFailures = 10
+)
+
+// checkDigest 到达摘要计划发送时间时汇总上一周期的任务执行情况并发送
+// 服务停机错过发送时间时，启动后会补发最近一个周期的摘要
+func (s *NotificationService) checkDigest(now time.Time) {
+	s.mu.RLock()
+	if s.settings == nil || !s.settings.Enabled || !s.settings.Digest.Enabled {
+		s.mu.RUnlock()
+		return
+	}
+	digest := s.settings.Digest
+	lastDigestAt := s.lastDigestAt
+	s.mu.RUnlock()
+
+	fireAt, periodStart, err := digest.Period(now)
+	if err != nil {
+		s.logger.Warn("摘要设置无效，跳过摘要发送", zap.Error(err))
+		return
+	}
+	if !fireAt.After(lastDigestAt) {
+		return
+	}
+
+	// 通过通知队列判断本周期摘要是否已发送，避免服务重启后重复发送
+	sent, err := repository.Notification.HasQueueSince(notification.TemplateTypeDigest, fireAt)
+	if err != nil {
+		s.logger.Error("检查摘要发送记录失败", zap.Error(err))
+		return
+	}
+	if !sent {
+		if err := s.SendDigestNotification(periodStart, fireAt); err != nil {
+			s.logger.Error("发送摘要通知失败", zap.Error(err))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.lastDigestAt = fireAt
+	s.mu.Unlock()
+}
+
+// SendDigestNotification 汇总指定时间段内的任务执行记录和新增媒体，加入摘要发送渠道的通知队列
+func (s *NotificationService) SendDigestNotification(start, end time.Time) error {
+	s.mu.RLock()
+	if s.settings == nil || !s.settings.Enabled || len(s.channels) == 0 {
+		s.mu.RUnlock()
+		s.logger.Debug("通知功能已禁用，跳过摘要通知")
+		return nil
+	}
+	digest := s.settings.Digest
+	maxGroups, maxItems := s.settings.NewMedia.Limits()
+	channelNames := digest.Channels
+	if len(channelNames) == 0 {
+		channelNames = []string{s.settings.DefaultChannel}
+	}
+	var channels []string
+	for _, name := range channelNames {
+		if _, ok := s.channels[notification.NotificationChannelType(name)]; ok {
+			channels = append(channels, name)
+		} else {
+			s.logger.Warn("摘要发送渠道未启用，已跳过", zap.String("channel", name))
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(channels) == 0 {
+		return nil
+	}
+
+	data, err := buildDigestData(digest.Frequency, start, end, maxGroups, maxItems)
+	if err != nil {
+		return err
+	}
+	if data.Digest.TotalRuns == 0 && data.NewMediaCount == 0 {
+		s.logger.Info("摘要周期内没有任务执行记录，跳过摘要通知",
+			zap.Time("periodStart", start),
+			zap.Time("periodEnd", end))
+		return nil
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		s.logger.Error("序列化摘要数据失败", zap.Error(err))
+		return err
+	}
+
+	for _, channelType := range channels {
+		if err := s.enqueue(channelType, notification.TemplateTypeDigest, string(jsonData), data.TaskName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildDigestData 根据任务日志和文件历史生成摘要通知数据
+func buildDigestData(frequency string, start, end time.Time, maxGroups, maxItems int) (*notification.TaskNotificationData, error) {
+	logs, err := repository.TaskLog.ListFinishedBetween(start, end)
+	if err != nil {
+		return nil, fmt.Errorf("获取任务日志失败: %w", err)
+	}
+
+	summary := &notification.DigestSummary{
+		Frequency:   frequency,
+		PeriodStart: start.Format("2006-01-02 15:04"),
+		PeriodEnd:   end.Format("2006-01-02 15:04"),
+	}
+
+	taskSummaries := make(map[uint]*notification.DigestTaskSummary)
+	for _, taskLog := range logs {
+		taskSummary, exists := taskSummaries[taskLog.TaskID]
+		if !exists {
+			taskSummary = &notification.DigestTaskSummary{TaskID: taskLog.TaskID, TaskName: digestTaskName(taskLog.TaskID)}
+			taskSummaries[taskLog.TaskID] = taskSummary
+		}
+
+		taskSummary.Runs++
+		taskSummary.GeneratedFile += taskLog.GeneratedFile
+		taskSummary.SkipFile += taskLog.SkipFile
+		taskSummary.MetadataDownloaded += taskLog.MetadataDownloaded
+		taskSummary.SubtitleDownloaded += taskLog.SubtitleDownloaded
+		taskSummary.FailedCount += taskLog.FailedCount
+
+		summary.TotalRuns++
+		summary.GeneratedFile += taskLog.GeneratedFile
+		summary.FailedFiles += taskLog.FailedCount
+
+		if taskLog.Status == tasklog.TaskLogStatusCompleted {
+			taskSummary.Completed++
+			summary.CompletedRuns++
+			continue
+		}
+
+		taskSummary.Failed++
+		summary.FailedRuns++
+		if len(summary.Failures) >= maxDigestFailures {
+			summary.FailuresOmitted++
+			continue
+		}
+		failureTime := taskLog.StartTime
+		if taskLog.EndTime != nil {
+			failureTime = *taskLog.EndTime
+		}
+		summary.Failures = append(summary.Failures, notification.DigestFailure{
+			TaskName: taskSummary.TaskName,
+			Time:     failureTime.Format("2006-01-02 15:04:05"),
+			Message:  taskLog.Message,
+		})
+	}
+
+	for _, taskSummary := range taskSummaries {
+		summary.Tasks = append(summary.Tasks, *taskSummary)
+	}
+	sort.Slice(summary.Tasks, func(i, j int) bool {
+		return summary.Tasks[i].TaskName < summary.Tasks[j].TaskName
+	})
+
+	title := "每日摘要"
+	if frequency == notification.DigestFrequencyWeekly {
+		title = "每周摘要"
+	}
+	data := &notification.TaskNotificationData{
+		TaskName:      title,
+		Status:        "completed",
+		EventTime:     end.Format("2006-01-02 15:04:05"),
+		GeneratedFile: summary.GeneratedFile,
+		FailedCount:   summary.FailedFiles,
+		Digest:        summary,
+	}
+
+	paths, total, err := repository.FileHistory.ListStrmCreatedBetween(start, end, maxNewMediaPaths)
+	if err != nil {
+		return nil, fmt.Errorf("获取新增媒体失败: %w", err)
+	}
+	if total > 0 {
+		data.NewMedia, data.NewMediaOmitted = notification.BuildNewMediaGroups("/", paths, maxGroups, maxItems)
+		data.NewMediaOmitted += int(total) - len(paths)
+		data.NewMediaCount = int(total)
+	}
+
+	return data, nil
+}
+
+// digestTaskName 获取摘要中显示的任务名称，任务已删除时使用任务 ID
+func digestTaskName(taskID uint) string {
+	taskInfo, err := repository.Task.GetByID(taskID)
+	if err != nil || taskInfo == nil {
+		return fmt.Sprintf("任务 #%d（已删除）", taskID)
+	}
+	return taskInfo.Name
+}
+
+// digestReplacesRealtime 判断通知是否由摘要代替逐次发送
+func (s *NotificationService) digestReplacesRealtime(templateType notification.TemplateType) bool {
+	if templateType.IsCritical() || templateType == notification.TemplateTypeDigest {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings != nil && s.settings.Digest.Enabled && s.settings.Digest.SuppressRealtime
+}
+
+// quietUntil 判断渠道当前是否处于免打扰时段，是则返回时段结束时间；紧急通知不受免打扰限制
+func (s *NotificationService) quietUntil(channelType notification.NotificationChannelType, templateType notification.TemplateType, now time.Time) (time.Time, bool) {
+	if templateType.IsCritical() {
+		return time.Time{}, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.settings == nil {
+		return time.Time{}, false
+	}
+	return s.settings.Channels[string(channelType)].QuietHours.Until(now)
+}
+
+// deferNotification 将通知延迟到指定时间发送，不计入重试次数
+func (s *NotificationService) deferNotification(notif *notification.Queue, until time.Time) {
+	if notif.ID > 0 {
+		if err := repository.Notification.DeferNotification(notif.ID, until); err != nil {
+			s.logger.Error("延迟通知失败", zap.Error(err), zap.Uint("id", notif.ID))
+			return
+		}
+	}
+
+	notif.Status = notification.StatusPending
+	notif.NextRetryTime = &until
+	notif.UpdatedAt = time.Now()
+	s.requeueAfter(notif, time.Until(until))
+
+	s.logger.Info("渠道处于免打扰时段，通知已延迟发送",
+		zap.Uint("id", notif.ID),
+		zap.String("channelType", notif.ChannelType),
+		zap.Time("sendAt", until))
+}
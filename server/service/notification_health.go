@@ -0,0 +1,45 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"go.uber.org/zap"
+)
+
+// SendSourceHealthNotification 发送源站异常或恢复通知到默认渠道
+// 恢复通知与异常告警成对出现，因此不受摘要设置影响
+func (s *NotificationService) SendSourceHealthNotification(templateType notification.TemplateType, event *notification.SourceHealthEvent) error {
+	s.mu.RLock()
+	enabled := s.settings != nil && s.settings.Enabled && len(s.channels) > 0
+	defaultChannel := ""
+	if s.settings != nil {
+		defaultChannel = s.settings.DefaultChannel
+	}
+	s.mu.RUnlock()
+
+	if !enabled {
+		s.logger.Debug("通知功能已禁用，跳过源站状态通知", zap.String("source", event.Source))
+		return nil
+	}
+
+	data := &notification.TaskNotificationData{
+		TaskName:  event.SourceName,
+		Status:    "completed",
+		EventTime: time.Now().Format("2006-01-02 15:04:05"),
+		Health:    event,
+	}
+	if templateType == notification.TemplateTypeSourceDown {
+		data.Status = "failed"
+		data.ErrorMessage = event.Message
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		s.logger.Error("序列化源站状态通知数据失败", zap.Error(err))
+		return err
+	}
+
+	return s.enqueue(defaultChannel, templateType, string(jsonData), data.TaskName)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	notificationRequest "github.com/MccRay-s/alist2strm/model/notification/request"
+	notificationResponse "github.com/MccRay-s/alist2strm/model/notification/response"
+	"github.com/MccRay-s/alist2strm/repository"
+	"github.com/MccRay-s/alist2strm/service/notification_channel"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// GetSettings 获取通知设置
+func (s *NotificationService) GetSettings() (*notification.Settings, error) {
+	settings, err := repository.Notification.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("获取通知设置失败: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings 校验并保存通知设置，保存成功后立即重新加载
+func (s *NotificationService) UpdateSettings(settings *notification.Settings) error {
+	if err := validateNotificationSettings(settings); err != nil {
+		return err
+	}
+
+	if err := repository.Notification.UpdateSettings(settings); err != nil {
+		s.logger.Error("保存通知设置失败", zap.Error(err))
+		return fmt.Errorf("保存通知设置失败: %w", err)
+	}
+
+	if err := s.Reload(); err != nil {
+		return fmt.Errorf("通知设置已保存，但重新加载失败: %w", err)
+	}
+	return nil
+}
+
+// PreviewTemplate 使用示例数据或指定数据渲染模板，模板语法或字段错误时返回错误
+func (s *NotificationService) PreviewTemplate(req *notificationRequest.NotificationTemplatePreviewReq) (*notificationResponse.NotificationTemplatePreviewResp, error) {
+	templateType := notification.TemplateType(req.TemplateType)
+
+	content := req.Content
+	if content == "" {
+		settings, err := s.GetSettings()
+		if err != nil {
+			return nil, err
+		}
+		content = settings.Templates[req.TemplateType].Get(req.TemplateKey)
+		if content == "" {
+			content = notification.DefaultSettings().Templates[req.TemplateType].Get(req.TemplateKey)
+		}
+		if content == "" {
+			return nil, fmt.Errorf("未找到模板: %s/%s", req.TemplateType, req.TemplateKey)
+		}
+	}
+
+	data := req.Data
+	if data == nil {
+		data = notification.SampleTaskNotificationData(templateType)
+	}
+
+	rendered, err := notification_channel.RenderPreview(req.TemplateKey, content, data)
+	if err != nil {
+		return nil, fmt.Errorf("模板渲染失败: %w", err)
+	}
+
+	return &notificationResponse.NotificationTemplatePreviewResp{Content: rendered}, nil
+}
+
+// GetQueueList 分页获取通知队列
+func (s *NotificationService) GetQueueList(req *notificationRequest.NotificationQueueListReq) (*notificationResponse.NotificationQueueListResp, error) {
+	list, total, err := repository.Notification.ListQueue(req)
+	if err != nil {
+		return nil, fmt.Errorf("获取通知队列失败: %w", err)
+	}
+
+	return &notificationResponse.NotificationQueueListResp{
+		List:     list,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// RetryQueueItem 手动重试一条发送失败的通知
+func (s *NotificationService) RetryQueueItem(id uint) error {
+	notif, err := repository.Notification.GetQueueByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("通知不存在")
+		}
+		return fmt.Errorf("获取通知失败: %w", err)
+	}
+
+	if notif.Status != notification.StatusFailed {
+		return fmt.Errorf("仅发送失败的通知可以重试，当前状态: %s", notif.Status)
+	}
+
+	return s.requeue(notif)
+}
+
+// RetryFailedQueue 重试全部发送失败的通知，返回重新入队数量
+func (s *NotificationService) RetryFailedQueue() (*notificationResponse.NotificationQueueBatchResp, error) {
+	notifications, err := repository.Notification.GetQueueByStatus(notification.StatusFailed)
+	if err != nil {
+		return nil, fmt.Errorf("获取失败通知失败: %w", err)
+	}
+
+	var count int64
+	for _, notif := range notifications {
+		if err := s.requeue(notif); err != nil {
+			s.logger.Error("重试通知失败", zap.Uint("id", notif.ID), zap.Error(err))
+			continue
+		}
+		count++
+	}
+
+	return &notificationResponse.NotificationQueueBatchResp{Count: count}, nil
+}
+
+// DeleteQueueItem 删除一条通知，处理中的通知不允许删除
+func (s *NotificationService) DeleteQueueItem(id uint) error {
+	notif, err := repository.Notification.GetQueueByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("通知不存在")
+		}
+		return fmt.Errorf("获取通知失败: %w", err)
+	}
+
+	if notif.Status == notification.StatusProcessing {
+		return fmt.Errorf("通知正在发送中，无法删除")
+	}
+
+	if err := repository.Notification.DeleteQueue(id); err != nil {
+		return fmt.Errorf("删除通知失败: %w", err)
+	}
+	return nil
+}
+
+// CleanQueue 按状态批量删除通知
+func (s *NotificationService) CleanQueue(req *notificationRequest.NotificationQueueCleanReq) (*notificationResponse.NotificationQueueBatchResp, error) {
+	count, err := repository.Notification.DeleteQueueByStatus(notification.Status(req.Status))
+	if err != nil {
+		return nil, fmt.Errorf("删除通知失败: %w", err)
+	}
+
+	s.logger.Info("已按状态清理通知队列", zap.String("status", req.Status), zap.Int64("count", count))
+	return &notificationResponse.NotificationQueueBatchResp{Count: count}, nil
+}
+
+// requeue 重置通知的重试状态并放回内存队列；通知功能未运行时保留在数据库中，待启动后加载
+func (s *NotificationService) requeue(notif *notification.Queue) error {
+	if err := repository.Notification.ResetForRetry(notif.ID); err != nil {
+		return fmt.Errorf("重置通知状态失败: %w", err)
+	}
+
+	notif.Status = notification.StatusPending
+	notif.RetryCount = 0
+	notif.NextRetryTime = nil
+	notif.ErrorMessage = ""
+
+	s.mu.RLock()
+	processing := s.queueProcessing
+	s.mu.RUnlock()
+	if !processing {
+		return nil
+	}
+
+	select {
+	case s.memoryQueue <- notif:
+		s.logger.Info("通知已手动重新入队", zap.Uint("id", notif.ID))
+	default:
+		s.logger.Warn("内存队列已满，通知将在下次加载时处理", zap.Uint("id", notif.ID))
+	}
+	return nil
+}
+
+// validateNotificationSettings 校验通知设置，包括默认渠道、队列参数以及全部模板能否正常渲染
+func validateNotificationSettings(settings *notification.Settings) error {
+	if settings == nil {
+		return fmt.Errorf("通知设置不能为空")
+	}
+
+	supported := make(map[string]bool)
+	for _, channelType := range notification_channel.SupportedChannelTypes() {
+		supported[string(channelType)] = true
+	}
+
+	for name, channelConfig := range settings.Channels {
+		if !supported[name] {
+			return fmt.Errorf("不支持的通知渠道: %s", name)
+		}
+		if err := channelConfig.QuietHours.Validate(); err != nil {
+			return fmt.Errorf("渠道 %s 的%w", name, err)
+		}
+	}
+
+	if _, err := telegramBotConfigFrom(settings); err != nil {
+		return err
+	}
+
+	// 存在已启用的渠道时，默认渠道必须指向其中之一，否则通知将无法发送
+	hasEnabledChannel := false
+	for _, channelConfig := range settings.Channels {
+		if channelConfig.Enabled {
+			hasEnabledChannel = true
+			break
+		}
+	}
+	if settings.Enabled && hasEnabledChannel {
+		channelConfig, exists := settings.Channels[settings.DefaultChannel]
+		if !exists || !channelConfig.Enabled {
+			return fmt.Errorf("默认渠道 %s 未配置或未启用", settings.DefaultChannel)
+		}
+	}
+
+	if settings.QueueSettings.MaxRetries < 0 {
+		return fmt.Errorf("最大重试次数不能小于 0")
+	}
+	if settings.QueueSettings.RetryInterval <= 0 {
+		return fmt.Errorf("重试间隔必须大于 0 秒")
+	}
+
+	if settings.Digest.Enabled {
+		if err := settings.Digest.Validate(); err != nil {
+			return err
+		}
+		for _, name := range settings.Digest.Channels {
+			if !supported[name] {
+				return fmt.Errorf("摘要发送渠道不支持: %s", name)
+			}
+		}
+	}
+
+	var errs []string
+	for templateType, templates := range settings.Templates {
+		if !notification.IsValidTemplateType(notification.TemplateType(templateType)) {
+			errs = append(errs, fmt.Sprintf("不支持的模板类型: %s", templateType))
+			continue
+		}
+		data := notification.SampleTaskNotificationData(notification.TemplateType(templateType))
+		for key, content := range templates {
+			if content == "" {
+				continue
+			}
+			if _, err := notification_channel.RenderPreview(key, content, data); err != nil {
+				errs = append(errs, fmt.Sprintf("模板 %s/%s 无效: %v", templateType, key, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
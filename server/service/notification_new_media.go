@@ -0,0 +1,172 @@
+package service
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"github.com/MccRay-s/alist2strm/model/task"
+	"go.uber.org/zap"
+)
+
+const (
+	// newMediaBatchDelay webhook 新增媒体的合并窗口，窗口内没有新文件时才发送通知
+	newMediaBatchDelay = 30 * time.Second
+	// maxNewMediaPaths 单次通知最多保留的新增文件路径数，超出部分只计数
+	maxNewMediaPaths = 500
+	// maxPosterPaths 用于查找海报的候选路径数
+	maxPosterPaths = 5
+)
+
+// newMediaBatch webhook 新增媒体的待发送批次
+type newMediaBatch struct {
+	taskInfo *task.Task
+	paths    []string
+	count    int
+	timer    *time.Timer
+}
+
+var (
+	newMediaBatchMu sync.Mutex
+	newMediaBatches = make(map[uint]*newMediaBatch)
+)
+
+// CollectNewMedia 收集 webhook 事件中新生成的 STRM 文件，同一任务的文件在合并窗口结束后作为一条新增媒体通知发送
+// 仅对订阅了 newMedia 事件的任务生效
+func (s *NotificationService) CollectNewMedia(taskInfo *task.Task, strmPath string) {
+	if !taskWantsEvent(taskInfo, task.NotifyEventNewMedia) {
+		return
+	}
+
+	newMediaBatchMu.Lock()
+	defer newMediaBatchMu.Unlock()
+
+	batch, exists := newMediaBatches[taskInfo.ID]
+	if !exists {
+		batch = &newMediaBatch{taskInfo: taskInfo}
+		newMediaBatches[taskInfo.ID] = batch
+		batch.timer = time.AfterFunc(newMediaBatchDelay, func() {
+			s.flushNewMediaBatch(taskInfo.ID)
+		})
+	} else {
+		batch.timer.Reset(newMediaBatchDelay)
+	}
+
+	batch.count++
+	if len(batch.paths) < maxNewMediaPaths {
+		batch.paths = append(batch.paths, strmPath)
+	}
+}
+
+// flushNewMediaBatch 发送任务当前累积的新增媒体通知
+func (s *NotificationService) flushNewMediaBatch(taskID uint) {
+	newMediaBatchMu.Lock()
+	batch, exists := newMediaBatches[taskID]
+	delete(newMediaBatches, taskID)
+	newMediaBatchMu.Unlock()
+
+	if !exists || len(batch.paths) == 0 {
+		return
+	}
+
+	if err := s.SendNewMediaNotification(batch.taskInfo, batch.paths, batch.count); err != nil {
+		s.logger.Error("发送新增媒体通知失败", zap.String("taskName", batch.taskInfo.Name), zap.Error(err))
+	}
+}
+
+// SendNewMediaNotification 发送新增媒体通知，count 为新增总数（可能大于 paths 中保留的路径数）
+func (s *NotificationService) SendNewMediaNotification(taskInfo *task.Task, paths []string, count int) error {
+	s.mu.RLock()
+	enabled := s.settings != nil && s.settings.Enabled && len(s.channels) > 0
+	defaultChannel := ""
+	if s.settings != nil {
+		defaultChannel = s.settings.DefaultChannel
+	}
+	s.mu.RUnlock()
+
+	if !enabled {
+		s.logger.Debug("通知功能已禁用，跳过发送新增媒体通知")
+		return nil
+	}
+	if s.digestReplacesRealtime(notification.TemplateTypeNewMedia) {
+		s.logger.Debug("已启用摘要通知，跳过新增媒体通知", zap.String("taskName", taskInfo.Name))
+		return nil
+	}
+
+	data := &notification.TaskNotificationData{
+		TaskID:     taskInfo.ID,
+		TaskName:   taskInfo.Name,
+		Status:     "completed",
+		EventTime:  time.Now().Format("2006-01-02 15:04:05"),
+		SourcePath: taskInfo.SourcePath,
+		TargetPath: taskInfo.TargetPath,
+	}
+	s.fillNewMediaData(data, paths, count)
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		s.logger.Error("序列化通知数据失败", zap.Error(err))
+		return err
+	}
+
+	for _, channelType := range notifyChannelsForTask(taskInfo, defaultChannel) {
+		if err := s.enqueue(channelType, notification.TemplateTypeNewMedia, string(jsonData), taskInfo.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillNewMediaData 填充新增媒体分组、截断信息以及海报候选路径
+func (s *NotificationService) fillNewMediaData(data *notification.TaskNotificationData, paths []string, count int) {
+	s.mu.RLock()
+	var newMediaSettings notification.NewMediaSettings
+	if s.settings != nil {
+		newMediaSettings = s.settings.NewMedia
+	}
+	s.mu.RUnlock()
+
+	maxGroups, maxItems := newMediaSettings.Limits()
+	data.NewMedia, data.NewMediaOmitted = notification.BuildNewMediaGroups(data.TargetPath, paths, maxGroups, maxItems)
+	data.NewMediaCount = len(paths)
+	if count > len(paths) {
+		// 超出保留上限的文件只计入未列出数量
+		data.NewMediaOmitted += count - len(paths)
+		data.NewMediaCount = count
+	}
+
+	if newMediaSettings.IncludePoster && Emby.IsConfigured() {
+		// 每个目录取一个文件作为海报候选，避免同一剧集重复查找
+		seenDirs := make(map[string]bool)
+		for _, p := range paths {
+			dir := filepath.Dir(p)
+			if seenDirs[dir] {
+				continue
+			}
+			seenDirs[dir] = true
+			data.PosterPaths = append(data.PosterPaths, p)
+			if len(data.PosterPaths) >= maxPosterPaths {
+				break
+			}
+		}
+	}
+	data.Link = Emby.GetWebURL()
+}
+
+// attachPoster 从 Emby 获取海报并附加到通知数据
+func (s *NotificationService) attachPoster(data *notification.TaskNotificationData) error {
+	poster, contentType, err := Emby.GetPosterByLocalPaths(data.PosterPaths)
+	if err != nil {
+		return err
+	}
+	data.Poster = poster
+	data.PosterType = contentType
+	return nil
+}
+
+// supportsPoster 判断渠道是否支持发送海报
+func supportsPoster(channelType notification.NotificationChannelType) bool {
+	return channelType == notification.ChannelTypeTelegram || channelType == notification.ChannelTypeWework
+}
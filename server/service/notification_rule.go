@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"github.com/MccRay-s/alist2strm/model/task"
+	"github.com/MccRay-s/alist2strm/service/notification_channel"
+)
+
+// validateNotifyRule 校验任务的通知规则
+func validateNotifyRule(taskInfo *task.Task) error {
+	events := taskInfo.NotifyEventList()
+	for _, event := range events {
+		if !task.IsValidNotifyEvent(event) {
+			return fmt.Errorf("不支持的通知事件: %s", event)
+		}
+		if event == task.NotifyEventNone && len(events) > 1 {
+			return fmt.Errorf("通知事件 none 不能与其他事件同时使用")
+		}
+	}
+
+	supported := make(map[string]bool)
+	for _, channelType := range notification_channel.SupportedChannelTypes() {
+		supported[string(channelType)] = true
+	}
+	for _, channel := range taskInfo.NotifyChannelList() {
+		if !supported[channel] {
+			return fmt.Errorf("不支持的通知渠道: %s", channel)
+		}
+	}
+
+	if taskInfo.NotifyMinGenerated < 0 {
+		return fmt.Errorf("通知阈值不能小于 0")
+	}
+	return nil
+}
+
+// runNotifyEvents 根据运行结果归类本次运行触发的通知事件
+func runNotifyEvents(status string, data *notification.TaskNotificationData) []string {
+	if status != "completed" {
+		return []string{task.NotifyEventFailed}
+	}
+
+	events := []string{task.NotifyEventCompleted}
+	if data.FailedCount > 0 {
+		events = append(events, task.NotifyEventPartialFailed)
+	}
+	if data.GeneratedFile > 0 {
+		events = append(events, task.NotifyEventNewMedia)
+	} else {
+		events = append(events, task.NotifyEventNoNew)
+	}
+	return events
+}
+
+// shouldNotifyTask 判断本次运行是否满足任务的通知规则
+// 未配置事件的历史任务按默认规则处理；生成文件数阈值只作用于成功的运行，失败总会按事件规则通知
+func shouldNotifyTask(taskInfo *task.Task, status string, data *notification.TaskNotificationData) (bool, string) {
+	selected := taskInfo.NotifyEventList()
+	if len(selected) == 0 {
+		selected = []string{task.NotifyEventCompleted, task.NotifyEventFailed}
+	}
+
+	wanted := make(map[string]bool, len(selected))
+	for _, event := range selected {
+		wanted[event] = true
+	}
+	if wanted[task.NotifyEventNone] {
+		return false, "任务已关闭通知"
+	}
+
+	matched := false
+	for _, event := range runNotifyEvents(status, data) {
+		if wanted[event] {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false, "运行结果不在任务的通知事件中"
+	}
+
+	if status == "completed" && data.GeneratedFile < taskInfo.NotifyMinGenerated {
+		return false, fmt.Sprintf("生成文件数 %d 未达到通知阈值 %d", data.GeneratedFile, taskInfo.NotifyMinGenerated)
+	}
+	return true, ""
+}
+
+// taskWantsEvent 判断任务是否订阅了指定的通知事件
+func taskWantsEvent(taskInfo *task.Task, event string) bool {
+	selected := taskInfo.NotifyEventList()
+	if len(selected) == 0 {
+		selected = []string{task.NotifyEventCompleted, task.NotifyEventFailed}
+	}
+	for _, item := range selected {
+		if item == event {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyChannelsForTask 获取任务的通知渠道，未配置时使用默认渠道
+func notifyChannelsForTask(taskInfo *task.Task, defaultChannel string) []string {
+	if channels := taskInfo.NotifyChannelList(); len(channels) > 0 {
+		return channels
+	}
+	if defaultChannel == "" {
+		defaultChannel = string(notification.ChannelTypeTelegram)
+	}
+	return []string{defaultChannel}
+}
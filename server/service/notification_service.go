@@ -2,6 +2,7 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -26,6 +27,8 @@ type NotificationService struct {
 	queueProcessing bool
 	stopChan        chan struct{}
 	cleanupStopChan chan struct{}
+	// 最近一次已处理的摘要计划发送时间，避免同一周期重复发送
+	lastDigestAt time.Time
 }
 
 // OnConfigUpdate 实现配置更新监听器接口
@@ -80,6 +83,9 @@ func (s *NotificationService) Initialize() error {
 	s.settings = settings
 	s.mu.Unlock()
 
+	// Telegram 机器人独立于通知开关，按渠道中的机器人配置启动
+	GetTelegramBotService().Apply(settings)
+
 	// 如果配置不存在，或通知未开启，亦或没有通知渠道，则不处理配置信息，完成初始化
 	if !s.shouldStartNotificationProcessing(settings) {
 		s.logger.Info("通知服务初始化完成（通知功能未启用或无可用渠道）")
@@ -118,6 +124,8 @@ func (s *NotificationService) Reload() error {
 		return err
 	}
 
+	GetTelegramBotService().Apply(settings)
+
 	// 检查当前处理状态
 	s.mu.RLock()
 	wasProcessing := s.queueProcessing
@@ -236,6 +244,15 @@ func (s *NotificationService) SendTaskNotification(taskInfo *task.Task, taskLogI
 	if failedCount, ok := stats["failed_count"].(int); ok {
 		data.FailedCount = failedCount
 	}
+	if scopePath, ok := stats["scope_path"].(string); ok && scopePath != "" {
+		data.ScopePath = scopePath
+	}
+	if sourcePath, ok := stats["source_path"].(string); ok && sourcePath != "" {
+		data.SourcePath = sourcePath
+	}
+	if targetPath, ok := stats["target_path"].(string); ok && targetPath != "" {
+		data.TargetPath = targetPath
+	}
 
 	// 设置错误信息（如果有）
 	if status == "failed" && stats["message"] != nil {
@@ -244,12 +261,33 @@ func (s *NotificationService) SendTaskNotification(taskInfo *task.Task, taskLogI
 		}
 	}
 
-	// 选择模板类型
+	// 按任务的通知规则过滤，避免无新增文件的定时任务反复通知
+	if ok, reason := shouldNotifyTask(taskInfo, status, data); !ok {
+		s.logger.Debug("根据任务通知规则跳过通知",
+			zap.String("taskName", taskInfo.Name),
+			zap.String("reason", reason))
+		return nil
+	}
+
+	// 选择模板类型：任务订阅了 newMedia 事件且本次有新增媒体时，使用新增媒体模板代替任务完成模板
 	var templateType notification.TemplateType
-	if status == "completed" {
-		templateType = notification.TemplateTypeTaskComplete
-	} else {
+	newMediaPaths, _ := stats["new_media"].([]string)
+	switch {
+	case status != "completed":
 		templateType = notification.TemplateTypeTaskFailed
+	case len(newMediaPaths) > 0 && taskWantsEvent(taskInfo, task.NotifyEventNewMedia):
+		templateType = notification.TemplateTypeNewMedia
+		s.fillNewMediaData(data, newMediaPaths, data.GeneratedFile)
+	default:
+		templateType = notification.TemplateTypeTaskComplete
+	}
+
+	// 启用摘要并关闭逐次通知时，非紧急通知改由定期摘要汇总发送
+	if s.digestReplacesRealtime(templateType) {
+		s.logger.Debug("已启用摘要通知，跳过逐次通知",
+			zap.String("taskName", taskInfo.Name),
+			zap.String("templateType", string(templateType)))
+		return nil
 	}
 
 	// 序列化通知数据
@@ -259,14 +297,20 @@ func (s *NotificationService) SendTaskNotification(taskInfo *task.Task, taskLogI
 		return err
 	}
 
-	// 配置默认渠道
-	channelType := defaultChannel
-	if channelType == "" {
-		channelType = string(notification.ChannelTypeTelegram)
+	// 每个通知渠道单独入队，独立重试
+	for _, channelType := range notifyChannelsForTask(taskInfo, defaultChannel) {
+		if err := s.enqueue(channelType, templateType, string(jsonData), taskInfo.Name); err != nil {
+			return err
+		}
 	}
 
+	return nil
+}
+
+// enqueue 将通知保存到数据库并加入内存队列
+func (s *NotificationService) enqueue(channelType string, templateType notification.TemplateType, payload string, taskName string) error {
 	// 先保存到数据库，获取ID
-	queueID, err := repository.Notification.AddToQueueWithID(channelType, string(templateType), string(jsonData))
+	queueID, err := repository.Notification.AddToQueueWithID(channelType, string(templateType), payload)
 	if err != nil {
 		s.logger.Error("将通知添加到数据库失败", zap.Error(err))
 		return err
@@ -278,7 +322,7 @@ func (s *NotificationService) SendTaskNotification(taskInfo *task.Task, taskLogI
 		ChannelType:  channelType,
 		TemplateType: string(templateType),
 		Status:       notification.StatusPending,
-		Payload:      string(jsonData),
+		Payload:      payload,
 		RetryCount:   0,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
@@ -290,15 +334,65 @@ func (s *NotificationService) SendTaskNotification(taskInfo *task.Task, taskLogI
 		s.logger.Info("通知已加入内存队列",
 			zap.String("channelType", channelType),
 			zap.String("templateType", string(templateType)),
-			zap.String("taskName", taskInfo.Name))
+			zap.String("taskName", taskName))
 	default:
 		s.logger.Warn("内存队列已满，通知将稍后处理",
-			zap.String("taskName", taskInfo.Name))
+			zap.String("taskName", taskName))
 	}
 
 	return nil
 }
 
+// SendTestNotification 使用示例数据向指定渠道同步发送一条测试通知
+// 测试时会强制启用该渠道，便于在正式启用前验证配置是否正确
+func (s *NotificationService) SendTestNotification(channelType notification.NotificationChannelType, templateType notification.TemplateType) error {
+	s.mu.RLock()
+	current := s.settings
+	s.mu.RUnlock()
+
+	if current == nil {
+		settings, err := s.loadNotificationSettings()
+		if err != nil {
+			return err
+		}
+		current = settings
+	}
+
+	channelConfig, exists := current.Channels[string(channelType)]
+	if !exists {
+		return fmt.Errorf("通知渠道未配置: %s", channelType)
+	}
+
+	// 复制一份设置，避免修改正在使用的配置
+	testSettings := *current
+	testSettings.Channels = make(map[string]notification.ChannelConfig, len(current.Channels))
+	for name, config := range current.Channels {
+		testSettings.Channels[name] = config
+	}
+	channelConfig.Enabled = true
+	testSettings.Channels[string(channelType)] = channelConfig
+
+	channel, ok := notification_channel.NewChannel(channelType, s.logger, &testSettings)
+	if !ok {
+		return fmt.Errorf("不支持的通知渠道: %s", channelType)
+	}
+	if !channel.IsEnabled() {
+		return fmt.Errorf("通知渠道配置不完整: %s", channelType)
+	}
+
+	if templateType == "" {
+		templateType = notification.TemplateTypeTaskComplete
+	}
+
+	if err := channel.Send(templateType, notification.SampleTaskNotificationData(templateType)); err != nil {
+		s.logger.Warn("发送测试通知失败", zap.String("channel", string(channelType)), zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("测试通知已发送", zap.String("channel", string(channelType)))
+	return nil
+}
+
 // shouldStartNotificationProcessing 判断是否应该启动通知处理
 func (s *NotificationService) shouldStartNotificationProcessing(settings *notification.Settings) bool {
 	if settings == nil {
@@ -362,18 +456,13 @@ func (s *NotificationService) initializeChannels(settings *notification.Settings
 	// 创建通道实例
 	channels := make(map[notification.NotificationChannelType]notification_channel.Channel)
 
-	// 初始化 Telegram 渠道
-	telegramChannel := notification_channel.NewTelegramChannel(s.logger, settings)
-	if telegramChannel.IsEnabled() {
-		channels[telegramChannel.GetType()] = telegramChannel
-		s.logger.Info("Telegram 通知渠道已启用")
-	}
-
-	// 初始化企业微信渠道
-	weworkChannel := notification_channel.NewWeworkChannel(s.logger, settings)
-	if weworkChannel.IsEnabled() {
-		channels[weworkChannel.GetType()] = weworkChannel
-		s.logger.Info("企业微信通知渠道已启用")
+	// 按注册表依次初始化所有渠道，仅保留配置完整且已启用的渠道
+	for _, channelType := range notification_channel.SupportedChannelTypes() {
+		channel, _ := notification_channel.NewChannel(channelType, s.logger, settings)
+		if channel.IsEnabled() {
+			channels[channel.GetType()] = channel
+			s.logger.Info("通知渠道已启用", zap.String("channel", string(channel.GetType())))
+		}
 	}
 
 	// 更新通道
@@ -494,6 +583,15 @@ func (s *NotificationService) processNotification(notif *notification.Queue) {
 		zap.String("channelType", notif.ChannelType),
 		zap.Int("retryCount", notif.RetryCount))
 
+	// 配置重载、重试或免打扰延迟可能使同一通知多次进入内存队列，以数据库中的状态为准跳过已处理或已删除的通知
+	if notif.ID > 0 {
+		current, err := repository.Notification.GetQueueByID(notif.ID)
+		if err != nil || current.Status == notification.StatusSent || current.Status == notification.StatusProcessing {
+			s.logger.Debug("通知已处理或已删除，跳过", zap.Uint("id", notif.ID))
+			return
+		}
+	}
+
 	// 更新状态为处理中（仅在数据库中有ID时更新）
 	if notif.ID > 0 {
 		err := repository.Notification.UpdateNotificationStatus(notif.ID, notification.StatusProcessing, "")
@@ -518,6 +616,12 @@ func (s *NotificationService) processNotification(notif *notification.Queue) {
 		return
 	}
 
+	// 渠道处于免打扰时段时，非紧急通知延迟到时段结束后发送
+	if until, quiet := s.quietUntil(channelType, notification.TemplateType(notif.TemplateType), time.Now()); quiet {
+		s.deferNotification(notif, until)
+		return
+	}
+
 	// 解析数据
 	var data notification.TaskNotificationData
 	err := json.Unmarshal([]byte(notif.Payload), &data)
@@ -530,6 +634,18 @@ func (s *NotificationService) processNotification(notif *notification.Queue) {
 		return
 	}
 
+	// 新增媒体通知在发送前尝试从 Emby 获取海报，媒体尚未入库时借助重试机制稍后再试
+	if notif.TemplateType == string(notification.TemplateTypeNewMedia) && supportsPoster(channelType) && len(data.PosterPaths) > 0 {
+		if err := s.attachPoster(&data); err != nil {
+			if errors.Is(err, ErrEmbyItemNotFound) && notif.RetryCount < maxRetries {
+				s.logger.Info("媒体尚未在 Emby 入库，稍后重试以获取海报", zap.Uint("id", notif.ID))
+				s.retryOrFail(notif, "等待 Emby 入库以获取海报", maxRetries, retryInterval)
+				return
+			}
+			s.logger.Warn("获取海报失败，将发送不带海报的通知", zap.Uint("id", notif.ID), zap.Error(err))
+		}
+	}
+
 	// 发送通知
 	err = channel.Send(notification.TemplateType(notif.TemplateType), &data)
 	if err != nil {
@@ -539,54 +655,7 @@ func (s *NotificationService) processNotification(notif *notification.Queue) {
 			zap.String("channelType", notif.ChannelType),
 			zap.String("taskName", data.TaskName))
 
-		// 检查是否应该重试
-		if notif.RetryCount < maxRetries {
-			// 计算新的重试次数和下次重试时间
-			newRetryCount := notif.RetryCount + 1
-			nextRetry := time.Now().Add(time.Duration(retryInterval) * time.Second)
-
-			// 先更新数据库状态为pending，设置下次重试时间和重试次数
-			if notif.ID > 0 {
-				err = repository.Notification.RequeueNotification(notif.ID, newRetryCount, nextRetry, errMsg)
-				if err != nil {
-					s.logger.Error("重新入队通知失败", zap.Error(err), zap.Uint("id", notif.ID))
-					return
-				}
-			}
-
-			// 更新内存中的重试次数（与数据库保持一致）
-			notif.RetryCount = newRetryCount
-			notif.Status = notification.StatusPending
-			notif.UpdatedAt = time.Now()
-
-			// 延迟后重新入内存队列
-			go func(retryNotif *notification.Queue, delay time.Duration) {
-				time.Sleep(delay)
-				select {
-				case s.memoryQueue <- retryNotif:
-					s.logger.Info("通知已重新入队",
-						zap.Uint("id", retryNotif.ID),
-						zap.Int("retryCount", retryNotif.RetryCount))
-				default:
-					s.logger.Warn("内存队列已满，无法重新入队通知", zap.Uint("id", retryNotif.ID))
-					// 如果内存队列满了，通知仍在数据库中保持pending状态，下次服务重启时会重新加载
-				}
-			}(notif, time.Duration(retryInterval)*time.Second)
-
-			s.logger.Info("通知将在稍后重试",
-				zap.Uint("id", notif.ID),
-				zap.Int("retryCount", newRetryCount),
-				zap.Time("nextRetryTime", nextRetry))
-		} else {
-			// 达到最大重试次数，标记为最终失败
-			if notif.ID > 0 {
-				err = repository.Notification.UpdateNotificationStatus(notif.ID, notification.StatusFailed, errMsg)
-				if err != nil {
-					s.logger.Error("更新通知状态失败", zap.Error(err), zap.Uint("id", notif.ID))
-				}
-			}
-			s.logger.Warn("通知达到最大重试次数，已标记为失败", zap.Uint("id", notif.ID))
-		}
+		s.retryOrFail(notif, errMsg, maxRetries, retryInterval)
 		return
 	}
 
@@ -604,16 +673,83 @@ func (s *NotificationService) processNotification(notif *notification.Queue) {
 		zap.String("taskName", data.TaskName))
 }
 
-// startCleanupTask 启动定期清理任务
+// retryOrFail 未达到最大重试次数时延迟重新入队，否则标记为最终失败
+func (s *NotificationService) retryOrFail(notif *notification.Queue, errMsg string, maxRetries, retryInterval int) {
+	// 检查是否应该重试
+	if notif.RetryCount < maxRetries {
+		// 计算新的重试次数和下次重试时间
+		newRetryCount := notif.RetryCount + 1
+		nextRetry := time.Now().Add(time.Duration(retryInterval) * time.Second)
+
+		// 先更新数据库状态为pending，设置下次重试时间和重试次数
+		if notif.ID > 0 {
+			err := repository.Notification.RequeueNotification(notif.ID, newRetryCount, nextRetry, errMsg)
+			if err != nil {
+				s.logger.Error("重新入队通知失败", zap.Error(err), zap.Uint("id", notif.ID))
+				return
+			}
+		}
+
+		// 更新内存中的重试次数（与数据库保持一致）
+		notif.RetryCount = newRetryCount
+		notif.Status = notification.StatusPending
+		notif.UpdatedAt = time.Now()
+
+		// 延迟后重新入内存队列
+		s.requeueAfter(notif, time.Duration(retryInterval)*time.Second)
+
+		s.logger.Info("通知将在稍后重试",
+			zap.Uint("id", notif.ID),
+			zap.Int("retryCount", newRetryCount),
+			zap.Time("nextRetryTime", nextRetry))
+	} else {
+		// 达到最大重试次数，标记为最终失败
+		if notif.ID > 0 {
+			err := repository.Notification.UpdateNotificationStatus(notif.ID, notification.StatusFailed, errMsg)
+			if err != nil {
+				s.logger.Error("更新通知状态失败", zap.Error(err), zap.Uint("id", notif.ID))
+			}
+		}
+		s.logger.Warn("通知达到最大重试次数，已标记为失败", zap.Uint("id", notif.ID))
+	}
+}
+
+// requeueAfter 延迟后将通知重新加入内存队列
+func (s *NotificationService) requeueAfter(notif *notification.Queue, delay time.Duration) {
+	go func(retryNotif *notification.Queue) {
+		time.Sleep(delay)
+		select {
+		case s.memoryQueue <- retryNotif:
+			s.logger.Info("通知已重新入队",
+				zap.Uint("id", retryNotif.ID),
+				zap.Int("retryCount", retryNotif.RetryCount))
+		default:
+			s.logger.Warn("内存队列已满，无法重新入队通知", zap.Uint("id", retryNotif.ID))
+			// 如果内存队列满了，通知仍在数据库中保持pending状态，下次服务重启时会重新加载
+		}
+	}(notif)
+}
+
+// startCleanupTask 启动定期清理任务，同时负责按计划发送摘要通知
 func (s *NotificationService) startCleanupTask() {
 	// 每24小时清理一次历史数据
 	ticker := time.NewTicker(24 * time.Hour)
 	defer ticker.Stop()
 
+	// 每分钟检查一次是否到达摘要发送时间
+	digestTicker := time.NewTicker(digestCheckInterval)
+	defer digestTicker.Stop()
+
+	s.mu.RLock()
+	stopCh := s.cleanupStopChan
+	s.mu.RUnlock()
+
 	s.logger.Info("通知清理任务已启动")
 
 	for {
 		select {
+		case now := <-digestTicker.C:
+			s.checkDigest(now)
 		case <-ticker.C:
 			// 清理30天前的已发送通知
 			cleanCutoff := time.Now().AddDate(0, 0, -30)
@@ -623,7 +759,7 @@ func (s *NotificationService) startCleanupTask() {
 			} else {
 				s.logger.Info("已清理历史通知数据")
 			}
-		case <-s.cleanupStopChan:
+		case <-stopCh:
 			s.logger.Info("收到停止信号，清理任务即将退出")
 			return
 		}
@@ -639,6 +775,10 @@ func (s *NotificationService) stopNotificationFeatures() {
 
 	// 停止清理任务
 	s.mu.Lock()
+	if s.cleanupStopChan != nil {
+		close(s.cleanupStopChan)
+		s.cleanupStopChan = nil
+	}
 	s.channels = make(map[notification.NotificationChannelType]notification_channel.Channel)
 	s.mu.Unlock()
 
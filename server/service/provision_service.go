@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/MccRay-s/alist2strm/config"
+	"github.com/MccRay-s/alist2strm/model/backup"
+	backupRequest "github.com/MccRay-s/alist2strm/model/backup/request"
+	backupResponse "github.com/MccRay-s/alist2strm/model/backup/response"
+	taskRequest "github.com/MccRay-s/alist2strm/model/task/request"
+	"github.com/MccRay-s/alist2strm/repository"
+	"github.com/MccRay-s/alist2strm/utils"
+)
+
+// 声明式配置的同步模式
+const (
+	// provisionModeSeed 仅创建不存在的任务和配置，已存在的保持不变
+	provisionModeSeed = "seed"
+	// provisionModeSync 以文件为准，覆盖同名任务和同代码的配置，并禁用文件中未声明的任务
+	provisionModeSync = "sync"
+)
+
+// provisionEnvPattern 声明文件中引用环境变量的写法，如 ${ALIST_TOKEN}，便于将密钥放在文件之外
+var provisionEnvPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+type ProvisionService struct {
+	mu        sync.Mutex
+	watchOnce sync.Once
+}
+
+// 包级别的全局实例
+var Provision = &ProvisionService{}
+
+// Enabled 是否配置了声明文件
+func (s *ProvisionService) Enabled() bool {
+	return config.GlobalConfig != nil && config.GlobalConfig.Provision.File != ""
+}
+
+// Reconcile 读取声明文件并同步到数据库，文件中任一条目校验失败时不写入任何数据并返回包含全部错误的错误信息
+func (s *ProvisionService) Reconcile(trigger string) (*backupResponse.ImportResp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cfg := config.GlobalConfig.Provision
+	conflict := backup.ConflictSkip
+	switch cfg.Mode {
+	case "", provisionModeSeed:
+	case provisionModeSync:
+		conflict = backup.ConflictOverwrite
+	default:
+		return nil, fmt.Errorf("声明式配置的同步模式无效: %s，支持 seed / sync", cfg.Mode)
+	}
+
+	data, err := os.ReadFile(cfg.File)
+	if err != nil {
+		return nil, fmt.Errorf("读取声明文件失败: %w", err)
+	}
+	data, err = expandProvisionEnv(data)
+	if err != nil {
+		return nil, err
+	}
+
+	format := backup.FormatJSON
+	if ext := strings.ToLower(filepath.Ext(cfg.File)); ext == ".yaml" || ext == ".yml" {
+		format = backup.FormatYAML
+	}
+	doc, err := Backup.Decode(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := Backup.Import(doc, &backupRequest.ImportReq{Format: format, Conflict: conflict})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Valid {
+		return resp, fmt.Errorf("声明文件 %s 校验失败:\n%s", cfg.File, provisionProblems(resp))
+	}
+
+	disabled := 0
+	if cfg.Mode == provisionModeSync {
+		disabled = s.disableUndeclaredTasks(doc)
+	}
+
+	counts := make(map[string]int)
+	for _, items := range [][]backupResponse.ImportItem{resp.Tasks, resp.Configs} {
+		for _, item := range items {
+			counts[item.Action]++
+			if item.Action == backup.ActionFailed || item.Message == redactedSecretMessage {
+				utils.Warn("声明式配置条目同步异常", "name", item.Name, "action", item.Action, "message", item.Message)
+			}
+		}
+	}
+	utils.Info("声明式配置同步完成",
+		"trigger", trigger,
+		"file", cfg.File,
+		"mode", cfg.Mode,
+		"created", counts[backup.ActionCreate],
+		"updated", counts[backup.ActionOverwrite],
+		"skipped", counts[backup.ActionSkip],
+		"disabled", disabled,
+		"failed", counts[backup.ActionFailed])
+	return resp, nil
+}
+
+// disableUndeclaredTasks 禁用声明文件中未出现的任务并移除其调度和排队中的执行，返回禁用的任务数。
+// 任务不会被删除，以保留任务日志和文件历史，正在执行的任务完成本次执行后不再调度
+func (s *ProvisionService) disableUndeclaredTasks(doc *backup.Document) int {
+	declared := make(map[string]bool, len(doc.Tasks))
+	for _, spec := range doc.Tasks {
+		declared[spec.Name] = true
+	}
+
+	enabled := true
+	tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{Enabled: &enabled})
+	if err != nil {
+		utils.Error("获取任务列表失败，无法禁用未声明的任务", "error", err.Error())
+		return 0
+	}
+
+	disabled := 0
+	for i := range tasks {
+		t := &tasks[i]
+		if declared[t.Name] {
+			continue
+		}
+
+		t.Enabled = false
+		if err := repository.Task.Update(t); err != nil {
+			utils.Error("禁用未声明的任务失败", "task_id", t.ID, "name", t.Name, "error", err.Error())
+			continue
+		}
+		if err := GetTaskScheduler().UpdateTask(t); err != nil {
+			utils.Warn("更新任务调度失败", "task_id", t.ID, "error", err.Error())
+		}
+		GetTaskQueue().RemoveTaskFromQueue(t.ID)
+		disabled++
+		utils.Info("任务未在声明文件中，已禁用", "task_id", t.ID, "name", t.Name)
+	}
+	return disabled
+}
+
+// expandProvisionEnv 替换声明文件中引用的环境变量，引用了未设置的变量时返回错误
+func expandProvisionEnv(data []byte) ([]byte, error) {
+	var missing []string
+	expanded := provisionEnvPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		name := string(provisionEnvPattern.FindSubmatch(match)[1])
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return []byte(value)
+	})
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, errors.New("声明文件引用的环境变量未设置: " + strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// provisionProblems 汇总未通过校验的条目，每行一条
+func provisionProblems(resp *backupResponse.ImportResp) string {
+	var lines []string
+	for _, item := range resp.Configs {
+		if item.Action == backup.ActionInvalid {
+			lines = append(lines, fmt.Sprintf("  配置 %s: %s", item.Name, item.Message))
+		}
+	}
+	for i, item := range resp.Tasks {
+		if item.Action == backup.ActionInvalid {
+			lines = append(lines, fmt.Sprintf("  第 %d 个任务 %s: %s", i+1, item.Name, item.Message))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WatchReload 收到 SIGHUP 信号时重新同步声明文件，同步失败时保留当前数据
+func (s *ProvisionService) WatchReload() {
+	s.watchOnce.Do(func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGHUP)
+		go func() {
+			for range signals {
+				utils.Info("收到 SIGHUP 信号，重新同步声明式配置", "file", config.GlobalConfig.Provision.File)
+				if _, err := s.Reconcile("reload"); err != nil {
+					utils.Error("声明式配置同步失败，已保留当前配置", "error", err.Error())
+				}
+			}
+		}()
+	})
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/config"
+	"github.com/MccRay-s/alist2strm/model/tasklog"
+	"github.com/MccRay-s/alist2strm/repository"
+	"github.com/MccRay-s/alist2strm/utils"
+)
+
+const (
+	// runLogFlushInterval 运行日志批量写入数据库的间隔
+	runLogFlushInterval = time.Second
+	// runLogPurgeInterval 清理过期运行日志的间隔
+	runLogPurgeInterval = 24 * time.Hour
+)
+
+// runLogWriter 运行日志写入器，日志条目先缓存在内存中，由后台协程定期批量写入数据库，避免拖慢文件处理
+type runLogWriter struct {
+	mu        sync.Mutex
+	pending   []tasklog.LogEntry
+	counts    map[uint]int // 每次运行已记录的条目数
+	flushMu   sync.Mutex
+	startOnce sync.Once
+}
+
+// runLogs 包级别的运行日志写入器
+var runLogs = &runLogWriter{counts: make(map[uint]int)}
+
+// runLogConfig 获取运行日志配置
+func runLogConfig() config.RunLogConfig {
+	cfg := config.RunLogConfig{Level: tasklog.LogLevelInfo, MaxEntries: 10000, RetentionDays: 30}
+	if config.GlobalConfig != nil {
+		cfg = config.GlobalConfig.RunLog
+	}
+	if tasklog.LogLevelRank(cfg.Level) < 0 {
+		cfg.Level = tasklog.LogLevelInfo
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 10000
+	}
+	if cfg.RetentionDays < 1 {
+		cfg.RetentionDays = 30
+	}
+	return cfg
+}
+
+// add 记录一条运行日志，taskLogID 为 0 或级别低于配置时忽略
+func (w *runLogWriter) add(taskID, taskLogID uint, level, event, path, message string) {
+	if taskLogID == 0 {
+		return
+	}
+	cfg := runLogConfig()
+	if tasklog.LogLevelRank(level) < tasklog.LogLevelRank(cfg.Level) {
+		return
+	}
+	w.startOnce.Do(w.start)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	count := w.counts[taskLogID]
+	if count > cfg.MaxEntries {
+		return
+	}
+	if count == cfg.MaxEntries {
+		// 达到上限后仅追加一条提示，后续条目丢弃
+		level, event, path = tasklog.LogLevelWarn, tasklog.LogEventRun, ""
+		message = fmt.Sprintf("运行日志超过 %d 条，后续条目已省略", cfg.MaxEntries)
+	}
+	w.counts[taskLogID] = count + 1
+	w.pending = append(w.pending, tasklog.LogEntry{
+		CreatedAt: time.Now(),
+		TaskID:    taskID,
+		TaskLogID: taskLogID,
+		Level:     level,
+		Event:     event,
+		Path:      path,
+		Message:   message,
+	})
+}
+
+// finish 运行结束时立即写入缓存的日志，并释放该次运行的计数
+func (w *runLogWriter) finish(taskLogID uint) {
+	w.flush()
+	w.mu.Lock()
+	delete(w.counts, taskLogID)
+	w.mu.Unlock()
+}
+
+// flush 将缓存的日志写入数据库
+func (w *runLogWriter) flush() {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	entries := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	if err := repository.TaskLogEntry.CreateBatch(entries); err != nil {
+		utils.Error("写入运行日志失败", "count", len(entries), "error", err.Error())
+	}
+}
+
+// start 启动后台写入和过期清理
+func (w *runLogWriter) start() {
+	go func() {
+		ticker := time.NewTicker(runLogFlushInterval)
+		defer ticker.Stop()
+
+		var lastPurge time.Time
+		for range ticker.C {
+			w.flush()
+			if time.Since(lastPurge) >= runLogPurgeInterval {
+				lastPurge = time.Now()
+				w.purge()
+			}
+		}
+	}()
+}
+
+// purge 清理超过保留天数的运行日志
+func (w *runLogWriter) purge() {
+	before := time.Now().AddDate(0, 0, -runLogConfig().RetentionDays)
+	if purged, err := repository.TaskLogEntry.DeleteBefore(before); err != nil {
+		utils.Error("清理过期运行日志失败", "error", err.Error())
+	} else if purged > 0 {
+		utils.Info("已清理过期运行日志", "count", purged)
+	}
+}
+
+// logFileResult 记录单个文件的处理结果
+func (s *StrmGeneratorService) logFileResult(taskID, taskLogID uint, fileType FileType, sourcePath string, result *ProcessedFile) {
+	event := tasklog.LogEventDownload
+	if fileType == FileTypeMedia {
+		event = tasklog.LogEventStrm
+	}
+
+	switch {
+	case result.Success && fileType == FileTypeMedia:
+		runLogs.add(taskID, taskLogID, tasklog.LogLevelDebug, event, sourcePath, "已生成 STRM 文件: "+result.TargetPath)
+	case result.Success:
+		runLogs.add(taskID, taskLogID, tasklog.LogLevelDebug, event, sourcePath, "已下载到: "+result.TargetPath)
+	case result.ErrorMessage == strmFileExistsMessage:
+		runLogs.add(taskID, taskLogID, tasklog.LogLevelDebug, tasklog.LogEventSkip, sourcePath, "STRM 文件已存在且不允许覆盖，已跳过")
+	default:
+		runLogs.add(taskID, taskLogID, tasklog.LogLevelWarn, event, sourcePath, result.ErrorMessage)
+	}
+}
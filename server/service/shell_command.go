@@ -0,0 +1,76 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/config"
+)
+
+const (
+	// maxCommandOutput 保存到日志中的命令输出最大长度，超出时保留末尾部分
+	maxCommandOutput = 4000
+	// defaultCommandTimeout 未配置时的命令超时时间
+	defaultCommandTimeout = 300 * time.Second
+)
+
+// errCommandDisabled 未开启 Shell 命令执行
+var errCommandDisabled = errors.New("未开启 Shell 命令执行，请设置环境变量 SHELL_COMMAND_ENABLED=true")
+
+// commandsEnabled 是否允许执行 Shell 命令
+func commandsEnabled() bool {
+	return config.GlobalConfig != nil && config.GlobalConfig.Command.Enabled
+}
+
+// commandTimeout 获取命令超时时间，seconds 为 0 时使用全局默认值
+func commandTimeout(seconds int) time.Duration {
+	if seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if config.GlobalConfig != nil && config.GlobalConfig.Command.Timeout > 0 {
+		return time.Duration(config.GlobalConfig.Command.Timeout) * time.Second
+	}
+	return defaultCommandTimeout
+}
+
+// runShellCommand 通过系统 Shell 执行命令，env 追加到当前进程的环境变量之后，返回合并后的标准输出和错误输出
+func runShellCommand(command string, env []string, timeout time.Duration) (string, error) {
+	if !commandsEnabled() {
+		return "", errCommandDisabled
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := newShellCommand(ctx, command)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// 命令超时被终止后，最多再等待子进程关闭输出管道的时间
+	cmd.WaitDelay = 5 * time.Second
+
+	err := cmd.Run()
+	result := truncateCommandOutput(output.String())
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return result, fmt.Errorf("命令执行超时（%s）", timeout)
+	}
+	if err != nil {
+		return result, fmt.Errorf("命令执行失败: %w", err)
+	}
+	return result, nil
+}
+
+// truncateCommandOutput 去除首尾空白并截断过长的输出，保留末尾部分
+func truncateCommandOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) <= maxCommandOutput {
+		return output
+	}
+	return "..." + strings.ToValidUTF8(output[len(output)-maxCommandOutput:], "")
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package service
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+)
+
+// newShellCommand 创建通过 /bin/sh 执行的命令，超时时终止整个进程组，避免残留子进程
+func newShellCommand(ctx context.Context, command string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return cmd
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"os/exec"
+)
+
+// newShellCommand 创建通过 cmd /C 执行的命令
+func newShellCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/C", command)
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/task"
+	"github.com/MccRay-s/alist2strm/model/tasklog"
+	"github.com/MccRay-s/alist2strm/repository"
+	"go.uber.org/zap"
+)
+
+// retryCounters 重试失败文件时的统计
+type retryCounters struct {
+	total              int
+	generated          int
+	metadataDownloaded int
+	subtitleDownloaded int
+	failed             int
+}
+
+// recordFileFailure 记录处理失败的文件，webhook 事件（taskLogID 为 0）不记录
+func (s *StrmGeneratorService) recordFileFailure(taskID, taskLogID uint, phase, sourcePath, targetPath string, file *AListFile, fileType FileType, errorMessage string) {
+	if taskLogID == 0 {
+		return
+	}
+
+	failure := &tasklog.FileFailure{
+		TaskID:       taskID,
+		TaskLogID:    taskLogID,
+		Phase:        phase,
+		SourcePath:   sourcePath,
+		TargetPath:   targetPath,
+		FileType:     s.getFileTypeString(fileType),
+		ErrorMessage: errorMessage,
+	}
+	if file != nil {
+		failure.FileName = file.Name
+		failure.FileSize = file.Size
+	}
+
+	if err := repository.FileFailure.Create(failure); err != nil {
+		s.logger.Error("记录失败文件失败",
+			zap.String("sourcePath", sourcePath),
+			zap.String("phase", phase),
+			zap.Error(err))
+	}
+}
+
+// resolveTargetPath 根据任务配置将源路径映射为目标路径
+func (s *StrmGeneratorService) resolveTargetPath(taskInfo *task.Task, sourcePath string) string {
+	relativePath := strings.TrimPrefix(normalizeSourcePath(sourcePath), normalizeSourcePath(taskInfo.SourcePath))
+	relativePath = strings.TrimPrefix(relativePath, "/")
+	return filepath.Join(taskInfo.TargetPath, relativePath)
+}
+
+// RetryFileFailures 仅重新处理指定的失败记录，结果记录到新的任务日志中
+func (s *StrmGeneratorService) RetryFileFailures(taskInfo *task.Task, taskLogID uint, failures []tasklog.FileFailure) error {
+	defer runLogs.finish(taskLogID)
+	runLogs.add(taskInfo.ID, taskLogID, tasklog.LogLevelInfo, tasklog.LogEventRun, "", fmt.Sprintf("开始重试 %d 个失败记录", len(failures)))
+
+	strmConfig, err := s.loadStrmConfig()
+	if err != nil {
+		s.updateTaskLogWithError(taskLogID, "加载 STRM 配置失败: "+err.Error())
+		return err
+	}
+
+	s.logger.Info("开始重试失败文件",
+		zap.String("task", taskInfo.Name),
+		zap.Int("failureCount", len(failures)),
+		zap.Uint("taskLogID", taskLogID))
+
+	counters := &retryCounters{}
+	listings := make(map[string][]AListFile)
+
+	for _, failure := range failures {
+		var resolved bool
+		if failure.Phase == tasklog.FailurePhaseList {
+			resolved = s.retryDirectory(taskInfo, strmConfig, taskLogID, failure.SourcePath, counters)
+		} else {
+			resolved = s.retryFile(taskInfo, strmConfig, taskLogID, failure, listings, counters)
+		}
+
+		if err := repository.FileFailure.MarkRetried(failure.ID, taskLogID, resolved); err != nil {
+			s.logger.Warn("更新失败记录状态失败", zap.Uint("failureID", failure.ID), zap.Error(err))
+		}
+	}
+
+	s.finishRetryTaskLog(taskInfo, taskLogID, counters)
+	return nil
+}
+
+// retryFile 重试单个文件，源文件已不存在时视为已解决
+func (s *StrmGeneratorService) retryFile(taskInfo *task.Task, strmConfig *StrmConfig, taskLogID uint, failure tasklog.FileFailure, listings map[string][]AListFile, counters *retryCounters) bool {
+	parentDir := path.Dir(normalizeSourcePath(failure.SourcePath))
+	files, ok := listings[parentDir]
+	if !ok {
+		var err error
+		files, err = s.listFiles(taskInfo, parentDir)
+		if err != nil {
+			counters.total++
+			counters.failed++
+			s.recordFileFailure(taskInfo.ID, taskLogID, tasklog.FailurePhaseList, parentDir, s.resolveTargetPath(taskInfo, parentDir), nil, FileTypeOther, err.Error())
+			return false
+		}
+		listings[parentDir] = files
+	}
+
+	var file *AListFile
+	fileName := path.Base(normalizeSourcePath(failure.SourcePath))
+	for i := range files {
+		if files[i].Name == fileName && !files[i].IsDir {
+			file = &files[i]
+			break
+		}
+	}
+	if file == nil {
+		s.logger.Info("失败文件在源中已不存在，跳过重试", zap.String("sourcePath", failure.SourcePath))
+		return true
+	}
+
+	counters.total++
+	return s.retryEntry(taskInfo, strmConfig, taskLogID, file, failure.SourcePath, counters)
+}
+
+// retryDirectory 重新扫描列目录失败的目录（包含子目录）
+func (s *StrmGeneratorService) retryDirectory(taskInfo *task.Task, strmConfig *StrmConfig, taskLogID uint, dirPath string, counters *retryCounters) bool {
+	files, err := s.listFiles(taskInfo, dirPath)
+	if err != nil {
+		counters.total++
+		counters.failed++
+		s.recordFileFailure(taskInfo.ID, taskLogID, tasklog.FailurePhaseList, dirPath, s.resolveTargetPath(taskInfo, dirPath), nil, FileTypeOther, err.Error())
+		return false
+	}
+
+	resolved := true
+	for i := range files {
+		sourcePath := filepath.Join(dirPath, files[i].Name)
+		if files[i].IsDir {
+			if !s.retryDirectory(taskInfo, strmConfig, taskLogID, sourcePath, counters) {
+				resolved = false
+			}
+			continue
+		}
+
+		counters.total++
+		if !s.retryEntry(taskInfo, strmConfig, taskLogID, &files[i], sourcePath, counters) {
+			resolved = false
+		}
+	}
+	return resolved
+}
+
+// retryEntry 处理单个源文件，成功时记录文件历史，失败时记录到新的任务日志
+func (s *StrmGeneratorService) retryEntry(taskInfo *task.Task, strmConfig *StrmConfig, taskLogID uint, file *AListFile, sourcePath string, counters *retryCounters) bool {
+	fileType := s.determineFileType(file, taskInfo, strmConfig)
+	switch fileType {
+	case FileTypeMedia:
+		if !s.isMediaFileSizeValid(file, strmConfig) {
+			return true
+		}
+	case FileTypeMetadata, FileTypeSubtitle:
+	default:
+		return true
+	}
+
+	processed := s.processFile(file, fileType, taskInfo, strmConfig, taskLogID, sourcePath, s.resolveTargetPath(taskInfo, sourcePath))
+	if !processed.Success {
+		if processed.ErrorMessage == strmFileExistsMessage {
+			return true
+		}
+		counters.failed++
+		phase := tasklog.FailurePhaseDownload
+		if fileType == FileTypeMedia {
+			phase = tasklog.FailurePhaseStrm
+		}
+		s.recordFileFailure(taskInfo.ID, taskLogID, phase, sourcePath, processed.TargetPath, file, fileType, processed.ErrorMessage)
+		return false
+	}
+
+	s.recordFileHistory(taskInfo.ID, taskLogID, file, sourcePath, processed.TargetPath, fileType, true)
+	switch fileType {
+	case FileTypeMedia:
+		counters.generated++
+	case FileTypeMetadata:
+		counters.metadataDownloaded++
+	case FileTypeSubtitle:
+		counters.subtitleDownloaded++
+	}
+	return true
+}
+
+// finishRetryTaskLog 更新重试任务日志并发送通知
+func (s *StrmGeneratorService) finishRetryTaskLog(taskInfo *task.Task, taskLogID uint, counters *retryCounters) {
+	endTime := time.Now()
+	status := tasklog.TaskLogStatusCompleted
+	message := fmt.Sprintf("重试失败文件完成，成功 %d 个，失败 %d 个", counters.total-counters.failed, counters.failed)
+	if counters.failed > 0 && counters.failed >= counters.total {
+		status = tasklog.TaskLogStatusFailed
+	}
+
+	var durationSeconds int64
+	if taskLogRecord, err := repository.TaskLog.GetByID(taskLogID); err == nil && taskLogRecord != nil {
+		durationSeconds = int64(endTime.Sub(taskLogRecord.StartTime).Seconds())
+	}
+
+	updateData := map[string]interface{}{
+		"status":              status,
+		"message":             message,
+		"end_time":            &endTime,
+		"duration":            durationSeconds,
+		"total_file":          counters.total,
+		"generated_file":      counters.generated,
+		"metadata_count":      counters.metadataDownloaded,
+		"subtitle_count":      counters.subtitleDownloaded,
+		"metadata_downloaded": counters.metadataDownloaded,
+		"subtitle_downloaded": counters.subtitleDownloaded,
+		"failed_count":        counters.failed,
+	}
+	if err := repository.TaskLog.UpdatePartial(taskLogID, updateData); err != nil {
+		s.logger.Error("更新重试任务日志失败", zap.Error(err))
+	}
+	finishLevel := tasklog.LogLevelInfo
+	if status == tasklog.TaskLogStatusFailed {
+		finishLevel = tasklog.LogLevelError
+	}
+	runLogs.add(taskInfo.ID, taskLogID, finishLevel, tasklog.LogEventRun, "", message)
+
+	notifyData := map[string]interface{}{
+		"total_file":          counters.total,
+		"generated_file":      counters.generated,
+		"metadata_count":      counters.metadataDownloaded,
+		"subtitle_count":      counters.subtitleDownloaded,
+		"metadata_downloaded": counters.metadataDownloaded,
+		"subtitle_downloaded": counters.subtitleDownloaded,
+		"failed_count":        counters.failed,
+	}
+	s.notifyRunFinished(taskInfo, taskLogID, status, durationSeconds, notifyData)
+	s.refreshEmbyAfterRun(taskInfo, status, counters.generated > 0 || counters.metadataDownloaded > 0 || counters.subtitleDownloaded > 0)
+
+	s.logger.Info("重试失败文件结束",
+		zap.String("task", taskInfo.Name),
+		zap.Uint("taskLogID", taskLogID),
+		zap.Int("total", counters.total),
+		zap.Int("failed", counters.failed))
+}
@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
@@ -31,6 +33,9 @@ type StrmConfig struct {
 	MinFileSize   int64  `json:"minFileSize"`   // 最小文件大小(MB)，用于过滤小文件，0表示不过滤
 }
 
+// strmFileExistsMessage STRM 文件已存在且不允许覆盖时的提示，属于跳过而非失败
+const strmFileExistsMessage = "文件已存在且不允许覆盖"
+
 // FileType 文件类型枚举
 type FileType int
 
@@ -78,7 +83,9 @@ type ProcessingStats struct {
 	SubtitleDownloaded     int          // 已下载的字幕文件数
 	SubtitleSkipped        int          // 已跳过的字幕文件数
 	OtherSkipped           int          // 跳过的其他类型文件数
+	DownloadProcessed      int          // 下载队列中已处理的文件数
 	FailedCount            int          // 处理失败的文件数 (与 TaskLog 字段保持一致)
+	NewStrmFiles           []string     // 本次新生成的 STRM 文件路径，用于新增媒体通知（最多保留 maxNewMediaPaths 条）
 	ScanFinished           bool         // 目录扫描是否已完成
 	StrmProcessingDone     bool         // STRM 文件处理是否已完成
 	DownloadProcessingDone bool         // 下载文件处理是否已完成
@@ -94,6 +101,8 @@ type StrmGeneratorService struct {
 	queue             *FileProcessQueue // 文件处理队列
 	stats             *ProcessingStats  // 处理统计
 	urlEncodeCache    *URLEncodeCache   // URL编码缓存
+	renameMu          sync.Mutex        // 重命名检测互斥锁，避免并发工作协程重复认领同一个旧文件
+	run               *strmRun          // 当前正在执行的任务，用于查询进度和取消
 }
 
 var (
@@ -201,9 +210,6 @@ func (s *StrmGeneratorService) ProcessFileChangeEvent(taskInfo *task.Task, event
 	// 4. 确定文件类型并进行相应处理。
 	fileType := s.determineFileType(aListFile, taskInfo, strmConfig)
 
-	var success bool
-	var errorMessage string
-
 	s.logger.Info("Determined file type for webhook event",
 		zap.String("file", aListFile.Name),
 		zap.String("type", getFileTypeString(fileType)),
@@ -215,23 +221,24 @@ func (s *StrmGeneratorService) ProcessFileChangeEvent(taskInfo *task.Task, event
 			s.logger.Debug("Skipping media file from webhook due to size constraints", zap.String("file", aListFile.Name))
 			return nil
 		}
-		// 将原始的、非标准化的路径传递给 generateStrmFile，因为它会处理自己的标准化。
-		success, errorMessage, _ = s.generateStrmFile(aListFile, strmConfig, taskInfo, event.SourceFile, targetPath)
 	case FileTypeMetadata, FileTypeSubtitle:
-		// 将原始的、非标准化的路径传递给 downloadFile。
-		success, errorMessage = s.downloadFile(aListFile, event.SourceFile, targetPath, taskInfo)
 	default:
 		s.logger.Info("Skipping file with unhandled type from webhook", zap.String("file", aListFile.Name))
 		return nil // 不是错误，只是跳过。
 	}
 
-	if !success {
-		return fmt.Errorf("failed to process file from webhook '%s': %s", event.SourceFile, errorMessage)
+	// 将原始的、非标准化的路径传递给 processFile，它会返回实际写入的目标路径。
+	// 使用 0 作为 taskLogID，因为这是 webhook 事件，不是批处理任务
+	processed := s.processFile(aListFile, fileType, taskInfo, strmConfig, 0, event.SourceFile, targetPath)
+	if !processed.Success {
+		return fmt.Errorf("failed to process file from webhook '%s': %s", event.SourceFile, processed.ErrorMessage)
 	}
 
 	// 记录成功处理文件的文件历史
-	// 使用 0 作为 taskLogID，因为这是 webhook 事件，不是批处理任务
-	s.recordFileHistory(taskInfo.ID, 0, aListFile, event.SourceFile, targetPath, fileType, true)
+	s.recordFileHistory(taskInfo.ID, 0, aListFile, event.SourceFile, processed.TargetPath, fileType, true)
+	if fileType == FileTypeMedia {
+		GetNotificationService().CollectNewMedia(taskInfo, processed.TargetPath)
+	}
 
 	s.logger.Info("Successfully processed file from webhook", zap.String("file", event.SourceFile))
 	return nil
@@ -313,9 +320,6 @@ func (s *StrmGeneratorService) processDirectoryEvent(taskInfo *task.Task, dirPat
 		// 确定文件类型
 		fileType := s.determineFileType(&file, taskInfo, strmConfig)
 
-		var success bool
-		var errorMessage string
-
 		switch fileType {
 		case FileTypeMedia:
 			if !s.isMediaFileSizeValid(&file, strmConfig) {
@@ -323,9 +327,7 @@ func (s *StrmGeneratorService) processDirectoryEvent(taskInfo *task.Task, dirPat
 					zap.String("file", file.Name))
 				continue
 			}
-			success, errorMessage, _ = s.generateStrmFile(&file, strmConfig, taskInfo, sourceFilePath, targetFilePath)
 		case FileTypeMetadata, FileTypeSubtitle:
-			success, errorMessage = s.downloadFile(&file, sourceFilePath, targetFilePath, taskInfo)
 		default:
 			s.logger.Debug("Skipping file with unhandled type",
 				zap.String("file", file.Name),
@@ -333,19 +335,23 @@ func (s *StrmGeneratorService) processDirectoryEvent(taskInfo *task.Task, dirPat
 			continue
 		}
 
-		if success {
+		// 使用 0 作为 taskLogID，因为这是 webhook 事件，不是批处理任务
+		processed := s.processFile(&file, fileType, taskInfo, strmConfig, 0, sourceFilePath, targetFilePath)
+		if processed.Success {
 			processedCount++
 			s.logger.Debug("Successfully processed file from directory",
 				zap.String("file", sourceFilePath))
 
 			// 记录成功处理文件的文件历史
-			// 使用 0 作为 taskLogID，因为这是 webhook 事件，不是批处理任务
-			s.recordFileHistory(taskInfo.ID, 0, &file, sourceFilePath, targetFilePath, fileType, true)
+			s.recordFileHistory(taskInfo.ID, 0, &file, sourceFilePath, processed.TargetPath, fileType, true)
+			if fileType == FileTypeMedia {
+				GetNotificationService().CollectNewMedia(taskInfo, processed.TargetPath)
+			}
 		} else {
 			errorCount++
 			s.logger.Error("Failed to process file from directory",
 				zap.String("file", sourceFilePath),
-				zap.String("error", errorMessage))
+				zap.String("error", processed.ErrorMessage))
 		}
 	}
 
@@ -368,7 +374,7 @@ func (s *StrmGeneratorService) ProcessFileDeleteEvent(taskInfo *task.Task, sourc
 		zap.String("sourceFile", sourceFilePath),
 	)
 
-	// 1. 计算已删除文件的预期目标路径。
+	// 1. 标准化路径。
 	sourceFileNormalized := strings.ReplaceAll(sourceFilePath, "\\", "/")
 	taskSourcePathNormalized := strings.ReplaceAll(taskInfo.SourcePath, "\\", "/")
 
@@ -380,6 +386,54 @@ func (s *StrmGeneratorService) ProcessFileDeleteEvent(taskInfo *task.Task, sourc
 		return nil
 	}
 
+	// 2. 优先使用记录的目标映射，目标文件名可能已被截断，重新计算的路径无法找到它们。
+	mappings, err := s.findTargetMappings(taskInfo.ID, sourceFilePath)
+	if err != nil {
+		s.logger.Warn("Failed to query target mappings, falling back to computed paths", zap.Error(err))
+	}
+
+	var filesToDelete []string
+	if len(mappings) > 0 {
+		filesToDelete = s.collectMappedTargetFiles(mappings)
+	} else {
+		filesToDelete = s.computeDeleteCandidates(taskInfo, sourceFilePath, sourceFileNormalized, taskSourcePathNormalized)
+	}
+
+	if len(filesToDelete) == 0 {
+		s.logger.Info("No corresponding target files found to delete.", zap.String("sourceFile", sourceFilePath))
+		return nil
+	}
+
+	// 3. 将文件移入回收站，而不是直接删除，以便误删后可以恢复。
+	var lastErr error
+	for _, fileToDel := range filesToDelete {
+		if _, err := os.Stat(fileToDel); err == nil {
+			if err := Trash.MoveToTrash(taskInfo, sourceFilePath, fileToDel); err != nil {
+				s.logger.Error("Failed to move target file to trash",
+					zap.String("file", fileToDel),
+					zap.Error(err),
+				)
+				lastErr = err // 记录最后一个错误
+			} else {
+				s.logger.Info("Successfully moved target file to trash", zap.String("file", fileToDel))
+			}
+		}
+	}
+
+	// 4. 目标文件已移除，清理对应的映射记录。
+	if lastErr == nil {
+		for _, mapping := range mappings {
+			if err := repository.TargetMapping.Delete(mapping.ID); err != nil {
+				s.logger.Warn("Failed to delete target mapping", zap.Uint("mappingID", mapping.ID), zap.Error(err))
+			}
+		}
+	}
+
+	return lastErr
+}
+
+// computeDeleteCandidates 在没有目标映射记录时，根据源路径推算需要删除的目标文件。
+func (s *StrmGeneratorService) computeDeleteCandidates(taskInfo *task.Task, sourceFilePath, sourceFileNormalized, taskSourcePathNormalized string) []string {
 	relativePath := strings.TrimPrefix(sourceFileNormalized, taskSourcePathNormalized)
 	relativePath = strings.TrimPrefix(relativePath, "/")
 
@@ -387,7 +441,7 @@ func (s *StrmGeneratorService) ProcessFileDeleteEvent(taskInfo *task.Task, sourc
 	targetMediaFilePath := filepath.Join(taskInfo.TargetPath, relativePath)
 	targetDir := filepath.Dir(targetMediaFilePath)
 
-	// 2. 查找目标目录中所有需要删除的相关文件。
+	// 查找目标目录中所有需要删除的相关文件。
 	// 这包括 .strm 文件、.nfo 文件、字幕文件等。
 	baseName := strings.TrimSuffix(filepath.Base(targetMediaFilePath), filepath.Ext(targetMediaFilePath))
 	filesToDelete, err := filepath.Glob(filepath.Join(targetDir, baseName+".*"))
@@ -396,7 +450,7 @@ func (s *StrmGeneratorService) ProcessFileDeleteEvent(taskInfo *task.Task, sourc
 		// 不要返回，尝试手动构建 strm 路径。
 	}
 
-	// 3. 手动构建 .strm 文件路径以确保它在列表中。
+	// 手动构建 .strm 文件路径以确保它在列表中。
 	strmConfig, err := s.loadStrmConfig()
 	if err != nil {
 		s.logger.Warn("Could not load strm config for delete event, .strm file might be missed if glob failed", zap.Error(err))
@@ -424,28 +478,7 @@ func (s *StrmGeneratorService) ProcessFileDeleteEvent(taskInfo *task.Task, sourc
 		}
 	}
 
-	if len(filesToDelete) == 0 {
-		s.logger.Info("No corresponding target files found to delete.", zap.String("sourceFile", sourceFilePath))
-		return nil
-	}
-
-	// 4. 删除文件。
-	var lastErr error
-	for _, fileToDel := range filesToDelete {
-		if _, err := os.Stat(fileToDel); err == nil {
-			if err := os.Remove(fileToDel); err != nil {
-				s.logger.Error("Failed to delete target file",
-					zap.String("file", fileToDel),
-					zap.Error(err),
-				)
-				lastErr = err // 记录最后一个错误
-			} else {
-				s.logger.Info("Successfully deleted target file", zap.String("file", fileToDel))
-			}
-		}
-	}
-
-	return lastErr
+	return filesToDelete
 }
 
 // ProcessFileRenameEvent 处理来自 webhook 的文件重命名/移动事件。
@@ -485,29 +518,57 @@ func (s *StrmGeneratorService) IsInitialized() bool {
 
 // GenerateStrmFiles 生成 STRM 文件主方法
 func (s *StrmGeneratorService) GenerateStrmFiles(taskID uint) error {
+	_, err := s.GenerateStrmFilesInScope(taskID, "", task.TriggerManual)
+	return err
+}
+
+// GenerateStrmFilesInScope 仅扫描任务源路径下的指定子路径并生成 STRM 文件，子路径为空时扫描整个源路径，
+// trigger 为本次执行的触发来源，记录到任务日志中。返回本次执行创建的任务日志ID，未能创建任务日志时为 0
+func (s *StrmGeneratorService) GenerateStrmFilesInScope(taskID uint, subPath string, trigger string) (uint, error) {
 	// 检查服务是否已初始化
 	if !s.IsInitialized() {
-		return fmt.Errorf("STRM 生成服务未正确初始化")
+		return 0, fmt.Errorf("STRM 生成服务未正确初始化")
 	}
 
 	// 获取任务信息
 	taskInfo, err := repository.Task.GetByID(taskID)
 	if err != nil {
-		return fmt.Errorf("获取任务信息失败: %w", err)
+		return 0, fmt.Errorf("获取任务信息失败: %w", err)
+	}
+	if taskInfo == nil {
+		return 0, fmt.Errorf("任务不存在")
+	}
+
+	// 计算本次扫描的源路径与目标路径
+	scanSourcePath, scanTargetPath := taskInfo.SourcePath, taskInfo.TargetPath
+	scopePath := ""
+	startMessage := "开始生成 STRM 文件"
+	if subPath != "" {
+		scopePath, err = resolveScopePath(taskInfo, subPath)
+		if err != nil {
+			return 0, err
+		}
+		scanSourcePath = scopePath
+		scanTargetPath = s.resolveTargetPath(taskInfo, scopePath)
+		startMessage = "开始生成 STRM 文件（局部扫描: " + scopePath + "）"
 	}
 
 	// 重置处理队列和统计信息
+	s.mu.Lock()
 	s.queue = &FileProcessQueue{
 		StrmFiles:     make([]FileEntry, 0),
 		DownloadFiles: make([]FileEntry, 0),
 	}
 	s.stats = &ProcessingStats{}
+	s.mu.Unlock()
 
 	// 创建任务日志
 	taskLog := &tasklog.TaskLog{
 		TaskID:        taskID,
 		Status:        tasklog.TaskLogStatusRunning,
-		Message:       "开始生成 STRM 文件",
+		Message:       startMessage,
+		ScopePath:     scopePath,
+		Trigger:       trigger,
 		StartTime:     time.Now(),
 		TotalFile:     0,
 		GeneratedFile: 0,
@@ -518,21 +579,34 @@ func (s *StrmGeneratorService) GenerateStrmFiles(taskID uint) error {
 
 	taskLogID, err := s.createTaskLog(taskLog)
 	if err != nil {
-		return fmt.Errorf("创建任务日志失败: %w", err)
+		return 0, fmt.Errorf("创建任务日志失败: %w", err)
+	}
+	s.beginRun(taskID, taskLogID, scopePath)
+	defer s.endRun()
+	defer runLogs.finish(taskLogID)
+	runLogs.add(taskID, taskLogID, tasklog.LogLevelInfo, tasklog.LogEventRun, scanSourcePath,
+		fmt.Sprintf("%s，触发来源: %s，目标路径: %s", startMessage, trigger, scanTargetPath))
+
+	// 执行任务开始前的钩子，失败策略为 failTask 时不再扫描
+	hookEnv := taskHookEnv(taskInfo, taskLogID, trigger, scanSourcePath, scanTargetPath, scopePath)
+	if hookErr := s.runTaskHook(task.HookBeforeTask, taskInfo, hookEnv); hookErr != nil {
+		s.updateTaskLogWithError(taskLogID, hookErr.Error())
+		return taskLogID, hookErr
 	}
 
 	// 加载 STRM 配置
 	strmConfig, err := s.loadStrmConfig()
 	if err != nil {
+		s.runAfterTaskHook(taskInfo, hookEnv, tasklog.TaskLogStatusFailed, 0, 0)
 		s.updateTaskLogWithError(taskLogID, "加载 STRM 配置失败: "+err.Error())
-		return err
+		return taskLogID, err
 	}
 
 	// 开始处理文件
 	s.logger.Info("开始处理任务",
 		zap.Uint("taskId", taskID),
-		zap.String("sourcePath", taskInfo.SourcePath),
-		zap.String("targetPath", taskInfo.TargetPath))
+		zap.String("sourcePath", scanSourcePath),
+		zap.String("targetPath", scanTargetPath))
 
 	// 先启动STRM文件处理协程（并发），让它等待队列中的项目
 	var strmProcessingErr error
@@ -550,16 +624,21 @@ func (s *StrmGeneratorService) GenerateStrmFiles(taskID uint) error {
 
 	// 现在开始递归扫描，边扫描边将媒体文件加入队列（立即处理）
 	startTime := time.Now()
-	err = s.scanDirectoryRecursive(taskInfo, strmConfig, taskLogID, taskInfo.SourcePath, taskInfo.TargetPath)
-	if err != nil {
+	err = s.scanDirectoryRecursive(taskInfo, strmConfig, taskLogID, scanSourcePath, scanTargetPath)
+	// 扫描被取消时继续走完整的收尾流程，保留已处理文件的统计
+	if err != nil && !errors.Is(err, ErrTaskCancelled) {
 		// 通知STRM协程扫描已结束（失败）
 		close(strmScanDoneChan)
 
 		// 等待STRM协程结束
 		wg.Wait()
 
+		s.stats.Mutex.RLock()
+		generated, failed := s.stats.GeneratedFile, s.stats.FailedCount
+		s.stats.Mutex.RUnlock()
+		s.runAfterTaskHook(taskInfo, hookEnv, tasklog.TaskLogStatusFailed, generated, failed)
 		s.updateTaskLogWithError(taskLogID, "扫描目录失败: "+err.Error())
-		return err
+		return taskLogID, err
 	}
 	scanDuration := time.Since(startTime)
 
@@ -628,7 +707,15 @@ func (s *StrmGeneratorService) GenerateStrmFiles(taskID uint) error {
 	message := "STRM 文件生成完成"
 
 	// 如果任一处理出错，标记任务失败
-	if strmProcessingErr != nil {
+	if reason, aborted := s.abortedReason(); aborted {
+		status = tasklog.TaskLogStatusFailed
+		message = "任务已终止: " + reason
+		err = errors.New(reason)
+	} else if s.isCancelRequested() {
+		status = tasklog.TaskLogStatusCancelled
+		message = "任务已取消"
+		err = ErrTaskCancelled
+	} else if strmProcessingErr != nil {
 		status = tasklog.TaskLogStatusFailed
 		message = "STRM 文件生成失败: " + strmProcessingErr.Error()
 		err = strmProcessingErr
@@ -662,8 +749,27 @@ func (s *StrmGeneratorService) GenerateStrmFiles(taskID uint) error {
 	subtitleSkipped := s.stats.SubtitleSkipped
 	otherSkipped := s.stats.OtherSkipped
 	failedCount := s.stats.FailedCount
+	newStrmFiles := append([]string(nil), s.stats.NewStrmFiles...)
 	s.stats.Mutex.RUnlock()
 
+	// 执行任务结束后的钩子，失败策略为 failTask 时将成功的任务标记为失败
+	if hookErr := s.runAfterTaskHook(taskInfo, hookEnv, status, generatedFiles, failedCount); hookErr != nil && status == tasklog.TaskLogStatusCompleted {
+		status = tasklog.TaskLogStatusFailed
+		message = hookErr.Error()
+		err = hookErr
+	}
+
+	finishLevel := tasklog.LogLevelInfo
+	switch status {
+	case tasklog.TaskLogStatusFailed:
+		finishLevel = tasklog.LogLevelError
+	case tasklog.TaskLogStatusCancelled:
+		finishLevel = tasklog.LogLevelWarn
+	}
+	runLogs.add(taskID, taskLogID, finishLevel, tasklog.LogEventRun, "",
+		fmt.Sprintf("%s，共 %d 个文件，生成 %d，跳过 %d，失败 %d，用时 %d 秒",
+			message, totalFiles, generatedFiles, skippedFiles, failedCount, durationSeconds))
+
 	// 只包含 TaskLog 模型中存在的字段
 	updateData := map[string]interface{}{
 		"status":              status,
@@ -678,6 +784,7 @@ func (s *StrmGeneratorService) GenerateStrmFiles(taskID uint) error {
 		"metadata_downloaded": metadataDownloaded,
 		"subtitle_downloaded": subtitleDownloaded,
 		"failed_count":        failedCount,
+		"hook_output":         s.hookOutput(taskLogID),
 	}
 
 	// 额外的统计信息保留在通知中，但不更新到数据库
@@ -697,20 +804,38 @@ func (s *StrmGeneratorService) GenerateStrmFiles(taskID uint) error {
 		"subtitle_skipped":    subtitleSkipped,
 		"other_skipped":       otherSkipped,
 		"failed_count":        failedCount,
+		"new_media":           newStrmFiles,
+	}
+	if scopePath != "" {
+		// 局部扫描时通知中展示实际扫描的路径
+		notifyData["scope_path"] = scopePath
+		notifyData["source_path"] = scanSourcePath
+		notifyData["target_path"] = scanTargetPath
 	}
 
 	if updateErr := repository.TaskLog.UpdatePartial(taskLogID, updateData); updateErr != nil {
 		s.logger.Error("更新任务日志失败", zap.Error(updateErr))
 	}
 
-	// 发送通知 - 使用包含额外详细统计信息的notifyData
-	notifyErr := s.sendNotification(taskInfo, taskLogID, status, durationSeconds, notifyData)
-	if notifyErr != nil {
+	// 刷新 Emby 媒体库在后续动作执行之后进行，见 ExecuteScopedStrmGeneration
+	s.notifyRunFinished(taskInfo, taskLogID, status, durationSeconds, notifyData)
+
+	return taskLogID, err
+}
+
+// notifyRunFinished 发送运行结束通知，手动取消的运行不发送
+func (s *StrmGeneratorService) notifyRunFinished(taskInfo *task.Task, taskLogID uint, status string, durationSeconds int64, notifyData map[string]interface{}) {
+	if status == tasklog.TaskLogStatusCancelled {
+		return
+	}
+	if notifyErr := s.sendNotification(taskInfo, taskLogID, status, durationSeconds, notifyData); notifyErr != nil {
 		s.logger.Error("发送任务通知失败", zap.Error(notifyErr))
 	}
+}
 
-	// 如果任务成功完成，则刷新 Emby 媒体库
-	if status == tasklog.TaskLogStatusCompleted && (generatedFiles > 0 || metadataDownloaded > 0 || subtitleDownloaded > 0) {
+// refreshEmbyAfterRun 运行成功且有新生成或下载的文件时刷新 Emby 媒体库
+func (s *StrmGeneratorService) refreshEmbyAfterRun(taskInfo *task.Task, status string, changed bool) {
+	if status == tasklog.TaskLogStatusCompleted && changed {
 		s.logger.Info("开始刷新 Emby 媒体库", zap.String("taskName", taskInfo.Name))
 		if refreshErr := Emby.RefreshAllLibraries(); refreshErr != nil {
 			s.logger.Error("刷新 Emby 媒体库失败", zap.Error(refreshErr))
@@ -719,8 +844,6 @@ func (s *StrmGeneratorService) GenerateStrmFiles(taskID uint) error {
 			s.logger.Info("刷新 Emby 媒体库请求成功", zap.String("taskName", taskInfo.Name))
 		}
 	}
-
-	return err
 }
 
 // loadStrmConfig 加载 STRM 配置
@@ -781,6 +904,9 @@ func (s *StrmGeneratorService) listFiles(taskInfo *task.Task, path string) ([]AL
 func (s *StrmGeneratorService) listLocalFiles(path string) ([]AListFile, error) {
 	dirEntries, err := os.ReadDir(path)
 	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read local directory %s: %w: %w", path, ErrSourcePathNotFound, err)
+		}
 		return nil, fmt.Errorf("failed to read local directory %s: %w", path, err)
 	}
 
@@ -851,6 +977,11 @@ func (scanner *StreamingScanner) scanWithMemoryControl(sourcePath, targetPath st
 
 // scanDirectoryRecursiveInternal 内部递归扫描实现
 func (scanner *StreamingScanner) scanDirectoryRecursiveInternal(sourcePath, targetPath string) error {
+	if scanner.service.isCancelRequested() {
+		return ErrTaskCancelled
+	}
+	scanner.service.setCurrentDir(sourcePath)
+
 	// 检查是否已处理过此目录
 	scanner.mutex.RLock()
 	if scanner.processedDirs[sourcePath] {
@@ -867,6 +998,8 @@ func (scanner *StreamingScanner) scanDirectoryRecursiveInternal(sourcePath, targ
 	// 根据任务类型获取当前目录的文件列表
 	files, err := scanner.service.listFiles(scanner.taskInfo, sourcePath)
 	if err != nil {
+		scanner.service.recordFileFailure(scanner.taskInfo.ID, scanner.taskLogID, tasklog.FailurePhaseList, sourcePath, targetPath, nil, FileTypeOther, err.Error())
+		runLogs.add(scanner.taskInfo.ID, scanner.taskLogID, tasklog.LogLevelError, tasklog.LogEventScan, sourcePath, "获取目录文件列表失败: "+err.Error())
 		return fmt.Errorf("获取目录文件列表失败 [%s]: %w", sourcePath, err)
 	}
 
@@ -874,6 +1007,8 @@ func (scanner *StreamingScanner) scanDirectoryRecursiveInternal(sourcePath, targ
 		zap.String("sourcePath", sourcePath),
 		zap.String("targetPath", targetPath),
 		zap.Int("fileCount", len(files)))
+	runLogs.add(scanner.taskInfo.ID, scanner.taskLogID, tasklog.LogLevelInfo, tasklog.LogEventScan, sourcePath,
+		fmt.Sprintf("扫描目录，共 %d 个条目", len(files)))
 
 	// 创建目标目录
 	if err := scanner.service.safeMkdirAll(targetPath, 0755); err != nil {
@@ -911,6 +1046,11 @@ func (scanner *StreamingScanner) processFilesStreaming(files []AListFile, source
 		// 确定文件类型
 		fileType := scanner.service.determineFileType(&file, scanner.taskInfo, scanner.strmConfig)
 
+		// 字幕和元数据按截断后的实际路径检查是否已存在
+		if fileType == FileTypeSubtitle || fileType == FileTypeMetadata {
+			currentTargetPath = scanner.service.truncatePathLength(currentTargetPath)
+		}
+
 		// 获取不含扩展名的文件名
 		nameWithoutExt := strings.TrimSuffix(file.Name, filepath.Ext(file.Name))
 
@@ -938,6 +1078,8 @@ func (scanner *StreamingScanner) processFilesStreaming(files []AListFile, source
 					zap.String("path", sourcePath),
 					zap.Int64("fileSize", file.Size),
 					zap.Int64("minSize", scanner.strmConfig.MinFileSize*1024*1024))
+				runLogs.add(scanner.taskInfo.ID, scanner.taskLogID, tasklog.LogLevelInfo, tasklog.LogEventSkip, currentSourcePath,
+					fmt.Sprintf("媒体文件大小 %s 小于最小文件大小 %d MB，已跳过", humanizeSize(file.Size), scanner.strmConfig.MinFileSize))
 			}
 		case FileTypeSubtitle:
 			subtitleFileEntries = append(subtitleFileEntries, entry)
@@ -948,6 +1090,7 @@ func (scanner *StreamingScanner) processFilesStreaming(files []AListFile, source
 			scanner.service.stats.Mutex.Lock()
 			scanner.service.stats.OtherSkipped++
 			scanner.service.stats.Mutex.Unlock()
+			runLogs.add(scanner.taskInfo.ID, scanner.taskLogID, tasklog.LogLevelDebug, tasklog.LogEventSkip, currentSourcePath, "不是媒体、元数据或字幕文件，已跳过")
 		}
 
 		// 内存控制：当媒体文件队列过大时，先处理一批
@@ -1029,9 +1172,11 @@ func (scanner *StreamingScanner) processSubtitleAndMetadata(subtitleFileEntries,
 			scanner.service.logger.Debug("字幕文件已存在，跳过下载",
 				zap.String("fileName", entry.File.Name),
 				zap.String("targetPath", entry.TargetPath))
+			runLogs.add(scanner.taskInfo.ID, scanner.taskLogID, tasklog.LogLevelDebug, tasklog.LogEventSkip, entry.SourcePath, "字幕文件已存在，跳过下载")
 
-			// 记录文件历史（已存在的文件）
+			// 记录文件历史和目标映射（已存在的文件）
 			scanner.service.recordFileHistory(scanner.taskInfo.ID, scanner.taskLogID, entry.File, entry.SourcePath, entry.TargetPath, entry.FileType, true)
+			scanner.service.recordTargetMapping(scanner.taskInfo.ID, entry.SourcePath, entry.TargetPath, entry.FileType)
 
 			// 更新统计信息
 			scanner.service.stats.Mutex.Lock()
@@ -1048,9 +1193,11 @@ func (scanner *StreamingScanner) processSubtitleAndMetadata(subtitleFileEntries,
 			scanner.service.logger.Debug("元数据文件已存在，跳过下载",
 				zap.String("fileName", entry.File.Name),
 				zap.String("targetPath", entry.TargetPath))
+			runLogs.add(scanner.taskInfo.ID, scanner.taskLogID, tasklog.LogLevelDebug, tasklog.LogEventSkip, entry.SourcePath, "元数据文件已存在，跳过下载")
 
-			// 记录文件历史（已存在的文件）
+			// 记录文件历史和目标映射（已存在的文件）
 			scanner.service.recordFileHistory(scanner.taskInfo.ID, scanner.taskLogID, entry.File, entry.SourcePath, entry.TargetPath, entry.FileType, true)
+			scanner.service.recordTargetMapping(scanner.taskInfo.ID, entry.SourcePath, entry.TargetPath, entry.FileType)
 
 			// 更新统计信息
 			scanner.service.stats.Mutex.Lock()
@@ -1429,20 +1576,34 @@ func (s *StrmGeneratorService) processFile(file *AListFile, fileType FileType, t
 
 	switch fileType {
 	case FileTypeMedia:
+		// 若该文件是由任务内已有文件移动或重命名而来，先将原有 STRM 及附属文件迁移到新位置，
+		// 随后强制覆盖写入，以更新 STRM 中的链接
+		strmTaskInfo := taskInfo
+		if s.detectAndMoveRenamedTarget(file, taskInfo, strmConfig, sourcePath, s.buildStrmFilePath(file, strmConfig, targetPath)) {
+			overwriteTask := *taskInfo
+			overwriteTask.Overwrite = true
+			strmTaskInfo = &overwriteTask
+		}
+
 		// 生成 STRM 文件 - 仅使用 AListFile 中已有信息
 		var strmFilePath string
-		result.Success, result.ErrorMessage, strmFilePath = s.generateStrmFile(file, strmConfig, taskInfo, sourcePath, targetPath)
-		if result.Success {
-			// 如果成功生成STRM文件，更新目标路径为实际的STRM文件路径
+		result.Success, result.ErrorMessage, strmFilePath = s.generateStrmFile(file, strmConfig, strmTaskInfo, sourcePath, targetPath)
+		if strmFilePath != "" {
+			// 更新目标路径为实际的STRM文件路径（可能已被截断）
 			result.TargetPath = strmFilePath
 		}
 	case FileTypeMetadata, FileTypeSubtitle:
 		// 下载元数据或字幕文件 - 仅使用 AListFile 中已有信息
-		result.Success, result.ErrorMessage = s.downloadFile(file, sourcePath, targetPath, taskInfo)
+		result.Success, result.ErrorMessage, result.TargetPath = s.downloadFile(file, fileType, sourcePath, targetPath, taskInfo)
 	default:
 		result.ErrorMessage = "不支持的文件类型，已跳过"
 	}
 
+	// 记录源文件与实际目标文件的映射（包括因已存在而跳过的文件）
+	if result.Success || s.targetFileExists(result.TargetPath) {
+		s.recordTargetMapping(taskInfo.ID, sourcePath, result.TargetPath, fileType)
+	}
+
 	// 记录处理结果
 	if !result.Success {
 		s.logger.Warn("处理文件失败",
@@ -1454,6 +1615,7 @@ func (s *StrmGeneratorService) processFile(file *AListFile, fileType FileType, t
 			zap.String("文件名", file.Name),
 			zap.String("文件类型", getFileTypeString(fileType)))
 	}
+	s.logFileResult(taskInfo.ID, taskLogID, fileType, sourcePath, result)
 
 	return result
 }
@@ -1521,26 +1683,16 @@ func (s *StrmGeneratorService) generateStrmFile(file *AListFile, strmConfig *Str
 		return false, fmt.Sprintf("无法为类型 %s 生成文件URL，请检查相关配置是否完整", taskConfig.ConfigType), ""
 	}
 
-	// 生成 STRM 文件名
-	var strmFileName string
-	if strmConfig.ReplaceSuffix {
-		// 替换后缀为 .strm
-		nameWithoutExt := strings.TrimSuffix(file.Name, filepath.Ext(file.Name))
-		strmFileName = nameWithoutExt + ".strm"
-	} else {
-		// 在原文件名后添加 .strm
-		strmFileName = file.Name + ".strm"
-	}
-
 	// 构建完整的 STRM 文件路径
-	strmFilePath := filepath.Join(filepath.Dir(targetPath), strmFileName)
-
-	// 检查并处理路径长度，包括目录名和文件名
-	strmFilePath = s.truncatePathLength(strmFilePath)
+	strmFilePath := s.buildStrmFilePath(file, strmConfig, targetPath)
 
 	// 检查是否需要覆盖现有文件
 	if !s.shouldOverwrite(strmFilePath, taskConfig) {
-		return false, "文件已存在且不允许覆盖", strmFilePath
+		return false, strmFileExistsMessage, strmFilePath
+	}
+
+	if hookErr := s.runFileHook(task.HookBeforeStrm, taskConfig, FileTypeMedia, file, sourcePath, strmFilePath, fileURL); hookErr != "" {
+		return false, hookErr, strmFilePath
 	}
 
 	// 确保目标目录存在
@@ -1558,11 +1710,32 @@ func (s *StrmGeneratorService) generateStrmFile(file *AListFile, strmConfig *Str
 		zap.String("strmFile", strmFilePath),
 		zap.String("url", fileURL))
 
+	if hookErr := s.runFileHook(task.HookAfterStrm, taskConfig, FileTypeMedia, file, sourcePath, strmFilePath, fileURL); hookErr != "" {
+		return false, hookErr, strmFilePath
+	}
+
 	return true, "", strmFilePath
 }
 
-// downloadFile 下载文件（元数据和字幕）
-func (s *StrmGeneratorService) downloadFile(file *AListFile, sourcePath, targetPath string, taskConfig *task.Task) (bool, string) {
+// buildStrmFilePath 根据媒体文件计算实际写入的 STRM 文件路径（包含长度截断）
+func (s *StrmGeneratorService) buildStrmFilePath(file *AListFile, strmConfig *StrmConfig, targetPath string) string {
+	// 生成 STRM 文件名
+	var strmFileName string
+	if strmConfig.ReplaceSuffix {
+		// 替换后缀为 .strm
+		nameWithoutExt := strings.TrimSuffix(file.Name, filepath.Ext(file.Name))
+		strmFileName = nameWithoutExt + ".strm"
+	} else {
+		// 在原文件名后添加 .strm
+		strmFileName = file.Name + ".strm"
+	}
+
+	// 检查并处理路径长度，包括目录名和文件名
+	return s.truncatePathLength(filepath.Join(filepath.Dir(targetPath), strmFileName))
+}
+
+// downloadFile 下载文件（元数据和字幕），返回成功状态、错误消息和实际写入的文件路径
+func (s *StrmGeneratorService) downloadFile(file *AListFile, fileType FileType, sourcePath, targetPath string, taskConfig *task.Task) (bool, string, string) {
 
 	// 检查并处理路径长度，包括目录名和文件名
 	originalPath := targetPath
@@ -1580,31 +1753,42 @@ func (s *StrmGeneratorService) downloadFile(file *AListFile, sourcePath, targetP
 
 	// 确保目标目录存在
 	if err := s.safeMkdirAll(targetDir, 0755); err != nil {
-		return false, fmt.Sprintf("创建目标目录失败: %v", err)
+		return false, fmt.Sprintf("创建目标目录失败: %v", err), targetPath
 	}
 
 	// 对于本地文件，执行文件复制
 	if taskConfig.ConfigType == "local" {
+		if hookErr := s.runFileHook(task.HookBeforeDownload, taskConfig, fileType, file, sourcePath, targetPath, ""); hookErr != "" {
+			return false, hookErr, targetPath
+		}
+
 		in, err := os.Open(sourcePath)
 		if err != nil {
-			return false, fmt.Sprintf("打开源文件失败: %v", err)
+			return false, fmt.Sprintf("打开源文件失败: %v", err), targetPath
 		}
 		defer in.Close()
 
 		out, err := os.Create(targetPath)
 		if err != nil {
-			return false, fmt.Sprintf("创建目标文件失败: %v", err)
+			return false, fmt.Sprintf("创建目标文件失败: %v", err), targetPath
 		}
 		defer out.Close()
 
 		_, err = io.Copy(out, in)
 		if err != nil {
-			return false, fmt.Sprintf("复制文件失败: %v", err)
+			return false, fmt.Sprintf("复制文件失败: %v", err), targetPath
+		}
+		if err := out.Close(); err != nil {
+			return false, fmt.Sprintf("写入目标文件失败: %v", err), targetPath
 		}
 		s.logger.Info("复制本地文件成功",
 			zap.String("sourceFile", sourcePath),
 			zap.String("targetPath", targetPath))
-		return true, ""
+
+		if hookErr := s.runFileHook(task.HookAfterDownload, taskConfig, fileType, file, sourcePath, targetPath, ""); hookErr != "" {
+			return false, hookErr, targetPath
+		}
+		return true, "", targetPath
 	}
 
 	// --- 对于远程文件 (alist, clouddrive)，执行下载 ---
@@ -1612,7 +1796,7 @@ func (s *StrmGeneratorService) downloadFile(file *AListFile, sourcePath, targetP
 	// 获取 STRM 配置以检查是否需要 URL 编码
 	strmConfig, err := s.loadStrmConfig()
 	if err != nil {
-		return false, fmt.Sprintf("加载 STRM 配置失败: %v", err)
+		return false, fmt.Sprintf("加载 STRM 配置失败: %v", err), targetPath
 	}
 
 	// 处理路径和文件名
@@ -1629,16 +1813,20 @@ func (s *StrmGeneratorService) downloadFile(file *AListFile, sourcePath, targetP
 	case "clouddrive":
 		fileURL = s.cloudDriveService.GetFileURL(dirPath, fileName, file.Sign)
 	default:
-		return false, fmt.Sprintf("不支持的 ConfigType 用于下载: %s", taskConfig.ConfigType)
+		return false, fmt.Sprintf("不支持的 ConfigType 用于下载: %s", taskConfig.ConfigType), targetPath
 	}
 
 	if fileURL == "" {
-		return false, fmt.Sprintf("无法为类型 %s 生成文件下载URL，请检查相关配置", taskConfig.ConfigType)
+		return false, fmt.Sprintf("无法为类型 %s 生成文件下载URL，请检查相关配置", taskConfig.ConfigType), targetPath
+	}
+
+	if hookErr := s.runFileHook(task.HookBeforeDownload, taskConfig, fileType, file, sourcePath, targetPath, fileURL); hookErr != "" {
+		return false, hookErr, targetPath
 	}
 
 	// 实现 HTTP 下载逻辑
 	if err := s.downloadFileFromURL(fileURL, targetPath); err != nil {
-		return false, fmt.Sprintf("下载文件失败: %v", err)
+		return false, fmt.Sprintf("下载文件失败: %v", err), targetPath
 	}
 
 	s.logger.Info("下载文件成功",
@@ -1646,7 +1834,11 @@ func (s *StrmGeneratorService) downloadFile(file *AListFile, sourcePath, targetP
 		zap.String("targetPath", targetPath),
 		zap.String("size", humanizeSize(file.Size)))
 
-	return true, ""
+	if hookErr := s.runFileHook(task.HookAfterDownload, taskConfig, fileType, file, sourcePath, targetPath, fileURL); hookErr != "" {
+		return false, hookErr, targetPath
+	}
+
+	return true, "", targetPath
 }
 
 // humanizeSize 将字节大小转换为友好的字符串表示
@@ -1736,12 +1928,7 @@ func (s *StrmGeneratorService) recordFileHistory(taskID, taskLogID uint, file *A
 	fileTypeStr := s.getFileTypeString(fileType)
 
 	// 获取文件Hash
-	hash := ""
-	if file.HashInfo.Sha1 != "" {
-		// hash = file.HashInfo.Sha1
-		// 测试为 null 的结果
-		hash = ""
-	}
+	hash := file.HashInfo.Sha1
 
 	// 记录文件类型和是否有Hash值
 	s.logger.Debug("处理文件历史记录",
@@ -1750,51 +1937,37 @@ func (s *StrmGeneratorService) recordFileHistory(taskID, taskLogID uint, file *A
 		zap.Bool("hasHash", hash != ""),
 		zap.String("hash", hash))
 
-	// 查找现有记录 - 支持两种查找方式
-	var existingRecord *filehistory.FileHistory
-	var findErr error
-
-	// 1. 优先通过 Hash 查找（如果有 hash）
-	if hash != "" {
-		existingRecord, findErr = repository.FileHistory.GetByHash(hash)
-		if findErr != nil {
-			s.logger.Debug("通过Hash查找文件历史记录失败",
-				zap.String("hash", hash),
-				zap.Error(findErr))
-		}
-	}
-
-	// 2. 如果通过 Hash 没找到记录或者没有 hash，则通过文件路径和属性查找
-	if existingRecord == nil {
-		existingRecord, findErr = repository.FileHistory.GetByFileAttributes(sourcePath, file.Name, file.Size, fileTypeStr)
-		if findErr != nil {
-			s.logger.Debug("通过文件属性查找文件历史记录失败",
-				zap.String("sourcePath", sourcePath),
-				zap.String("fileName", file.Name),
-				zap.Int64("fileSize", file.Size),
-				zap.String("fileType", fileTypeStr),
-				zap.Error(findErr))
-		} else if existingRecord != nil {
-			existingHashStr := ""
-			if existingRecord.Hash != nil {
-				existingHashStr = *existingRecord.Hash
-			}
-			s.logger.Debug("通过文件属性找到现有记录",
-				zap.String("fileName", file.Name),
-				zap.String("existingHash", existingHashStr),
-				zap.String("newHash", hash))
+	// 通过文件路径和属性查找现有记录
+	// 注意：不按 Hash 查找，内容相同的副本会互相覆盖记录；文件移动由扫描时的重命名检测负责迁移记录
+	existingRecord, findErr := repository.FileHistory.GetByFileAttributes(sourcePath, file.Name, file.Size, fileTypeStr)
+	if findErr != nil {
+		s.logger.Debug("通过文件属性查找文件历史记录失败",
+			zap.String("sourcePath", sourcePath),
+			zap.String("fileName", file.Name),
+			zap.Int64("fileSize", file.Size),
+			zap.String("fileType", fileTypeStr),
+			zap.Error(findErr))
+	} else if existingRecord != nil {
+		existingHashStr := ""
+		if existingRecord.Hash != nil {
+			existingHashStr = *existingRecord.Hash
 		}
+		s.logger.Debug("通过文件属性找到现有记录",
+			zap.String("fileName", file.Name),
+			zap.String("existingHash", existingHashStr),
+			zap.String("newHash", hash))
 	}
 
 	// 如果找到现有记录，更新它
 	if existingRecord != nil {
 		now := time.Now()
 		updateData := map[string]interface{}{
-			"task_id":     taskID,
-			"task_log_id": taskLogID,
-			"updated_at":  now,
-			"file_size":   file.Size,
-			"modified_at": &file.Modified,
+			"task_id":          taskID,
+			"task_log_id":      taskLogID,
+			"updated_at":       now,
+			"file_size":        file.Size,
+			"modified_at":      &file.Modified,
+			"target_file_path": targetPath,
 		}
 
 		// 处理 hash 字段更新
@@ -1910,6 +2083,11 @@ func (s *StrmGeneratorService) updateTaskLogWithError(taskLogID uint, errorMessa
 		"end_time": &endTime,
 		"duration": durationSeconds,
 	}
+	if output := s.hookOutput(taskLogID); output != "" {
+		updateData["hook_output"] = output
+	}
+
+	runLogs.add(taskLog.TaskID, taskLogID, tasklog.LogLevelError, tasklog.LogEventRun, "", errorMessage)
 
 	if err := repository.TaskLog.UpdatePartial(taskLogID, updateData); err != nil {
 		s.logger.Error("更新任务日志失败", zap.Error(err))
@@ -1945,6 +2123,11 @@ func (s *StrmGeneratorService) processDownloadFileQueue(taskInfo *task.Task, str
 
 	// 串行处理每个下载项，带间隔延迟
 	for i, entry := range downloadFiles {
+		if s.isCancelRequested() {
+			s.logger.Info("任务已取消，停止处理下载队列", zap.Int("已处理", i), zap.Int("总数", totalDownloadFiles))
+			break
+		}
+
 		// 添加随机延迟(0.5-2秒)，防止网盘风控，优化延迟时间
 		if i > 0 {
 			// 使用更高效的随机数生成，减少延迟时间
@@ -1963,6 +2146,7 @@ func (s *StrmGeneratorService) processDownloadFileQueue(taskInfo *task.Task, str
 
 		// 更新统计信息
 		s.stats.Mutex.Lock()
+		s.stats.DownloadProcessed++
 		if processed.Success {
 			if entry.FileType == FileTypeSubtitle {
 				s.stats.SubtitleDownloaded++ // 成功下载的字幕文件
@@ -1970,6 +2154,7 @@ func (s *StrmGeneratorService) processDownloadFileQueue(taskInfo *task.Task, str
 				s.stats.MetadataDownloaded++ // 成功下载的元数据文件
 			}
 		} else {
+			s.recordFileFailure(taskInfo.ID, taskLogID, tasklog.FailurePhaseDownload, entry.SourcePath, processed.TargetPath, entry.File, entry.FileType, processed.ErrorMessage)
 			s.stats.FailedCount++ // 处理失败的文件
 			// 下载失败的文件也应计入相应的跳过类别
 			if entry.FileType == FileTypeSubtitle {
@@ -2047,8 +2232,10 @@ func (s *StrmGeneratorService) processStrmFileQueueAsync(taskInfo *task.Task, st
 	resultCollector := s.createResultCollector(workerPool.resultChan, taskInfo, taskLogID)
 	go resultCollector.start()
 
-	// 启动智能队列分发器
+	// 启动智能队列分发器（在启动协程前登记，避免 wait 先于 start 返回）
 	dispatcher := s.createSmartDispatcher(workerPool, scanDoneChan, concurrency)
+	s.setDispatcher(dispatcher)
+	dispatcher.wg.Add(1)
 	go dispatcher.start()
 
 	// 等待所有处理完成
@@ -2204,6 +2391,7 @@ type WorkerPool struct {
 	workers    []*Worker
 	resultChan chan FileProcessResult
 	wg         sync.WaitGroup
+	pending    sync.WaitGroup // 已分发但尚未处理完成的任务
 	ctx        context.Context
 	cancel     context.CancelFunc
 	closed     int32         // 使用原子操作防止重复关闭
@@ -2220,6 +2408,7 @@ type Worker struct {
 	strmConfig *StrmConfig
 	taskLogID  uint
 	resultChan chan FileProcessResult // 结果通道引用
+	pending    *sync.WaitGroup        // 工作池中未完成任务的计数
 }
 
 // createWorkerPool 创建高性能工作池
@@ -2249,6 +2438,7 @@ func (pool *WorkerPool) startWorkers(service *StrmGeneratorService, taskInfo *ta
 			strmConfig: strmConfig,
 			taskLogID:  taskLogID,
 			resultChan: pool.resultChan,
+			pending:    &pool.pending,
 		}
 		pool.workers[i] = worker
 	}
@@ -2293,6 +2483,8 @@ func (w *Worker) run(ctx context.Context, wg *sync.WaitGroup, allWorkers []*Work
 
 // processJob 处理单个任务
 func (w *Worker) processJob(entry FileEntry) {
+	defer w.pending.Done()
+
 	processed := w.service.processFile(
 		entry.File,
 		entry.FileType,
@@ -2313,6 +2505,7 @@ func (w *Worker) processJob(entry FileEntry) {
 
 // addLocalJob 添加任务到本地队列
 func (w *Worker) addLocalJob(job FileEntry) {
+	w.pending.Add(1)
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 	w.localQueue = append(w.localQueue, job)
@@ -2360,6 +2553,9 @@ func (pool *WorkerPool) Close() {
 		return // 已经关闭过了
 	}
 
+	// 分发器在停止分发后才关闭工作池，此时等待已分发的任务全部处理完毕再停止工作协程，避免丢弃本地队列中的尾部任务
+	pool.pending.Wait()
+
 	pool.cancel()
 	pool.wg.Wait()
 	close(pool.resultChan)
@@ -2372,6 +2568,7 @@ type ResultCollector struct {
 	taskInfo       *task.Task
 	taskLogID      uint
 	batchSize      int
+	flushInterval  time.Duration // 将结果汇总到统计信息的间隔，保证运行进度及时刷新
 	updateInterval time.Duration
 	lastUpdateTime time.Time
 	pendingResults []FileProcessResult
@@ -2387,6 +2584,7 @@ func (s *StrmGeneratorService) createResultCollector(resultChan chan FileProcess
 		taskInfo:       taskInfo,
 		taskLogID:      taskLogID,
 		batchSize:      100,             // 批量处理大小
+		flushInterval:  time.Second,     // 统计汇总间隔
 		updateInterval: 5 * time.Second, // 更新间隔
 		lastUpdateTime: time.Now(),
 		pendingResults: make([]FileProcessResult, 0, 100),
@@ -2396,7 +2594,7 @@ func (s *StrmGeneratorService) createResultCollector(resultChan chan FileProcess
 
 // start 启动结果收集
 func (rc *ResultCollector) start() {
-	ticker := time.NewTicker(rc.updateInterval)
+	ticker := time.NewTicker(rc.flushInterval)
 	defer ticker.Stop()
 	defer close(rc.done) // 结束时发送完成信号
 
@@ -2453,6 +2651,7 @@ func (rc *ResultCollector) processBatch() {
 
 	// 批量处理文件历史记录
 	var successResults []FileProcessResult
+	var newStrmFiles []string
 	var generatedCount, skippedCount int
 
 	for _, result := range rc.pendingResults {
@@ -2470,8 +2669,14 @@ func (rc *ResultCollector) processBatch() {
 		if result.Success {
 			generatedCount++
 			successResults = append(successResults, result)
+			newStrmFiles = append(newStrmFiles, result.Processed.TargetPath)
 		} else {
 			skippedCount++
+			// 已存在而跳过的不属于失败，其余记录失败明细以便重试
+			if result.Processed.ErrorMessage != strmFileExistsMessage {
+				rc.service.recordFileFailure(rc.taskInfo.ID, rc.taskLogID, tasklog.FailurePhaseStrm,
+					result.Entry.SourcePath, result.Processed.TargetPath, result.Entry.File, result.FileType, result.Processed.ErrorMessage)
+			}
 		}
 	}
 
@@ -2479,6 +2684,12 @@ func (rc *ResultCollector) processBatch() {
 	rc.service.stats.Mutex.Lock()
 	rc.service.stats.GeneratedFile += generatedCount
 	rc.service.stats.SkipFile += skippedCount
+	if remaining := maxNewMediaPaths - len(rc.service.stats.NewStrmFiles); remaining > 0 {
+		if len(newStrmFiles) > remaining {
+			newStrmFiles = newStrmFiles[:remaining]
+		}
+		rc.service.stats.NewStrmFiles = append(rc.service.stats.NewStrmFiles, newStrmFiles...)
+	}
 	totalGenerated := rc.service.stats.GeneratedFile
 	totalSkipped := rc.service.stats.SkipFile
 	rc.service.stats.Mutex.Unlock()
@@ -2628,7 +2839,6 @@ func (s *StrmGeneratorService) createSmartDispatcher(workerPool *WorkerPool, sca
 
 // start 启动智能分发
 func (sd *SmartDispatcher) start() {
-	sd.wg.Add(1)
 	defer sd.wg.Done()
 
 	scanDone := false
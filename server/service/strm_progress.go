@@ -0,0 +1,226 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrTaskCancelled 任务被手动取消
+var ErrTaskCancelled = errors.New("任务已取消")
+
+// strmRun 当前正在执行的 STRM 生成任务，用于查询进度和取消
+type strmRun struct {
+	taskID    uint
+	taskLogID uint
+	scopePath string
+	startTime time.Time
+	cancelled atomic.Bool
+
+	// currentDir 正在扫描的源目录，dispatcher 当前的 STRM 分发器，用于进度查询
+	currentDir atomic.Value
+	dispatcher atomic.Pointer[SmartDispatcher]
+
+	// aborted 因钩子执行失败而终止，abortReason 为终止原因
+	aborted     atomic.Bool
+	abortReason atomic.Value
+
+	hookMu        sync.Mutex
+	hookOutput    strings.Builder
+	hookTruncated bool
+}
+
+// TaskProgress 正在执行任务的处理进度快照
+type TaskProgress struct {
+	TaskID                 uint
+	TaskLogID              uint
+	ScopePath              string
+	StartTime              time.Time
+	CurrentDir             string
+	StrmQueueSize          int
+	DownloadQueueSize      int
+	TotalFiles             int
+	ProcessedFiles         int
+	GeneratedFile          int
+	SkipFile               int
+	MetadataDownloaded     int
+	SubtitleDownloaded     int
+	FailedCount            int
+	ScanFinished           bool
+	StrmProcessingDone     bool
+	DownloadProcessingDone bool
+	Cancelling             bool
+}
+
+// beginRun 记录开始执行的任务
+func (s *StrmGeneratorService) beginRun(taskID, taskLogID uint, scopePath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.run = &strmRun{
+		taskID:    taskID,
+		taskLogID: taskLogID,
+		scopePath: scopePath,
+		startTime: time.Now(),
+	}
+}
+
+// endRun 清除当前执行的任务
+func (s *StrmGeneratorService) endRun() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.run = nil
+}
+
+// currentRun 获取当前执行的任务
+func (s *StrmGeneratorService) currentRun() *strmRun {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.run
+}
+
+// setCurrentDir 记录当前执行的任务正在扫描的目录
+func (s *StrmGeneratorService) setCurrentDir(dir string) {
+	if run := s.currentRun(); run != nil {
+		run.currentDir.Store(dir)
+	}
+}
+
+// setDispatcher 记录当前执行的任务使用的 STRM 分发器
+func (s *StrmGeneratorService) setDispatcher(dispatcher *SmartDispatcher) {
+	if run := s.currentRun(); run != nil {
+		run.dispatcher.Store(dispatcher)
+	}
+}
+
+// isCancelRequested 检查当前执行的任务是否已被请求取消或因钩子执行失败而终止
+func (s *StrmGeneratorService) isCancelRequested() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.run != nil && (s.run.cancelled.Load() || s.run.aborted.Load())
+}
+
+// abortRun 因钩子执行失败终止正在执行的任务，任务会在处理完当前目录或文件后停止并标记为失败
+func (s *StrmGeneratorService) abortRun(taskID uint, reason string) {
+	s.mu.RLock()
+	run := s.run
+	queue := s.queue
+	s.mu.RUnlock()
+
+	if run == nil || run.taskID != taskID || run.aborted.Load() {
+		return
+	}
+	run.abortReason.Store(reason)
+	run.aborted.Store(true)
+
+	queue.FilesMutex.Lock()
+	queue.StrmFiles = queue.StrmFiles[:0]
+	queue.FilesMutex.Unlock()
+
+	s.logger.Warn("钩子执行失败，终止任务", zap.Uint("taskId", taskID), zap.String("reason", reason))
+}
+
+// abortedReason 获取当前执行任务的终止原因，未被终止时返回 false
+func (s *StrmGeneratorService) abortedReason() (string, bool) {
+	s.mu.RLock()
+	run := s.run
+	s.mu.RUnlock()
+	if run == nil || !run.aborted.Load() {
+		return "", false
+	}
+	reason, _ := run.abortReason.Load().(string)
+	return reason, true
+}
+
+// CancelRun 请求取消正在执行的任务，任务会在处理完当前目录或文件后停止
+// 任务未在执行时返回 false
+func (s *StrmGeneratorService) CancelRun(taskID uint) bool {
+	s.mu.RLock()
+	run := s.run
+	queue := s.queue
+	s.mu.RUnlock()
+
+	if run == nil || run.taskID != taskID {
+		return false
+	}
+	run.cancelled.Store(true)
+
+	// 清空尚未分发的 STRM 队列，已分发给工作协程的文件会正常处理完
+	queue.FilesMutex.Lock()
+	queue.StrmFiles = queue.StrmFiles[:0]
+	queue.FilesMutex.Unlock()
+
+	s.logger.Info("已请求取消任务", zap.Uint("taskId", taskID))
+	return true
+}
+
+// GetProgress 获取正在执行任务的处理进度，taskID 为 0 时返回当前任意正在执行的任务
+func (s *StrmGeneratorService) GetProgress(taskID uint) (*TaskProgress, bool) {
+	s.mu.RLock()
+	run := s.run
+	stats := s.stats
+	queue := s.queue
+	s.mu.RUnlock()
+
+	if run == nil || stats == nil || (taskID != 0 && run.taskID != taskID) {
+		return nil, false
+	}
+	return s.progressOf(run, stats, queue), true
+}
+
+// GetRunProgress 获取指定任务日志对应运行的处理进度，该运行未在执行时返回 false
+func (s *StrmGeneratorService) GetRunProgress(taskLogID uint) (*TaskProgress, bool) {
+	s.mu.RLock()
+	run := s.run
+	stats := s.stats
+	queue := s.queue
+	s.mu.RUnlock()
+
+	if run == nil || stats == nil || run.taskLogID != taskLogID {
+		return nil, false
+	}
+	return s.progressOf(run, stats, queue), true
+}
+
+// progressOf 生成运行进度快照
+func (s *StrmGeneratorService) progressOf(run *strmRun, stats *ProcessingStats, queue *FileProcessQueue) *TaskProgress {
+	currentDir, _ := run.currentDir.Load().(string)
+	strmQueueSize := 0
+	if dispatcher := run.dispatcher.Load(); dispatcher != nil {
+		strmQueueSize = dispatcher.getQueueSize()
+	}
+	queue.FilesMutex.RLock()
+	downloadFiles := len(queue.DownloadFiles)
+	queue.FilesMutex.RUnlock()
+
+	stats.Mutex.RLock()
+	defer stats.Mutex.RUnlock()
+	// 下载队列处理时不会移除条目，待下载数需扣除已处理的文件数
+	downloadQueueSize := downloadFiles - stats.DownloadProcessed
+	if downloadQueueSize < 0 {
+		downloadQueueSize = 0
+	}
+	return &TaskProgress{
+		TaskID:                 run.taskID,
+		TaskLogID:              run.taskLogID,
+		ScopePath:              run.scopePath,
+		StartTime:              run.startTime,
+		CurrentDir:             currentDir,
+		StrmQueueSize:          strmQueueSize,
+		DownloadQueueSize:      downloadQueueSize,
+		TotalFiles:             stats.TotalFiles,
+		ProcessedFiles:         stats.GeneratedFile + stats.SkipFile + stats.OtherSkipped + stats.MetadataDownloaded + stats.MetadataSkipped + stats.SubtitleDownloaded + stats.SubtitleSkipped,
+		GeneratedFile:          stats.GeneratedFile,
+		SkipFile:               stats.SkipFile + stats.OtherSkipped,
+		MetadataDownloaded:     stats.MetadataDownloaded,
+		SubtitleDownloaded:     stats.SubtitleDownloaded,
+		FailedCount:            stats.FailedCount,
+		ScanFinished:           stats.ScanFinished,
+		StrmProcessingDone:     stats.StrmProcessingDone,
+		DownloadProcessingDone: stats.DownloadProcessingDone,
+		Cancelling:             run.cancelled.Load(),
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/MccRay-s/alist2strm/model/filehistory"
+	"github.com/MccRay-s/alist2strm/model/task"
+	"github.com/MccRay-s/alist2strm/repository"
+	"go.uber.org/zap"
+)
+
+// detectAndMoveRenamedTarget 检测媒体文件是否由同一任务中的已有文件移动或重命名而来
+// 若是，则将原有 STRM 文件及其附属文件移动到新的目标位置，返回 true
+func (s *StrmGeneratorService) detectAndMoveRenamedTarget(file *AListFile, taskInfo *task.Task, strmConfig *StrmConfig, sourcePath, strmFilePath string) bool {
+	// 目标位置已有 STRM，说明不是新文件，无需检测
+	if s.targetFileExists(strmFilePath) {
+		return false
+	}
+
+	// 新源路径已有映射，说明不是新文件
+	if mapping, err := repository.TargetMapping.GetBySourcePath(taskInfo.ID, normalizeSourcePath(sourcePath)); err != nil || mapping != nil {
+		return false
+	}
+
+	candidates, err := repository.FileHistory.FindMoveCandidates(taskInfo.ID, file.HashInfo.Sha1, file.Name, file.Size,
+		s.getFileTypeString(FileTypeMedia), sourcePath)
+	if err != nil {
+		s.logger.Warn("查找重命名候选记录失败", zap.String("file", sourcePath), zap.Error(err))
+		return false
+	}
+
+	for _, candidate := range candidates {
+		mapping, err := repository.TargetMapping.GetBySourcePath(taskInfo.ID, normalizeSourcePath(candidate.SourcePath))
+		if err != nil || mapping == nil || !s.targetFileExists(mapping.TargetPath) {
+			continue
+		}
+
+		// 旧源文件仍然存在说明是复制而不是移动，保留原有 STRM。
+		// 该检查需要请求源站，在加锁前完成，避免阻塞其他文件的检测
+		if s.sourceFileExists(taskInfo, candidate.SourcePath) {
+			continue
+		}
+
+		if moved, ok := s.moveRenamedTargetLocked(taskInfo, mapping, candidate, file, sourcePath, strmFilePath); ok {
+			return moved
+		}
+	}
+
+	return false
+}
+
+// moveRenamedTargetLocked 加锁后确认映射和文件仍未被其他并发处理迁移，再执行迁移。
+// ok 为 false 表示候选已失效，可继续尝试下一个候选
+func (s *StrmGeneratorService) moveRenamedTargetLocked(taskInfo *task.Task, mapping *filehistory.TargetMapping, candidate *filehistory.FileHistory, file *AListFile, sourcePath, strmFilePath string) (moved bool, ok bool) {
+	s.renameMu.Lock()
+	defer s.renameMu.Unlock()
+
+	if s.targetFileExists(strmFilePath) {
+		return false, true
+	}
+	current, err := repository.TargetMapping.GetBySourcePath(taskInfo.ID, normalizeSourcePath(candidate.SourcePath))
+	if err != nil || current == nil || current.ID != mapping.ID || !s.targetFileExists(current.TargetPath) {
+		return false, false
+	}
+
+	if err := s.moveRenamedTarget(taskInfo, current, candidate, file, sourcePath, strmFilePath); err != nil {
+		s.logger.Warn("迁移重命名文件的 STRM 失败，将重新生成",
+			zap.String("from", current.TargetPath),
+			zap.String("to", strmFilePath),
+			zap.Error(err))
+		return false, true
+	}
+	return true, true
+}
+
+// moveRenamedTarget 将旧的 STRM 文件及同名附属文件移动到新位置，并迁移相关记录
+func (s *StrmGeneratorService) moveRenamedTarget(taskInfo *task.Task, mapping *filehistory.TargetMapping, history *filehistory.FileHistory, file *AListFile, sourcePath, strmFilePath string) error {
+	oldStrmPath := mapping.TargetPath
+	oldBase := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(oldStrmPath), ".strm"), path.Ext(mapping.SourcePath))
+	newBase := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(strmFilePath), ".strm"), filepath.Ext(file.Name))
+	newDir := filepath.Dir(strmFilePath)
+
+	// 先找出附属文件，再移动 STRM，避免 STRM 本身被匹配进去
+	sidecars, err := filepath.Glob(filepath.Join(filepath.Dir(oldStrmPath), globEscape(oldBase)+".*"))
+	if err != nil {
+		return err
+	}
+
+	if err := s.safeMkdirAll(newDir, 0755); err != nil {
+		return err
+	}
+	if err := moveFile(oldStrmPath, strmFilePath); err != nil {
+		return err
+	}
+
+	for _, sidecar := range sidecars {
+		if sidecar == oldStrmPath {
+			continue
+		}
+		newName := newBase + strings.TrimPrefix(filepath.Base(sidecar), oldBase)
+		newPath := s.truncatePathLength(filepath.Join(newDir, newName))
+		if s.targetFileExists(newPath) {
+			continue
+		}
+		if err := moveFile(sidecar, newPath); err != nil {
+			s.logger.Warn("移动附属文件失败", zap.String("from", sidecar), zap.String("to", newPath), zap.Error(err))
+			continue
+		}
+		// 附属文件原有映射已失效，新源文件被扫描到时会重新记录
+		if err := repository.TargetMapping.DeleteByTargetPath(taskInfo.ID, sidecar); err != nil {
+			s.logger.Warn("清理附属文件映射失败", zap.String("file", sidecar), zap.Error(err))
+		}
+	}
+
+	// 旧映射作废，新映射在 STRM 写入成功后记录
+	if err := repository.TargetMapping.Delete(mapping.ID); err != nil {
+		s.logger.Warn("删除旧目标映射失败", zap.Uint("mappingID", mapping.ID), zap.Error(err))
+	}
+
+	// 让文件历史跟随文件移动，保留原有记录（新位置已有的同一文件记录会被合并）
+	if err := repository.FileHistory.MoveByID(history, sourcePath, file.Name, strmFilePath); err != nil {
+		s.logger.Warn("更新文件历史路径失败", zap.Uint("historyID", history.ID), zap.Error(err))
+	}
+
+	s.logger.Info("检测到文件移动，已迁移原有 STRM 及附属文件",
+		zap.String("task", taskInfo.Name),
+		zap.String("fromSource", history.SourcePath),
+		zap.String("toSource", sourcePath),
+		zap.String("fromTarget", oldStrmPath),
+		zap.String("toTarget", strmFilePath),
+		zap.Int("sidecarCount", len(sidecars)-1))
+	return nil
+}
+
+// sourceFileExists 检查源文件是否仍然存在，查询失败时按存在处理以避免误移动
+func (s *StrmGeneratorService) sourceFileExists(taskInfo *task.Task, sourcePath string) bool {
+	normalized := normalizeSourcePath(sourcePath)
+	files, err := s.listFiles(taskInfo, path.Dir(normalized))
+	if err != nil {
+		return !errors.Is(err, ErrSourcePathNotFound)
+	}
+	name := path.Base(normalized)
+	for _, f := range files {
+		if f.Name == name && !f.IsDir {
+			return true
+		}
+	}
+	return false
+}
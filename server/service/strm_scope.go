@@ -0,0 +1,34 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/MccRay-s/alist2strm/model/task"
+)
+
+// resolveScopePath 将局部扫描路径解析为任务源路径下的完整路径，支持绝对路径和相对任务源路径的路径
+func resolveScopePath(taskInfo *task.Task, subPath string) (string, error) {
+	sourceRoot := normalizeSourcePath(taskInfo.SourcePath)
+	scopePath := normalizeSourcePath(subPath)
+
+	if !strings.HasPrefix(scopePath, "/") {
+		// 仅真正的相对路径才拼接到源路径下，绝对路径必须位于源路径之内
+		scopePath = path.Join(sourceRoot, scopePath)
+	}
+
+	if !isPathWithin(scopePath, sourceRoot) {
+		return "", fmt.Errorf("路径 '%s' 不在任务源路径 '%s' 下", subPath, taskInfo.SourcePath)
+	}
+
+	return scopePath, nil
+}
+
+// isPathWithin 判断 p 是否为 root 本身或位于 root 之下
+func isPathWithin(p, root string) bool {
+	if root == "/" {
+		return strings.HasPrefix(p, "/")
+	}
+	return p == root || strings.HasPrefix(p, root+"/")
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/MccRay-s/alist2strm/model/task"
+)
+
+func TestResolveScopePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourcePath string
+		subPath    string
+		want       string
+		wantErr    bool
+	}{
+		{name: "空路径为源路径本身", sourcePath: "/media", subPath: "", want: "/media"},
+		{name: "相对路径", sourcePath: "/media", subPath: "movies/2024", want: "/media/movies/2024"},
+		{name: "相对路径中的 . 和 ..", sourcePath: "/media", subPath: "./movies/../tv", want: "/media/tv"},
+		{name: "反斜杠", sourcePath: "/media", subPath: `movies\2024`, want: "/media/movies/2024"},
+		{name: "源路径末尾的斜杠", sourcePath: "/media/", subPath: "movies", want: "/media/movies"},
+		{name: "绝对路径为源路径本身", sourcePath: "/media", subPath: "/media", want: "/media"},
+		{name: "源路径下的绝对路径", sourcePath: "/media", subPath: "/media/movies/", want: "/media/movies"},
+		{name: "源路径为根目录", sourcePath: "/", subPath: "/anything/else", want: "/anything/else"},
+		{name: "源路径之外的绝对路径", sourcePath: "/media", subPath: "/other/movies", wantErr: true},
+		{name: "前缀相同的兄弟目录", sourcePath: "/media", subPath: "/media2/movies", wantErr: true},
+		{name: "绝对路径中的 .. 越界", sourcePath: "/media", subPath: "/media/../etc", wantErr: true},
+		{name: "相对路径中的 .. 越界", sourcePath: "/media", subPath: "../etc", wantErr: true},
+		{name: "相对路径中间的 .. 越界", sourcePath: "/media", subPath: "movies/../../media2", wantErr: true},
+		{name: "反斜杠中的 .. 越界", sourcePath: "/media", subPath: `..\etc`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveScopePath(&task.Task{SourcePath: tt.sourcePath}, tt.subPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveScopePath(%q, %q) error = %v, wantErr %v", tt.sourcePath, tt.subPath, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("resolveScopePath(%q, %q) = %q, want %q", tt.sourcePath, tt.subPath, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/MccRay-s/alist2strm/model/filehistory"
+	"github.com/MccRay-s/alist2strm/model/task"
+	taskRequest "github.com/MccRay-s/alist2strm/model/task/request"
+	"github.com/MccRay-s/alist2strm/repository"
+	"go.uber.org/zap"
+)
+
+// normalizeSourcePath 标准化源路径，保证映射的写入和查询使用相同格式
+func normalizeSourcePath(sourcePath string) string {
+	return path.Clean(strings.ReplaceAll(sourcePath, "\\", "/"))
+}
+
+// targetFileExists 检查目标文件是否存在
+func (s *StrmGeneratorService) targetFileExists(targetPath string) bool {
+	if targetPath == "" {
+		return false
+	}
+	info, err := os.Stat(targetPath)
+	return err == nil && !info.IsDir()
+}
+
+// recordTargetMapping 记录源文件对应的实际目标文件路径
+func (s *StrmGeneratorService) recordTargetMapping(taskID uint, sourcePath, targetPath string, fileType FileType) {
+	if sourcePath == "" || targetPath == "" {
+		return
+	}
+
+	mapping := &filehistory.TargetMapping{
+		TaskID:     taskID,
+		SourcePath: normalizeSourcePath(sourcePath),
+		TargetPath: targetPath,
+		FileType:   s.getFileTypeString(fileType),
+	}
+	if err := repository.TargetMapping.Upsert(mapping); err != nil {
+		s.logger.Error("记录目标文件映射失败",
+			zap.String("sourcePath", sourcePath),
+			zap.String("targetPath", targetPath),
+			zap.Error(err))
+	}
+}
+
+// findTargetMappings 查找源文件（或源目录下所有文件）对应的目标映射
+func (s *StrmGeneratorService) findTargetMappings(taskID uint, sourcePath string) ([]*filehistory.TargetMapping, error) {
+	normalized := normalizeSourcePath(sourcePath)
+
+	var mappings []*filehistory.TargetMapping
+	mapping, err := repository.TargetMapping.GetBySourcePath(taskID, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if mapping != nil {
+		mappings = append(mappings, mapping)
+	}
+
+	// 源路径可能是目录，此时其下所有文件的映射都需要处理
+	children, err := repository.TargetMapping.ListBySourceDir(taskID, normalized)
+	if err != nil {
+		return nil, err
+	}
+	return append(mappings, children...), nil
+}
+
+// collectMappedTargetFiles 根据映射收集需要处理的目标文件，包括 STRM 文件旁的同名附属文件
+func (s *StrmGeneratorService) collectMappedTargetFiles(mappings []*filehistory.TargetMapping) []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(file string) {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, mapping := range mappings {
+		add(mapping.TargetPath)
+
+		if mapping.FileType != s.getFileTypeString(FileTypeMedia) {
+			continue
+		}
+
+		// 以实际生成的 STRM 文件名为基准查找同名的 .nfo、图片等附属文件
+		baseName := strings.TrimSuffix(filepath.Base(mapping.TargetPath), ".strm")
+		baseName = strings.TrimSuffix(baseName, path.Ext(mapping.SourcePath))
+		pattern := filepath.Join(filepath.Dir(mapping.TargetPath), globEscape(baseName)+".*")
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			s.logger.Warn("查找附属文件失败", zap.String("pattern", pattern), zap.Error(err))
+			continue
+		}
+		for _, match := range matches {
+			add(match)
+		}
+	}
+
+	return files
+}
+
+// globEscape 转义文件名中的通配符，避免文件名中的 [ ] 等字符干扰匹配
+func globEscape(name string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`)
+	return replacer.Replace(name)
+}
+
+// BackfillTargetMappings 为尚无映射记录的任务回填目标文件映射（每个任务仅执行一次），
+// 需在任务队列执行器启动前同步调用，避免与正在执行的任务同时写入映射
+func (s *StrmGeneratorService) BackfillTargetMappings() {
+	tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{})
+	if err != nil {
+		s.logger.Error("回填目标映射时获取任务列表失败", zap.Error(err))
+		return
+	}
+
+	for i := range tasks {
+		taskInfo := &tasks[i]
+		count, err := repository.TargetMapping.CountByTaskID(taskInfo.ID)
+		if err != nil {
+			s.logger.Error("统计目标映射失败", zap.Uint("taskID", taskInfo.ID), zap.Error(err))
+			continue
+		}
+		if count > 0 {
+			continue
+		}
+
+		mapped := s.backfillTaskTargetMappings(taskInfo)
+		s.logger.Info("目标映射回填完成",
+			zap.String("task", taskInfo.Name),
+			zap.Int("mappingCount", mapped))
+	}
+}
+
+// backfillTaskTargetMappings 回填单个任务的目标映射，先依据文件历史，再扫描目标目录补全
+func (s *StrmGeneratorService) backfillTaskTargetMappings(taskInfo *task.Task) int {
+	strmConfig, err := s.loadStrmConfig()
+	if err != nil {
+		s.logger.Warn("回填目标映射时加载 STRM 配置失败", zap.Error(err))
+		return 0
+	}
+
+	mappedTargets := make(map[string]bool)
+	record := func(sourcePath, targetPath string, fileType FileType) {
+		if mappedTargets[targetPath] {
+			return
+		}
+		mappedTargets[targetPath] = true
+		s.recordTargetMapping(taskInfo.ID, sourcePath, targetPath, fileType)
+	}
+
+	// 1. 依据文件历史推算实际的目标路径
+	err = repository.FileHistory.FindByTaskIDInBatches(taskInfo.ID, 500, func(records []*filehistory.FileHistory) error {
+		for _, history := range records {
+			fileType := FileTypeMetadata
+			switch history.FileType {
+			case s.getFileTypeString(FileTypeMedia):
+				fileType = FileTypeMedia
+			case s.getFileTypeString(FileTypeSubtitle):
+				fileType = FileTypeSubtitle
+			}
+
+			candidates := []string{history.TargetFilePath, s.truncatePathLength(history.TargetFilePath)}
+			if fileType == FileTypeMedia && !strings.HasSuffix(history.TargetFilePath, ".strm") {
+				strmName := history.FileName + ".strm"
+				if strmConfig.ReplaceSuffix {
+					strmName = strings.TrimSuffix(history.FileName, filepath.Ext(history.FileName)) + ".strm"
+				}
+				strmPath := filepath.Join(filepath.Dir(history.TargetFilePath), strmName)
+				candidates = append(candidates, strmPath, s.truncatePathLength(strmPath))
+			}
+
+			for _, candidate := range candidates {
+				if s.targetFileExists(candidate) {
+					record(history.SourcePath, candidate, fileType)
+					break
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("回填目标映射时读取文件历史失败", zap.Uint("taskID", taskInfo.ID), zap.Error(err))
+	}
+
+	// 2. 扫描目标目录，为没有文件历史的 STRM 文件根据文件内容反推源路径。
+	// 其他文件无法确认是否由任务生成（可能是用户手动放置的），不记录映射，
+	// STRM 文件旁的同名附属文件在处理 STRM 映射时一并查找
+	if _, err := os.Stat(taskInfo.TargetPath); err != nil {
+		return len(mappedTargets)
+	}
+	err = filepath.WalkDir(taskInfo.TargetPath, func(targetPath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || mappedTargets[targetPath] || !strings.HasSuffix(targetPath, ".strm") {
+			return nil
+		}
+
+		if sourcePath := s.resolveStrmSourcePath(taskInfo, targetPath, strmConfig); sourcePath != "" {
+			record(sourcePath, targetPath, FileTypeMedia)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("回填目标映射时扫描目标目录失败", zap.String("targetPath", taskInfo.TargetPath), zap.Error(err))
+	}
+
+	return len(mappedTargets)
+}
+
+// resolveStrmSourcePath 根据 STRM 文件内容反推源文件路径
+func (s *StrmGeneratorService) resolveStrmSourcePath(taskInfo *task.Task, strmPath string, strmConfig *StrmConfig) string {
+	content, err := os.ReadFile(strmPath)
+	if err != nil {
+		return ""
+	}
+	link := strings.TrimSpace(string(content))
+	sourceRoot := normalizeSourcePath(taskInfo.SourcePath)
+
+	// 本地任务的 STRM 内容就是源文件路径
+	if taskInfo.ConfigType == "local" {
+		return normalizeSourcePath(link)
+	}
+
+	// 远程任务的 STRM 内容是下载链接，在解码后的链接中定位任务源路径
+	if parsed, err := url.Parse(link); err == nil {
+		link = parsed.Path
+	}
+	if decoded, err := url.PathUnescape(link); err == nil {
+		link = decoded
+	}
+	index := strings.Index(link, sourceRoot)
+	if sourceRoot == "/" || index < 0 {
+		// 无法可靠定位时，未替换后缀的 STRM 文件名仍可直接还原
+		if strmConfig.ReplaceSuffix {
+			return ""
+		}
+		relativePath, err := filepath.Rel(taskInfo.TargetPath, strings.TrimSuffix(strmPath, ".strm"))
+		if err != nil {
+			return ""
+		}
+		return path.Join(sourceRoot, filepath.ToSlash(relativePath))
+	}
+	return normalizeSourcePath(link[index:])
+}
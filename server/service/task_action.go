@@ -0,0 +1,407 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/task"
+	taskRequest "github.com/MccRay-s/alist2strm/model/task/request"
+	"github.com/MccRay-s/alist2strm/model/tasklog"
+	"github.com/MccRay-s/alist2strm/repository"
+	"github.com/MccRay-s/alist2strm/utils"
+)
+
+// defaultActionWebhookTimeout Webhook 动作的默认超时时间
+const defaultActionWebhookTimeout = 30 * time.Second
+
+// postActionPayload Webhook 动作发送的请求体
+type postActionPayload struct {
+	Event         string     `json:"event"`
+	TaskID        uint       `json:"taskId"`
+	TaskName      string     `json:"taskName"`
+	TaskLogID     uint       `json:"taskLogId"`
+	Status        string     `json:"status"`
+	Trigger       string     `json:"trigger"`
+	Message       string     `json:"message"`
+	SourcePath    string     `json:"sourcePath"`
+	TargetPath    string     `json:"targetPath"`
+	ScopePath     string     `json:"scopePath,omitempty"`
+	TotalFile     int        `json:"totalFile"`
+	GeneratedFile int        `json:"generatedFile"`
+	SkipFile      int        `json:"skipFile"`
+	FailedCount   int        `json:"failedCount"`
+	Duration      int64      `json:"duration"`
+	StartTime     time.Time  `json:"startTime"`
+	EndTime       *time.Time `json:"endTime"`
+}
+
+// runPostActions 任务执行结束后按条件依次执行后续动作，并将各动作的执行结果记录到任务日志，
+// 返回本次实际执行（未因条件跳过）的动作类型
+func runPostActions(taskInfo *task.Task, taskLog *tasklog.TaskLog) map[string]bool {
+	ran := make(map[string]bool)
+	if len(taskInfo.PostActions) == 0 || taskLog == nil {
+		return ran
+	}
+	// 手动取消的任务不执行后续动作
+	if taskLog.Status != tasklog.TaskLogStatusCompleted && taskLog.Status != tasklog.TaskLogStatusFailed {
+		return ran
+	}
+
+	results := make(tasklog.ActionResults, 0, len(taskInfo.PostActions))
+	for _, action := range taskInfo.PostActions {
+		result := tasklog.ActionResult{Type: action.Type, Target: postActionTarget(&action)}
+
+		if reason := postActionSkipReason(&action, taskLog); reason != "" {
+			result.Status = tasklog.ActionStatusSkipped
+			result.Message = reason
+			results = append(results, result)
+			continue
+		}
+
+		ran[action.Type] = true
+		start := time.Now()
+		message, err := executePostAction(&action, taskInfo, taskLog)
+		result.Duration = time.Since(start).Milliseconds()
+		result.Message = message
+		if err != nil {
+			result.Status = tasklog.ActionStatusFailed
+			if message != "" {
+				result.Message = err.Error() + "\n" + message
+			} else {
+				result.Message = err.Error()
+			}
+			utils.Error("任务后续动作执行失败", "task_id", taskInfo.ID, "type", action.Type, "target", result.Target, "error", err.Error())
+		} else {
+			result.Status = tasklog.ActionStatusSuccess
+			utils.Info("任务后续动作执行成功", "task_id", taskInfo.ID, "type", action.Type, "target", result.Target, "duration_ms", result.Duration)
+		}
+		results = append(results, result)
+	}
+
+	if err := repository.TaskLog.UpdatePartial(taskLog.ID, map[string]interface{}{"action_results": results}); err != nil {
+		utils.Error("保存后续动作执行结果失败", "task_id", taskInfo.ID, "log_id", taskLog.ID, "error", err.Error())
+	}
+	return ran
+}
+
+// postActionSkipReason 判断动作是否满足执行条件，不满足时返回原因
+func postActionSkipReason(action *task.PostAction, taskLog *tasklog.TaskLog) string {
+	switch action.On {
+	case task.ActionOnAlways:
+	case task.ActionOnFailed:
+		if taskLog.Status != tasklog.TaskLogStatusFailed {
+			return "仅在任务失败时执行"
+		}
+	default:
+		if taskLog.Status != tasklog.TaskLogStatusCompleted {
+			return "仅在任务成功时执行"
+		}
+	}
+	if taskLog.ScopePath != "" && !action.Scoped {
+		return "仅在完整扫描后执行"
+	}
+	if taskLog.GeneratedFile < action.MinGenerated {
+		return fmt.Sprintf("生成文件数 %d 未达到 %d", taskLog.GeneratedFile, action.MinGenerated)
+	}
+	return ""
+}
+
+// postActionTarget 动作目标的描述
+func postActionTarget(action *task.PostAction) string {
+	switch action.Type {
+	case task.ActionTypeTask:
+		return digestTaskName(action.TaskID)
+	case task.ActionTypeEmbyRefresh:
+		if len(action.LibraryIDs) == 0 {
+			return "全部媒体库"
+		}
+		return strings.Join(action.LibraryIDs, ",")
+	case task.ActionTypeWebhook:
+		return action.URL
+	case task.ActionTypeCommand:
+		return action.Command
+	default:
+		return ""
+	}
+}
+
+// executePostAction 执行单个后续动作，返回结果说明
+func executePostAction(action *task.PostAction, taskInfo *task.Task, taskLog *tasklog.TaskLog) (string, error) {
+	switch action.Type {
+	case task.ActionTypeTask:
+		return triggerChainedTask(action.TaskID)
+	case task.ActionTypeEmbyRefresh:
+		return refreshEmbyForAction(action.LibraryIDs)
+	case task.ActionTypeWebhook:
+		return callActionWebhook(action, taskInfo, taskLog)
+	case task.ActionTypeCommand:
+		return runActionCommand(action, taskInfo, taskLog)
+	default:
+		return "", fmt.Errorf("不支持的动作类型: %s", action.Type)
+	}
+}
+
+// triggerChainedTask 将后续任务加入执行队列
+func triggerChainedTask(taskID uint) (string, error) {
+	next, err := repository.Task.GetByID(taskID)
+	if err != nil {
+		return "", fmt.Errorf("获取任务失败: %w", err)
+	}
+	if next == nil {
+		return "", errors.New("任务不存在")
+	}
+	if !next.Enabled {
+		return "", errors.New("任务已禁用")
+	}
+
+	GetTaskQueue().AddTask(taskID, task.TriggerChain)
+	return "已加入执行队列", nil
+}
+
+// refreshEmbyForAction 刷新指定的 Emby 媒体库，未指定时刷新全部
+func refreshEmbyForAction(libraryIDs []string) (string, error) {
+	if !Emby.IsConfigured() {
+		return "", errors.New("Emby 未配置")
+	}
+	if len(libraryIDs) == 0 {
+		if err := Emby.RefreshAllLibraries(); err != nil {
+			return "", err
+		}
+		return "已触发全部媒体库刷新", nil
+	}
+
+	var failed []string
+	for _, id := range libraryIDs {
+		if err := Emby.RefreshLibrary(id); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", id, err))
+		}
+	}
+	if len(failed) > 0 {
+		return "", fmt.Errorf("部分媒体库刷新失败: %s", strings.Join(failed, "; "))
+	}
+	return fmt.Sprintf("已触发 %d 个媒体库刷新", len(libraryIDs)), nil
+}
+
+// callActionWebhook 调用外部 Webhook，请求体为本次执行的结果
+func callActionWebhook(action *task.PostAction, taskInfo *task.Task, taskLog *tasklog.TaskLog) (string, error) {
+	payload := postActionPayload{
+		Event:         "task.finished",
+		TaskID:        taskInfo.ID,
+		TaskName:      taskInfo.Name,
+		TaskLogID:     taskLog.ID,
+		Status:        taskLog.Status,
+		Trigger:       taskLog.Trigger,
+		Message:       taskLog.Message,
+		SourcePath:    taskInfo.SourcePath,
+		TargetPath:    taskInfo.TargetPath,
+		ScopePath:     taskLog.ScopePath,
+		TotalFile:     taskLog.TotalFile,
+		GeneratedFile: taskLog.GeneratedFile,
+		SkipFile:      taskLog.SkipFile,
+		FailedCount:   taskLog.FailedCount,
+		Duration:      taskLog.Duration,
+		StartTime:     taskLog.StartTime,
+		EndTime:       taskLog.EndTime,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("序列化请求体失败: %w", err)
+	}
+
+	method := strings.ToUpper(action.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	var reader io.Reader
+	if method != http.MethodGet {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, action.URL, reader)
+	if err != nil {
+		return "", fmt.Errorf("创建请求失败: %w", err)
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range action.Headers {
+		req.Header.Set(key, value)
+	}
+
+	timeout := defaultActionWebhookTimeout
+	if action.Timeout > 0 {
+		timeout = time.Duration(action.Timeout) * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return fmt.Sprintf("HTTP %d", resp.StatusCode), nil
+}
+
+// runActionCommand 执行 Shell 命令，通过环境变量传入本次执行的信息
+func runActionCommand(action *task.PostAction, taskInfo *task.Task, taskLog *tasklog.TaskLog) (string, error) {
+	env := []string{
+		"ALIST2STRM_TASK_ID=" + strconv.FormatUint(uint64(taskInfo.ID), 10),
+		"ALIST2STRM_TASK_NAME=" + taskInfo.Name,
+		"ALIST2STRM_TASK_LOG_ID=" + strconv.FormatUint(uint64(taskLog.ID), 10),
+		"ALIST2STRM_STATUS=" + taskLog.Status,
+		"ALIST2STRM_TRIGGER=" + taskLog.Trigger,
+		"ALIST2STRM_SOURCE_PATH=" + taskInfo.SourcePath,
+		"ALIST2STRM_TARGET_PATH=" + taskInfo.TargetPath,
+		"ALIST2STRM_SCOPE_PATH=" + taskLog.ScopePath,
+		"ALIST2STRM_GENERATED_FILE=" + strconv.Itoa(taskLog.GeneratedFile),
+		"ALIST2STRM_FAILED_COUNT=" + strconv.Itoa(taskLog.FailedCount),
+	}
+	return runShellCommand(action.Command, env, commandTimeout(action.Timeout))
+}
+
+// hasPostAction 判断任务是否配置了指定类型的后续动作
+func hasPostAction(taskInfo *task.Task, actionType string) bool {
+	for _, action := range taskInfo.PostActions {
+		if action.Type == actionType {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePostActions 校验任务的后续动作配置，并检查任务之间的触发是否形成循环
+func validatePostActions(t *task.Task) error {
+	for i, action := range t.PostActions {
+		if !task.IsValidActionType(action.Type) {
+			return fmt.Errorf("第 %d 个后续动作的类型无效: %s", i+1, action.Type)
+		}
+		if !task.IsValidActionOn(action.On) {
+			return fmt.Errorf("第 %d 个后续动作的执行条件无效: %s", i+1, action.On)
+		}
+		if action.MinGenerated < 0 || action.Timeout < 0 {
+			return fmt.Errorf("第 %d 个后续动作的生成文件数阈值和超时时间不能小于 0", i+1)
+		}
+
+		switch action.Type {
+		case task.ActionTypeTask:
+			if action.TaskID == 0 {
+				return fmt.Errorf("第 %d 个后续动作未指定触发的任务", i+1)
+			}
+			if action.TaskID == t.ID {
+				return fmt.Errorf("第 %d 个后续动作不能触发任务自身", i+1)
+			}
+			next, err := repository.Task.GetByID(action.TaskID)
+			if err != nil {
+				return err
+			}
+			if next == nil {
+				return fmt.Errorf("第 %d 个后续动作触发的任务 %d 不存在", i+1, action.TaskID)
+			}
+		case task.ActionTypeWebhook:
+			parsed, err := url.Parse(action.URL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return fmt.Errorf("第 %d 个后续动作的 Webhook 地址无效", i+1)
+			}
+			switch strings.ToUpper(action.Method) {
+			case "", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				return fmt.Errorf("第 %d 个后续动作的请求方法无效: %s", i+1, action.Method)
+			}
+		case task.ActionTypeCommand:
+			if strings.TrimSpace(action.Command) == "" {
+				return fmt.Errorf("第 %d 个后续动作的命令不能为空", i+1)
+			}
+			if !commandsEnabled() {
+				return errCommandDisabled
+			}
+		}
+	}
+
+	if t.ID != 0 && hasPostAction(t, task.ActionTypeTask) {
+		return checkChainCycle(t)
+	}
+	return nil
+}
+
+// checkChainCycle 检查任务之间通过后续动作相互触发是否形成循环
+func checkChainCycle(t *task.Task) error {
+	tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{})
+	if err != nil {
+		return err
+	}
+
+	edges := make(map[uint][]uint, len(tasks))
+	names := make(map[uint]string, len(tasks))
+	for _, other := range tasks {
+		names[other.ID] = other.Name
+		actions := other.PostActions
+		if other.ID == t.ID {
+			actions = t.PostActions
+		}
+		for _, action := range actions {
+			if action.Type == task.ActionTypeTask {
+				edges[other.ID] = append(edges[other.ID], action.TaskID)
+			}
+		}
+	}
+
+	if cycle := findCycle(t.ID, edges); cycle != nil {
+		chain := make([]string, 0, len(cycle))
+		for _, id := range cycle {
+			chain = append(chain, names[id])
+		}
+		return fmt.Errorf("后续动作形成循环触发: %s", strings.Join(chain, " → "))
+	}
+	return nil
+}
+
+// findCycle 从 start 出发沿触发关系深度优先查找循环，返回形成循环的节点（首尾相同），没有循环时返回 nil
+func findCycle[K comparable](start K, edges map[K][]K) []K {
+	visiting := make(map[K]bool)
+	visited := make(map[K]bool)
+	var path []K
+	var visit func(id K) []K
+	visit = func(id K) []K {
+		if visiting[id] {
+			return append(path, id)
+		}
+		if visited[id] {
+			return nil
+		}
+		visiting[id] = true
+		path = append(path, id)
+		for _, next := range edges[id] {
+			if cycle := visit(next); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		visiting[id] = false
+		visited[id] = true
+		return nil
+	}
+
+	cycle := visit(start)
+	if cycle == nil {
+		return nil
+	}
+	// 只保留形成循环的部分
+	last := cycle[len(cycle)-1]
+	for i, id := range cycle {
+		if id == last {
+			return cycle[i:]
+		}
+	}
+	return cycle
+}
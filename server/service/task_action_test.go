@@ -0,0 +1,32 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		start uint
+		edges map[uint][]uint
+		want  []uint
+	}{
+		{name: "没有后续动作", start: 1, edges: map[uint][]uint{}, want: nil},
+		{name: "链式触发", start: 1, edges: map[uint][]uint{1: {2}, 2: {3}}, want: nil},
+		{name: "触发自身", start: 1, edges: map[uint][]uint{1: {1}}, want: []uint{1, 1}},
+		{name: "两个任务互相触发", start: 1, edges: map[uint][]uint{1: {2}, 2: {1}}, want: []uint{1, 2, 1}},
+		{name: "三个任务形成循环", start: 1, edges: map[uint][]uint{1: {2}, 2: {3}, 3: {1}}, want: []uint{1, 2, 3, 1}},
+		{name: "只返回形成循环的部分", start: 1, edges: map[uint][]uint{1: {2}, 2: {3}, 3: {4}, 4: {2}}, want: []uint{2, 3, 4, 2}},
+		{name: "菱形不是循环", start: 1, edges: map[uint][]uint{1: {2, 3}, 2: {4}, 3: {4}}, want: nil},
+		{name: "其他分支中的循环", start: 1, edges: map[uint][]uint{1: {2, 3}, 3: {5}, 5: {3}}, want: []uint{3, 5, 3}},
+		{name: "与起点无关的循环", start: 1, edges: map[uint][]uint{1: {2}, 3: {4}, 4: {3}}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findCycle(tt.start, tt.edges); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findCycle(%d) = %v, want %v", tt.start, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MccRay-s/alist2strm/model/task"
+	"github.com/MccRay-s/alist2strm/model/tasklog"
+	"go.uber.org/zap"
+)
+
+// maxHookOutput 单次任务执行保存到日志中的钩子输出最大长度
+const maxHookOutput = 64 * 1024
+
+// taskHookEnv 任务级钩子的环境变量
+func taskHookEnv(taskInfo *task.Task, taskLogID uint, trigger, sourcePath, targetPath, scopePath string) []string {
+	return []string{
+		"ALIST2STRM_TASK_ID=" + strconv.FormatUint(uint64(taskInfo.ID), 10),
+		"ALIST2STRM_TASK_NAME=" + taskInfo.Name,
+		"ALIST2STRM_TASK_LOG_ID=" + strconv.FormatUint(uint64(taskLogID), 10),
+		"ALIST2STRM_TRIGGER=" + trigger,
+		"ALIST2STRM_SOURCE_PATH=" + sourcePath,
+		"ALIST2STRM_TARGET_PATH=" + targetPath,
+		"ALIST2STRM_SCOPE_PATH=" + scopePath,
+	}
+}
+
+// runTaskHook 执行任务级钩子，仅在失败策略为 failTask 且执行失败时返回错误
+func (s *StrmGeneratorService) runTaskHook(point string, taskInfo *task.Task, env []string) error {
+	hook := taskInfo.Hooks.Get(point)
+	if hook == nil {
+		return nil
+	}
+	err := s.runHook(point, hook, taskInfo, "", env)
+	if err != nil && hook.OnFailure == task.HookFailureFailTask {
+		return err
+	}
+	return nil
+}
+
+// runFileHook 执行文件级钩子，返回非空的错误消息时表示当前文件应标记为处理失败；
+// 失败策略为 failTask 时同时终止本次任务执行
+func (s *StrmGeneratorService) runFileHook(point string, taskInfo *task.Task, fileType FileType, file *AListFile, sourcePath, targetPath, fileURL string) string {
+	hook := taskInfo.Hooks.Get(point)
+	if hook == nil {
+		return ""
+	}
+
+	env := []string{
+		"ALIST2STRM_TASK_ID=" + strconv.FormatUint(uint64(taskInfo.ID), 10),
+		"ALIST2STRM_TASK_NAME=" + taskInfo.Name,
+		"ALIST2STRM_SOURCE_PATH=" + sourcePath,
+		"ALIST2STRM_TARGET_PATH=" + targetPath,
+		"ALIST2STRM_FILE_NAME=" + file.Name,
+		"ALIST2STRM_FILE_TYPE=" + s.getFileTypeString(fileType),
+		"ALIST2STRM_URL=" + fileURL,
+	}
+	err := s.runHook(point, hook, taskInfo, targetPath, env)
+	if err == nil {
+		return ""
+	}
+
+	switch hook.OnFailure {
+	case task.HookFailureFailFile:
+		return err.Error()
+	case task.HookFailureFailTask:
+		s.abortRun(taskInfo.ID, err.Error())
+		return err.Error()
+	}
+	return ""
+}
+
+// runHook 执行钩子命令，将输出和错误记录到当前执行的任务日志中
+func (s *StrmGeneratorService) runHook(point string, hook *task.Hook, taskInfo *task.Task, target string, env []string) error {
+	env = append(env, "ALIST2STRM_HOOK="+point)
+	output, err := runShellCommand(hook.Command, env, commandTimeout(hook.Timeout))
+	if err != nil {
+		err = fmt.Errorf("%s 钩子执行失败: %w", point, err)
+		s.logger.Warn("钩子执行失败",
+			zap.Uint("taskId", taskInfo.ID),
+			zap.String("hook", point),
+			zap.String("target", target),
+			zap.String("output", output),
+			zap.Error(err))
+	}
+
+	if output != "" || err != nil {
+		var entry strings.Builder
+		entry.WriteString("[" + point + "]")
+		if target != "" {
+			entry.WriteString(" " + target)
+		}
+		entry.WriteString("\n")
+		if output != "" {
+			entry.WriteString(output + "\n")
+		}
+		if err != nil {
+			entry.WriteString(err.Error() + "\n")
+		}
+		s.appendHookOutput(taskInfo.ID, entry.String())
+	}
+	if err != nil {
+		if run := s.currentRun(); run != nil && run.taskID == taskInfo.ID {
+			runLogs.add(taskInfo.ID, run.taskLogID, tasklog.LogLevelWarn, tasklog.LogEventHook, target, err.Error())
+		}
+	}
+	return err
+}
+
+// appendHookOutput 将钩子输出追加到当前执行任务的记录中，超出长度上限后丢弃
+func (s *StrmGeneratorService) appendHookOutput(taskID uint, entry string) {
+	s.mu.RLock()
+	run := s.run
+	s.mu.RUnlock()
+	if run == nil || run.taskID != taskID {
+		return
+	}
+
+	run.hookMu.Lock()
+	defer run.hookMu.Unlock()
+	if run.hookTruncated {
+		return
+	}
+	if run.hookOutput.Len()+len(entry) > maxHookOutput {
+		run.hookOutput.WriteString("...（钩子输出过长，后续输出已省略）\n")
+		run.hookTruncated = true
+		return
+	}
+	run.hookOutput.WriteString(entry)
+}
+
+// hookOutput 获取当前执行任务已记录的钩子输出
+func (s *StrmGeneratorService) hookOutput(taskLogID uint) string {
+	s.mu.RLock()
+	run := s.run
+	s.mu.RUnlock()
+	if run == nil || run.taskLogID != taskLogID {
+		return ""
+	}
+
+	run.hookMu.Lock()
+	defer run.hookMu.Unlock()
+	return strings.TrimSpace(run.hookOutput.String())
+}
+
+// validateHooks 校验任务的钩子配置
+func validateHooks(t *task.Task) error {
+	for _, point := range task.HookPoints {
+		hook := t.Hooks.Get(point)
+		if hook == nil {
+			continue
+		}
+		if strings.TrimSpace(hook.Command) == "" {
+			return fmt.Errorf("%s 钩子的命令不能为空", point)
+		}
+		if hook.Timeout < 0 {
+			return fmt.Errorf("%s 钩子的超时时间不能小于 0", point)
+		}
+		if !task.IsValidHookFailure(point, hook.OnFailure) {
+			return fmt.Errorf("%s 钩子的失败处理策略无效: %s", point, hook.OnFailure)
+		}
+		if !commandsEnabled() {
+			return errCommandDisabled
+		}
+	}
+	return nil
+}
+
+// runAfterTaskHook 执行任务结束后的钩子，额外传入本次执行的状态和统计信息
+func (s *StrmGeneratorService) runAfterTaskHook(taskInfo *task.Task, env []string, status string, generated, failed int) error {
+	env = append(env,
+		"ALIST2STRM_STATUS="+status,
+		"ALIST2STRM_GENERATED_FILE="+strconv.Itoa(generated),
+		"ALIST2STRM_FAILED_COUNT="+strconv.Itoa(failed),
+	)
+	return s.runTaskHook(task.HookAfterTask, taskInfo, env)
+}
@@ -1,13 +1,19 @@
 package service
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
+	"github.com/MccRay-s/alist2strm/model/task"
 	"github.com/MccRay-s/alist2strm/model/tasklog"
 	taskLogRequest "github.com/MccRay-s/alist2strm/model/tasklog/request"
 	taskLogResponse "github.com/MccRay-s/alist2strm/model/tasklog/response"
 	"github.com/MccRay-s/alist2strm/repository"
+	"github.com/MccRay-s/alist2strm/utils"
 )
 
 type TaskLogService struct{}
@@ -196,3 +202,303 @@ func (s *TaskLogService) GetFileProcessingStats(timeRange string) (*taskLogRespo
 		SubtitleDownloaded: subtitleDownloaded,
 	}, nil
 }
+
+// GetFileFailureList 获取某次运行的失败文件列表
+func (s *TaskLogService) GetFileFailureList(req *taskLogRequest.FileFailureListReq) (*taskLogResponse.FileFailureListResp, error) {
+	taskLog, err := repository.TaskLog.GetByID(req.TaskLogID)
+	if err != nil {
+		return nil, fmt.Errorf("查询任务日志失败: %v", err)
+	}
+	if taskLog == nil {
+		return nil, fmt.Errorf("任务日志不存在")
+	}
+
+	failures, total, err := repository.FileFailure.ListByTaskLogID(req)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败文件列表失败: %v", err)
+	}
+
+	return &taskLogResponse.FileFailureListResp{
+		List:     failures,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// RetryFailures 仅重新处理某次运行中未解决的失败文件，不重新扫描整个任务。
+// 重试加入执行队列，由执行器与该任务的其他执行依次进行
+func (s *TaskLogService) RetryFailures(taskLogID uint) (*taskLogResponse.RetryFailuresResp, error) {
+	taskLog, err := repository.TaskLog.GetByID(taskLogID)
+	if err != nil {
+		return nil, fmt.Errorf("查询任务日志失败: %v", err)
+	}
+	if taskLog == nil {
+		return nil, fmt.Errorf("任务日志不存在")
+	}
+	if taskLog.Status == tasklog.TaskLogStatusRunning {
+		return nil, fmt.Errorf("该次运行尚未结束，无法重试")
+	}
+
+	taskInfo, err := repository.Task.GetByID(taskLog.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("查询任务失败: %v", err)
+	}
+	if taskInfo == nil {
+		return nil, fmt.Errorf("任务不存在")
+	}
+
+	failures, err := repository.FileFailure.GetUnresolvedByTaskLogID(taskLogID)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败文件失败: %v", err)
+	}
+	if len(failures) == 0 {
+		return nil, fmt.Errorf("没有需要重试的失败文件")
+	}
+
+	if err := GetTaskQueue().AddRetry(taskInfo.ID, taskLogID); err != nil {
+		return nil, err
+	}
+
+	utils.Info("已提交失败文件重试", "task_id", taskInfo.ID, "task_log_id", taskLogID, "count", len(failures))
+
+	return &taskLogResponse.RetryFailuresResp{
+		TaskLogID:  taskLogID,
+		RetryCount: len(failures),
+	}, nil
+}
+
+// executeRetry 由队列执行器调用，重新处理任务日志中仍未解决的失败文件，结果记录到新的任务日志中
+func (s *TaskLogService) executeRetry(taskLogID uint) error {
+	taskLog, err := repository.TaskLog.GetByID(taskLogID)
+	if err != nil {
+		return fmt.Errorf("查询任务日志失败: %v", err)
+	}
+	if taskLog == nil {
+		return fmt.Errorf("任务日志不存在")
+	}
+
+	taskInfo, err := repository.Task.GetByID(taskLog.TaskID)
+	if err != nil {
+		return fmt.Errorf("查询任务失败: %v", err)
+	}
+	if taskInfo == nil {
+		return fmt.Errorf("任务不存在")
+	}
+	if taskInfo.Running {
+		return fmt.Errorf("任务正在运行中")
+	}
+
+	// 入队后可能已被其他执行处理，以执行时仍未解决的记录为准
+	failures, err := repository.FileFailure.GetUnresolvedByTaskLogID(taskLogID)
+	if err != nil {
+		return fmt.Errorf("查询失败文件失败: %v", err)
+	}
+	if len(failures) == 0 {
+		utils.Info("失败文件已全部解决，跳过重试", "task_id", taskInfo.ID, "task_log_id", taskLogID)
+		return nil
+	}
+
+	strmService := GetStrmGeneratorService()
+	if !strmService.IsInitialized() {
+		return fmt.Errorf("STRM 生成服务未正确初始化")
+	}
+
+	retryLog := &tasklog.TaskLog{
+		TaskID:    taskInfo.ID,
+		Status:    tasklog.TaskLogStatusRunning,
+		Message:   fmt.Sprintf("重试任务日志 #%d 的失败文件", taskLogID),
+		Trigger:   task.TriggerRetry,
+		StartTime: time.Now(),
+	}
+	if err := repository.TaskLog.Create(retryLog); err != nil {
+		return fmt.Errorf("创建任务日志失败: %v", err)
+	}
+
+	if err := repository.Task.UpdateRunningStatus(taskInfo.ID, true); err != nil {
+		return fmt.Errorf("更新任务运行状态失败: %v", err)
+	}
+	defer func() {
+		if updateErr := repository.Task.UpdateRunningStatus(taskInfo.ID, false); updateErr != nil {
+			utils.Error("更新任务运行状态失败", "task_id", taskInfo.ID, "error", updateErr.Error())
+		}
+	}()
+
+	return strmService.RetryFileFailures(taskInfo, retryLog.ID, failures)
+}
+
+// GetLogEntryList 分页获取某次运行的日志条目
+func (s *TaskLogService) GetLogEntryList(req *taskLogRequest.LogEntryListReq) (*taskLogResponse.LogEntryListResp, error) {
+	taskLog, err := repository.TaskLog.GetByID(req.TaskLogID)
+	if err != nil {
+		return nil, fmt.Errorf("查询任务日志失败: %v", err)
+	}
+	if taskLog == nil {
+		return nil, fmt.Errorf("任务日志不存在")
+	}
+
+	// 正在执行的运行先写入缓存中的条目，保证查询到最新的日志
+	if taskLog.Status == tasklog.TaskLogStatusRunning {
+		runLogs.flush()
+	}
+
+	entries, total, err := repository.TaskLogEntry.ListByTaskLogID(req)
+	if err != nil {
+		return nil, fmt.Errorf("查询运行日志失败: %v", err)
+	}
+
+	return &taskLogResponse.LogEntryListResp{
+		List:     entries,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// ExportLogEntries 导出某次运行中不低于指定级别的日志条目，校验通过后返回按行写出纯文本日志的函数
+func (s *TaskLogService) ExportLogEntries(taskLogID uint, level string) (func(w io.Writer) error, error) {
+	if level != "" && tasklog.LogLevelRank(level) < 0 {
+		return nil, fmt.Errorf("无效的日志级别: %s", level)
+	}
+	taskLog, err := repository.TaskLog.GetByID(taskLogID)
+	if err != nil {
+		return nil, fmt.Errorf("查询任务日志失败: %v", err)
+	}
+	if taskLog == nil {
+		return nil, fmt.Errorf("任务日志不存在")
+	}
+	if taskLog.Status == tasklog.TaskLogStatusRunning {
+		runLogs.flush()
+	}
+
+	return func(w io.Writer) error {
+		buf := bufio.NewWriter(w)
+		err := repository.TaskLogEntry.EachByTaskLogID(taskLogID, level, func(entries []tasklog.LogEntry) error {
+			for _, entry := range entries {
+				line := fmt.Sprintf("%s [%s] [%s]", entry.CreatedAt.Format("2006-01-02 15:04:05.000"), strings.ToUpper(entry.Level), entry.Event)
+				if entry.Path != "" {
+					line += " " + entry.Path
+				}
+				if _, err := buf.WriteString(line + " " + entry.Message + "\n"); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return buf.Flush()
+	}, nil
+}
+
+// progressStreamInterval 运行进度的推送间隔
+const progressStreamInterval = time.Second
+
+// StreamProgress 推送任务日志对应运行的实时进度：连接后立即推送一次当前快照（progress 事件），之后按固定间隔推送，
+// 运行结束后推送最终的任务日志（done 事件）并返回。emit 返回错误（如客户端已断开）时停止推送
+func (s *TaskLogService) StreamProgress(ctx context.Context, taskLogID uint, emit func(event string, data interface{}) error) error {
+	taskLog, err := repository.TaskLog.GetByID(taskLogID)
+	if err != nil {
+		return fmt.Errorf("查询任务日志失败: %v", err)
+	}
+	if taskLog == nil {
+		return fmt.Errorf("任务日志不存在")
+	}
+
+	ticker := time.NewTicker(progressStreamInterval)
+	defer ticker.Stop()
+
+	strmService := GetStrmGeneratorService()
+	var last *taskLogResponse.TaskLogProgressResp
+	for {
+		var snapshot *taskLogResponse.TaskLogProgressResp
+		if progress, running := strmService.GetRunProgress(taskLogID); running {
+			snapshot = newProgressResp(progress, last)
+		} else {
+			taskLog, err = repository.TaskLog.GetByID(taskLogID)
+			if err != nil {
+				return fmt.Errorf("查询任务日志失败: %v", err)
+			}
+			if taskLog == nil {
+				return fmt.Errorf("任务日志不存在")
+			}
+			if taskLog.Status != tasklog.TaskLogStatusRunning {
+				return emit("done", taskLog)
+			}
+			// 日志处于执行中但不是当前正在生成 STRM 的运行（如失败文件重试），推送数据库中已保存的统计
+			snapshot = progressRespFromLog(taskLog)
+		}
+
+		if err := emit("progress", snapshot); err != nil {
+			return err
+		}
+		last = snapshot
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// newProgressResp 根据运行进度生成推送快照，last 为上一次推送的快照，用于计算最近的处理速度
+func newProgressResp(progress *TaskProgress, last *taskLogResponse.TaskLogProgressResp) *taskLogResponse.TaskLogProgressResp {
+	now := time.Now()
+	elapsed := now.Sub(progress.StartTime)
+	resp := &taskLogResponse.TaskLogProgressResp{
+		TaskLogID:              progress.TaskLogID,
+		TaskID:                 progress.TaskID,
+		Status:                 tasklog.TaskLogStatusRunning,
+		ScopePath:              progress.ScopePath,
+		CurrentDir:             progress.CurrentDir,
+		StartTime:              progress.StartTime,
+		Elapsed:                int64(elapsed.Seconds()),
+		TotalFiles:             progress.TotalFiles,
+		ProcessedFiles:         progress.ProcessedFiles,
+		GeneratedFile:          progress.GeneratedFile,
+		SkipFile:               progress.SkipFile,
+		MetadataDownloaded:     progress.MetadataDownloaded,
+		SubtitleDownloaded:     progress.SubtitleDownloaded,
+		FailedCount:            progress.FailedCount,
+		StrmQueueSize:          progress.StrmQueueSize,
+		DownloadQueueSize:      progress.DownloadQueueSize,
+		ScanFinished:           progress.ScanFinished,
+		StrmProcessingDone:     progress.StrmProcessingDone,
+		DownloadProcessingDone: progress.DownloadProcessingDone,
+		Cancelling:             progress.Cancelling,
+		UpdatedAt:              now,
+	}
+	if elapsed > 0 {
+		resp.FilesPerSecond = float64(progress.ProcessedFiles) / elapsed.Seconds()
+	}
+	if last != nil {
+		if interval := now.Sub(last.UpdatedAt); interval > 0 {
+			resp.RecentFilesPerSecond = float64(progress.ProcessedFiles-last.ProcessedFiles) / interval.Seconds()
+		}
+	} else {
+		resp.RecentFilesPerSecond = resp.FilesPerSecond
+	}
+	return resp
+}
+
+// progressRespFromLog 根据任务日志中已保存的统计生成推送快照
+func progressRespFromLog(taskLog *tasklog.TaskLog) *taskLogResponse.TaskLogProgressResp {
+	now := time.Now()
+	return &taskLogResponse.TaskLogProgressResp{
+		TaskLogID:          taskLog.ID,
+		TaskID:             taskLog.TaskID,
+		Status:             taskLog.Status,
+		ScopePath:          taskLog.ScopePath,
+		StartTime:          taskLog.StartTime,
+		Elapsed:            int64(now.Sub(taskLog.StartTime).Seconds()),
+		TotalFiles:         taskLog.TotalFile,
+		GeneratedFile:      taskLog.GeneratedFile,
+		SkipFile:           taskLog.SkipFile,
+		MetadataDownloaded: taskLog.MetadataDownloaded,
+		SubtitleDownloaded: taskLog.SubtitleDownloaded,
+		FailedCount:        taskLog.FailedCount,
+		UpdatedAt:          now,
+	}
+}
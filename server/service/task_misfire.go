@@ -0,0 +1,106 @@
+package service
+
+import (
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/task"
+	"github.com/MccRay-s/alist2strm/repository"
+	"github.com/MccRay-s/alist2strm/utils"
+)
+
+// CatchUpMissedRuns 启动时根据各任务的补偿策略，补偿服务停机期间错过的定时执行
+func (s *TaskScheduler) CatchUpMissedRuns() {
+	tasks, err := repository.Task.GetAllEnabled()
+	if err != nil {
+		utils.Error("获取已启用任务失败，无法补偿错过的定时执行", "error", err.Error())
+		return
+	}
+
+	now := time.Now()
+	for i := range tasks {
+		s.catchUp(&tasks[i], now)
+	}
+}
+
+// misfireRunLimit 获取任务最多补偿执行的次数，为 0 表示不补偿
+func misfireRunLimit(t *task.Task) int {
+	switch t.MisfirePolicy {
+	case task.MisfirePolicyOnce:
+		return 1
+	case task.MisfirePolicyLimit:
+		if t.MisfireLimit > task.MaxMisfireLimit {
+			return task.MaxMisfireLimit
+		}
+		return t.MisfireLimit
+	default:
+		return 0
+	}
+}
+
+// catchUp 对比任务最后执行时间与调度计划，按补偿策略将错过的执行加入队列
+func (s *TaskScheduler) catchUp(t *task.Task, now time.Time) {
+	limit := misfireRunLimit(t)
+	if limit <= 0 || t.Cron == "" {
+		return
+	}
+
+	// 服务重启前已排队的完整扫描已由队列恢复，会补上错过的执行，不再重复添加
+	if GetTaskQueue().HasPendingFullScan(t.ID) {
+		utils.Info("任务已有恢复的完整扫描在队列中，跳过补偿执行", "task_id", t.ID, "name", t.Name)
+		return
+	}
+
+	schedule, err := parseCronSpec(t.Cron, t.Timezone)
+	if err != nil {
+		utils.Warn("任务调度表达式无效，跳过补偿执行", "task_id", t.ID, "cron", t.Cron, "error", err.Error())
+		return
+	}
+
+	// 从未执行过的任务从创建时间开始计算
+	from := t.CreatedAt
+	if t.LastRunAt != nil {
+		from = *t.LastRunAt
+	}
+
+	loc := scheduleLocation(specTimezone(t.Cron, t.Timezone))
+	missed := 0
+	var firstMissed time.Time
+	for next := schedule.Next(from.In(loc)); !next.IsZero() && !next.After(now) && missed < limit; next = schedule.Next(next) {
+		if missed == 0 {
+			firstMissed = next
+		}
+		missed++
+	}
+	if missed == 0 {
+		return
+	}
+
+	utils.Info("检测到停机期间错过的定时执行", "task_id", t.ID, "name", t.Name, "policy", t.MisfirePolicy,
+		"first_missed", firstMissed.Format("2006-01-02 15:04:05"), "runs", missed)
+
+	taskID := t.ID
+	windows, err := taskBlackoutWindows(t.BlackoutWindows)
+	if err != nil {
+		utils.Warn("禁止执行时段设置无效，忽略", "task_id", taskID, "error", err.Error())
+	}
+	if end, deferred := blackoutEnd(windows, now.In(loc)); deferred {
+		s.deferRun(taskID, t.Name, end, func() { s.enqueueCatchUp(taskID, missed) })
+		return
+	}
+	s.enqueueCatchUp(taskID, missed)
+}
+
+// enqueueCatchUp 将补偿执行加入执行队列
+func (s *TaskScheduler) enqueueCatchUp(taskID uint, runs int) {
+	currentTask, err := repository.Task.GetByID(taskID)
+	if err != nil {
+		utils.Error("获取任务信息失败", "task_id", taskID, "error", err.Error())
+		return
+	}
+	if currentTask == nil || !currentTask.Enabled {
+		utils.Info("任务不存在或已禁用，跳过补偿执行", "task_id", taskID)
+		return
+	}
+
+	GetTaskQueue().AddCatchUpRuns(taskID, runs)
+}
@@ -1,18 +1,23 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/MccRay-s/alist2strm/model/task"
+	taskRequest "github.com/MccRay-s/alist2strm/model/task/request"
+	taskResponse "github.com/MccRay-s/alist2strm/model/task/response"
+	"github.com/MccRay-s/alist2strm/model/webhook"
 	"github.com/MccRay-s/alist2strm/repository"
 	"github.com/MccRay-s/alist2strm/utils"
 )
 
-// TaskQueue 任务队列
+// TaskQueue 任务队列，队列条目持久化在数据库中，按优先级和排列顺序逐个执行
 type TaskQueue struct {
-	queue    []uint        // 任务ID队列
-	running  bool          // 执行器是否正在运行
-	mutex    sync.Mutex    // 互斥锁
+	running  bool          // 执行器是否正在执行任务
+	mutex    sync.Mutex    // 互斥锁，保证入队去重与出队的原子性
 	cond     *sync.Cond    // 条件变量，用于任务通知
 	shutdown chan struct{} // 关闭信号
 }
@@ -28,7 +33,6 @@ func GetTaskQueue() *TaskQueue {
 	taskQueueOnce.Do(func() {
 		// 创建一个互斥锁
 		taskQueue = &TaskQueue{
-			queue:    make([]uint, 0),
 			running:  false,
 			mutex:    sync.Mutex{},
 			shutdown: make(chan struct{}),
@@ -48,6 +52,9 @@ func StartTaskQueue() {
 	tq.running = false
 	tq.mutex.Unlock()
 
+	// 恢复服务停止前未完成的队列
+	tq.recover()
+
 	// 启动任务执行器
 	go tq.executor()
 
@@ -62,147 +69,404 @@ func StartTaskQueue() {
 	}()
 }
 
+// recover 处理服务停止时被中断的执行：正在执行的队列条目恢复为等待状态以便重新执行，
+// 仍标记为运行中的任务及其执行日志记录为中断
+func (tq *TaskQueue) recover() {
+	interrupted, err := repository.TaskQueue.ResetRunning()
+	if err != nil {
+		utils.Error("恢复被中断的队列条目失败", "error", err.Error())
+	}
+	for _, entry := range interrupted {
+		utils.Warn("任务执行因服务重启被中断，将重新执行", "task_id", entry.TaskID, "sub_path", entry.SubPath, "trigger", entry.Trigger)
+	}
+
+	running := true
+	tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{Running: &running})
+	if err != nil {
+		utils.Error("获取运行中的任务失败", "error", err.Error())
+	}
+	now := time.Now()
+	for _, t := range tasks {
+		if _, err := repository.TaskLog.FailRunningByTaskID(t.ID, "服务重启，执行被中断", now); err != nil {
+			utils.Error("更新被中断的任务日志失败", "task_id", t.ID, "error", err.Error())
+		}
+		if err := repository.Task.UpdateRunningStatus(t.ID, false); err != nil {
+			utils.Error("重置任务运行状态失败", "task_id", t.ID, "error", err.Error())
+			continue
+		}
+		utils.Warn("已重置被中断任务的运行状态", "task_id", t.ID, "name", t.Name)
+	}
+
+	if pending := tq.GetQueueLength(); pending > 0 {
+		utils.Info("已恢复执行队列", "queue_length", pending)
+	}
+}
+
 // AddTask 添加任务到队列
-func (tq *TaskQueue) AddTask(taskID uint) {
-	utils.Info("正在尝试添加任务到队列", "task_id", taskID)
+func (tq *TaskQueue) AddTask(taskID uint, trigger string) {
+	tq.AddScopedTask(taskID, "", trigger)
+}
+
+// AddScopedTask 添加局部扫描任务到队列，subPath 为空时等同于完整扫描。
+// 队列按触发来源的优先级执行，同一任务已有等待中的执行时合并触发：
+// 已排队的完整扫描覆盖任何局部扫描，合并时保留较高的优先级
+func (tq *TaskQueue) AddScopedTask(taskID uint, subPath string, trigger string) {
+	priority := task.TriggerPriority(trigger)
+	subPath = normalizeQueueScope(taskID, subPath)
+	utils.Info("正在尝试添加任务到队列", "task_id", taskID, "sub_path", subPath, "trigger", trigger, "priority", priority)
 
 	tq.mutex.Lock()
 	defer tq.mutex.Unlock()
 
-	// 检查任务是否已在队列中
-	for _, id := range tq.queue {
-		if id == taskID {
-			utils.Info("任务已在队列中，跳过添加", "task_id", taskID)
+	pending, err := repository.TaskQueue.ListPending()
+	if err != nil {
+		utils.Error("获取执行队列失败", "task_id", taskID, "error", err.Error())
+		return
+	}
+
+	// 合并到已在队列中且覆盖本次扫描范围的条目
+	var covered []task.QueueEntry
+	for _, entry := range pending {
+		// 失败文件重试只处理记录的文件，不参与扫描的合并
+		if entry.TaskID != taskID || entry.Kind == task.QueueKindRetry {
+			continue
+		}
+		if entry.Kind == task.QueueKindFile {
+			// 扫描范围包含的单文件处理由本次扫描一并完成
+			if scanCovers(subPath, entry.SubPath) {
+				covered = append(covered, entry)
+			}
+			continue
+		}
+		if entry.SubPath == "" || entry.SubPath == subPath {
+			tq.merge(&entry, trigger, priority)
 			return
 		}
+		if subPath == "" {
+			covered = append(covered, entry)
+		}
 	}
 
-	// 检查执行器状态
-	executorRunning := tq.running
-	queueLen := len(tq.queue)
+	// 完整扫描覆盖已排队的局部扫描
+	for _, entry := range covered {
+		if entry.Kind != task.QueueKindFile && entry.Priority > priority {
+			priority = entry.Priority
+			trigger = entry.Trigger
+		}
+		if err := repository.TaskQueue.Delete(entry.ID); err != nil {
+			utils.Error("移除被合并的队列条目失败", "entry_id", entry.ID, "error", err.Error())
+			continue
+		}
+		utils.Info("已排队的条目已合并到本次扫描", "task_id", taskID, "entry_id", entry.ID, "kind", entry.Kind, "sub_path", entry.SubPath)
+	}
 
 	// 添加到队列
-	tq.queue = append(tq.queue, taskID)
-	utils.Info("任务已添加到队列", "task_id", taskID, "queue_length", len(tq.queue), "executor_running", executorRunning)
+	entry := &task.QueueEntry{
+		TaskID:   taskID,
+		Kind:     task.QueueKindScan,
+		SubPath:  subPath,
+		Trigger:  trigger,
+		Priority: priority,
+		Status:   task.QueueStatusPending,
+	}
+	if err := repository.TaskQueue.Create(entry); err != nil {
+		utils.Error("添加任务到队列失败", "task_id", taskID, "error", err.Error())
+		return
+	}
+	utils.Info("任务已添加到队列", "task_id", taskID, "entry_id", entry.ID, "queue_length", len(pending)+1-len(covered), "executor_running", tq.running)
 
 	// 通知执行器有新任务
-	utils.Info("发送信号通知执行器处理新任务", "task_id", taskID, "queue_before", queueLen, "queue_after", len(tq.queue))
 	tq.cond.Signal()
 }
 
-// executor 任务执行器
+// AddFileEvent 将文件变更通知中新增的文件或目录加入队列，由执行器逐个处理，只生成该文件（目录）对应的内容，
+// 不创建任务日志，新增媒体通知按批次汇总。已排队的扫描包含该路径或同一路径已在队列中时不再重复添加
+func (tq *TaskQueue) AddFileEvent(taskID uint, filePath string, isDir bool) {
+	filePath = normalizeSourcePath(filePath)
+
+	tq.mutex.Lock()
+	defer tq.mutex.Unlock()
+
+	pending, err := repository.TaskQueue.ListPending()
+	if err != nil {
+		utils.Error("获取执行队列失败", "task_id", taskID, "error", err.Error())
+		return
+	}
+	for _, entry := range pending {
+		if entry.TaskID != taskID || entry.Kind == task.QueueKindRetry {
+			continue
+		}
+		if entry.Kind == task.QueueKindFile && entry.SubPath == filePath {
+			utils.Info("文件已在队列中，合并本次通知", "task_id", taskID, "entry_id", entry.ID, "path", filePath)
+			return
+		}
+		if entry.Kind != task.QueueKindFile && scanCovers(entry.SubPath, filePath) {
+			utils.Info("已排队的扫描包含该文件，合并本次通知", "task_id", taskID, "entry_id", entry.ID, "path", filePath, "sub_path", entry.SubPath)
+			return
+		}
+	}
+
+	entry := &task.QueueEntry{
+		TaskID:   taskID,
+		Kind:     task.QueueKindFile,
+		SubPath:  filePath,
+		IsDir:    isDir,
+		Trigger:  task.TriggerWebhook,
+		Priority: task.TriggerPriority(task.TriggerWebhook),
+		Status:   task.QueueStatusPending,
+	}
+	if err := repository.TaskQueue.Create(entry); err != nil {
+		utils.Error("添加文件变更到队列失败", "task_id", taskID, "path", filePath, "error", err.Error())
+		return
+	}
+	utils.Info("文件变更已添加到队列", "task_id", taskID, "entry_id", entry.ID, "path", filePath, "is_dir", isDir)
+	tq.cond.Signal()
+}
+
+// AddRetry 将重试某次运行中失败文件的请求加入队列，由执行器与其他执行依次进行，同一任务日志已在队列中时不再重复添加
+func (tq *TaskQueue) AddRetry(taskID uint, taskLogID uint) error {
+	tq.mutex.Lock()
+	defer tq.mutex.Unlock()
+
+	pending, err := repository.TaskQueue.ListPending()
+	if err != nil {
+		return fmt.Errorf("获取执行队列失败: %w", err)
+	}
+	for _, entry := range pending {
+		if entry.Kind == task.QueueKindRetry && entry.TaskLogID == taskLogID {
+			utils.Info("该次运行的失败文件已在重试队列中", "task_id", taskID, "task_log_id", taskLogID, "entry_id", entry.ID)
+			return nil
+		}
+	}
+
+	entry := &task.QueueEntry{
+		TaskID:    taskID,
+		Kind:      task.QueueKindRetry,
+		TaskLogID: taskLogID,
+		Trigger:   task.TriggerRetry,
+		Priority:  task.TriggerPriority(task.TriggerRetry),
+		Status:    task.QueueStatusPending,
+	}
+	if err := repository.TaskQueue.Create(entry); err != nil {
+		return fmt.Errorf("添加重试到队列失败: %w", err)
+	}
+	utils.Info("失败文件重试已添加到队列", "task_id", taskID, "task_log_id", taskLogID, "entry_id", entry.ID)
+	tq.cond.Signal()
+	return nil
+}
+
+// normalizeQueueScope 将与任务源路径相同的局部扫描路径视为完整扫描，便于与已排队的完整扫描合并
+func normalizeQueueScope(taskID uint, subPath string) string {
+	if subPath == "" {
+		return ""
+	}
+	taskInfo, err := repository.Task.GetByID(taskID)
+	if err != nil || taskInfo == nil {
+		return subPath
+	}
+	if normalizeSourcePath(subPath) == normalizeSourcePath(taskInfo.SourcePath) {
+		return ""
+	}
+	return subPath
+}
+
+// scanCovers 判断扫描范围是否包含指定路径，scope 为空表示完整扫描
+func scanCovers(scope, p string) bool {
+	return scope == "" || isPathWithin(normalizeSourcePath(p), normalizeSourcePath(scope))
+}
+
+// AddCatchUpRuns 添加补偿执行：第一次与已排队的执行合并，其余按次数依次排队
+func (tq *TaskQueue) AddCatchUpRuns(taskID uint, runs int) {
+	tq.AddTask(taskID, task.TriggerCatchUp)
+	if runs <= 1 {
+		return
+	}
+
+	tq.mutex.Lock()
+	defer tq.mutex.Unlock()
+
+	for i := 1; i < runs; i++ {
+		entry := &task.QueueEntry{
+			TaskID:   taskID,
+			Kind:     task.QueueKindScan,
+			Trigger:  task.TriggerCatchUp,
+			Priority: task.TriggerPriority(task.TriggerCatchUp),
+			Status:   task.QueueStatusPending,
+		}
+		if err := repository.TaskQueue.Create(entry); err != nil {
+			utils.Error("添加补偿执行到队列失败", "task_id", taskID, "error", err.Error())
+			return
+		}
+	}
+	utils.Info("补偿执行已加入队列", "task_id", taskID, "runs", runs)
+	tq.cond.Signal()
+}
+
+// merge 将新的触发合并到已排队的条目，新触发优先级更高时提升该条目的优先级
+func (tq *TaskQueue) merge(entry *task.QueueEntry, trigger string, priority int) {
+	if priority <= entry.Priority {
+		utils.Info("任务已在队列中，合并本次触发", "task_id", entry.TaskID, "entry_id", entry.ID, "trigger", trigger, "queued_trigger", entry.Trigger)
+		return
+	}
+
+	if err := repository.TaskQueue.UpdateTrigger(entry.ID, trigger, priority); err != nil {
+		utils.Error("提升队列条目优先级失败", "entry_id", entry.ID, "error", err.Error())
+		return
+	}
+	utils.Info("任务已在队列中，按本次触发提升优先级", "task_id", entry.TaskID, "entry_id", entry.ID, "trigger", trigger, "queued_trigger", entry.Trigger, "priority", priority)
+}
+
+// IsIdle 检查执行队列是否空闲（没有正在执行或等待执行的任务）
+func (tq *TaskQueue) IsIdle() bool {
+	return !tq.IsExecutorRunning() && tq.GetQueueLength() == 0
+}
+
+// executor 任务执行器，逐个取出队列中的条目执行，执行完成后再取下一个
 func (tq *TaskQueue) executor() {
 	utils.Info("任务执行器已启动，等待任务...")
 
 	for {
-		var taskID uint
-		var hasTask bool
-
 		// 获取任务
 		tq.mutex.Lock()
-		queueLen := len(tq.queue)
-
-		utils.Info("执行器检查队列", "queue_length", queueLen, "running_state", tq.running)
+		entry, err := repository.TaskQueue.NextPending()
+		if err != nil {
+			tq.mutex.Unlock()
+			utils.Error("获取待执行任务失败，稍后重试", "error", err.Error())
+			time.Sleep(5 * time.Second)
+			continue
+		}
 
 		// 等待队列中有任务
-		if queueLen == 0 {
+		if entry == nil {
 			utils.Info("队列为空，执行器进入等待状态")
-
-			// 使用条件变量等待任务或关闭信号
 			waitStart := time.Now()
 
-			// 创建一个通道，用于监听关闭信号
-			shutdownCh := tq.shutdown
-
 			// 在条件变量等待期间已经持有锁，Wait会释放锁并在返回前重新获取锁
 			tq.cond.Wait()
-
-			waitDuration := time.Since(waitStart)
-			utils.Info("执行器收到信号，退出等待状态", "wait_duration", waitDuration.String(), "queue_length", len(tq.queue))
+			utils.Info("执行器收到信号，退出等待状态", "wait_duration", time.Since(waitStart).String())
 
 			// 检查是否收到关闭信号 (需要在重新获取锁之后检查，避免竞争)
 			select {
-			case <-shutdownCh:
-				// 收到关闭信号，释放锁并退出
+			case <-tq.shutdown:
 				utils.Info("执行器收到关闭信号，退出执行")
 				tq.mutex.Unlock()
 				return
 			default:
-				// 没有关闭信号，继续处理
 			}
+			tq.mutex.Unlock()
+			continue
 		}
 
-		// 取出队列头部的任务
-		if len(tq.queue) > 0 {
-			taskID = tq.queue[0]
-			tq.queue = tq.queue[1:]
-			tq.running = true
-			hasTask = true
+		if err := repository.TaskQueue.MarkRunning(entry.ID, time.Now()); err != nil {
+			utils.Error("更新队列条目状态失败", "entry_id", entry.ID, "error", err.Error())
 		}
+		tq.running = true
 		tq.mutex.Unlock()
 
-		// 如果有任务，则执行
-		if hasTask {
-			// 创建一个单独的goroutine来执行任务，避免阻塞队列处理
-			go func(id uint) {
-				// 执行任务（不在锁内执行，避免阻塞其他操作）
-				utils.Info("开始执行任务", "task_id", id)
-
-				// 记录开始时间
-				startTime := time.Now()
-
-				// 执行任务
-				_, err := Task.ExecuteStrmGeneration(id)
-
-				// 计算持续时间（秒）
-				endTime := time.Now()
-				durationSeconds := int64(endTime.Sub(startTime).Seconds())
-
-				// 获取最新的任务日志，更新持续时间
-				taskLogs, total, logErr := repository.TaskLog.GetLatestByTaskID(id, 1)
-				if logErr == nil && total > 0 && len(taskLogs) > 0 {
-					latestLog := taskLogs[0]
-
-					// 更新持续时间
-					updateData := map[string]interface{}{
-						"duration": durationSeconds,
-					}
-
-					if updateErr := repository.TaskLog.UpdatePartial(latestLog.ID, updateData); updateErr != nil {
-						utils.Error("更新任务日志持续时间失败", "task_id", id, "log_id", latestLog.ID, "error", updateErr.Error())
-					} else {
-						utils.Info("更新任务日志持续时间", "task_id", id, "duration", durationSeconds)
-					}
-				}
-
-				// 任务已经在ExecuteStrmGeneration方法中设置和重置了运行状态
-
-				// 记录执行结果
-				if err != nil {
-					utils.Error("任务执行失败", "task_id", id, "error", err.Error(), "duration", durationSeconds)
-				} else {
-					utils.Info("任务执行成功", "task_id", id, "duration", durationSeconds)
-				}
-
-				// 更新状态
-				tq.mutex.Lock()
-				tq.running = false
-				tq.mutex.Unlock()
-			}(taskID)
+		tq.execute(entry)
+
+		if err := repository.TaskQueue.Delete(entry.ID); err != nil {
+			utils.Error("移除已完成的队列条目失败", "entry_id", entry.ID, "error", err.Error())
+		}
 
-			// 短暂休息，避免CPU占用过高和立即处理下一个任务
-			time.Sleep(100 * time.Millisecond)
+		tq.mutex.Lock()
+		tq.running = false
+		tq.mutex.Unlock()
+
+		select {
+		case <-tq.shutdown:
+			utils.Info("执行器收到关闭信号，退出执行")
+			return
+		default:
 		}
 	}
 }
 
-// IsTaskInQueue 检查任务是否在队列中
+// execute 执行队列条目并记录执行耗时
+func (tq *TaskQueue) execute(entry *task.QueueEntry) {
+	switch entry.Kind {
+	case task.QueueKindFile:
+		tq.executeFileEvent(entry)
+		return
+	case task.QueueKindRetry:
+		if err := TaskLogServiceInstance.executeRetry(entry.TaskLogID); err != nil {
+			utils.Error("重试失败文件失败", "task_id", entry.TaskID, "task_log_id", entry.TaskLogID, "error", err.Error())
+		}
+		return
+	}
+
+	id := entry.TaskID
+	utils.Info("开始执行任务", "task_id", id, "sub_path", entry.SubPath, "trigger", entry.Trigger)
+
+	// 记录开始时间
+	startTime := time.Now()
+
+	// 执行任务
+	resp, err := Task.ExecuteScopedStrmGeneration(id, entry.SubPath, entry.Trigger)
+
+	// 计算持续时间（秒）
+	durationSeconds := int64(time.Since(startTime).Seconds())
+
+	// 更新本次执行的任务日志的持续时间
+	if resp != nil && resp.TaskLogID != 0 {
+		updateData := map[string]interface{}{
+			"duration": durationSeconds,
+		}
+
+		if updateErr := repository.TaskLog.UpdatePartial(resp.TaskLogID, updateData); updateErr != nil {
+			utils.Error("更新任务日志持续时间失败", "task_id", id, "log_id", resp.TaskLogID, "error", updateErr.Error())
+		} else {
+			utils.Info("更新任务日志持续时间", "task_id", id, "duration", durationSeconds)
+		}
+	}
+
+	// 记录执行结果
+	if err != nil {
+		utils.Error("任务执行失败", "task_id", id, "error", err.Error(), "duration", durationSeconds)
+	} else {
+		utils.Info("任务执行成功", "task_id", id, "duration", durationSeconds)
+	}
+}
+
+// executeFileEvent 处理文件变更通知中新增的单个文件或目录
+func (tq *TaskQueue) executeFileEvent(entry *task.QueueEntry) {
+	taskInfo, err := repository.Task.GetByID(entry.TaskID)
+	if err != nil {
+		utils.Error("获取任务信息失败", "task_id", entry.TaskID, "error", err.Error())
+		return
+	}
+	if taskInfo == nil || !taskInfo.Enabled {
+		utils.Info("任务不存在或已禁用，跳过文件变更处理", "task_id", entry.TaskID, "path", entry.SubPath)
+		return
+	}
+
+	event := &webhook.FileChangeEvent{Action: "create", IsDir: webhook.CustomBool(entry.IsDir), SourceFile: entry.SubPath}
+	if err := GetStrmGeneratorService().ProcessFileChangeEvent(taskInfo, event); err != nil {
+		utils.Error("处理文件变更失败", "task_id", entry.TaskID, "path", entry.SubPath, "error", err.Error())
+		return
+	}
+	utils.Info("文件变更处理完成", "task_id", entry.TaskID, "path", entry.SubPath)
+}
+
+// IsTaskInQueue 检查任务是否在队列中等待执行
 func (tq *TaskQueue) IsTaskInQueue(taskID uint) bool {
-	tq.mutex.Lock()
-	defer tq.mutex.Unlock()
+	exists, err := repository.TaskQueue.ExistsPendingByTaskID(taskID)
+	if err != nil {
+		utils.Error("检查任务是否在队列中失败", "task_id", taskID, "error", err.Error())
+		return false
+	}
+	return exists
+}
 
-	for _, id := range tq.queue {
-		if id == taskID {
+// HasPendingFullScan 检查任务是否有等待执行的完整扫描
+func (tq *TaskQueue) HasPendingFullScan(taskID uint) bool {
+	pending, err := repository.TaskQueue.ListPending()
+	if err != nil {
+		utils.Error("获取执行队列失败", "task_id", taskID, "error", err.Error())
+		return false
+	}
+	for _, entry := range pending {
+		if entry.TaskID == taskID && entry.Kind == task.QueueKindScan && entry.SubPath == "" {
 			return true
 		}
 	}
@@ -216,30 +480,129 @@ func (tq *TaskQueue) IsExecutorRunning() bool {
 	return tq.running
 }
 
-// GetQueueLength 获取队列长度
+// GetQueueLength 获取等待执行的队列长度
 func (tq *TaskQueue) GetQueueLength() int {
+	count, err := repository.TaskQueue.CountPending()
+	if err != nil {
+		utils.Error("获取队列长度失败", "error", err.Error())
+		return 0
+	}
+	return int(count)
+}
+
+// RemoveTaskFromQueue 从队列中移除指定任务（包括其局部扫描）
+func (tq *TaskQueue) RemoveTaskFromQueue(taskID uint) bool {
 	tq.mutex.Lock()
 	defer tq.mutex.Unlock()
-	return len(tq.queue)
+
+	removed, err := repository.TaskQueue.DeletePendingByTaskID(taskID)
+	if err != nil {
+		utils.Error("从队列中移除任务失败", "task_id", taskID, "error", err.Error())
+		return false
+	}
+	return removed > 0
 }
 
-// RemoveTaskFromQueue 从队列中移除指定任务
-func (tq *TaskQueue) RemoveTaskFromQueue(taskID uint) bool {
+// List 按执行顺序获取队列条目，正在执行的条目排在最前
+func (tq *TaskQueue) List() ([]taskResponse.TaskQueueItem, error) {
+	entries, err := repository.TaskQueue.List()
+	if err != nil {
+		return nil, fmt.Errorf("获取执行队列失败: %w", err)
+	}
+
+	items := make([]taskResponse.TaskQueueItem, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, taskResponse.TaskQueueItem{
+			ID:         entry.ID,
+			TaskID:     entry.TaskID,
+			TaskName:   digestTaskName(entry.TaskID),
+			Kind:       entry.Kind,
+			SubPath:    entry.SubPath,
+			TaskLogID:  entry.TaskLogID,
+			Trigger:    entry.Trigger,
+			Priority:   entry.Priority,
+			Status:     entry.Status,
+			EnqueuedAt: entry.CreatedAt,
+			StartedAt:  entry.StartedAt,
+		})
+	}
+	return items, nil
+}
+
+// Reorder 按给定顺序重新排列等待中的条目，未列出的条目保持原有顺序排在其后。
+// 队列先按优先级执行，被调整到高优先级条目之前的条目会提升到与其后条目相同的优先级，使手动调整的顺序生效
+func (tq *TaskQueue) Reorder(ids []uint) error {
 	tq.mutex.Lock()
 	defer tq.mutex.Unlock()
 
-	for i, id := range tq.queue {
-		if id == taskID {
-			// 移除任务
-			tq.queue = append(tq.queue[:i], tq.queue[i+1:]...)
-			return true
+	pending, err := repository.TaskQueue.ListPending()
+	if err != nil {
+		return fmt.Errorf("获取执行队列失败: %w", err)
+	}
+
+	pendingByID := make(map[uint]task.QueueEntry, len(pending))
+	for _, entry := range pending {
+		pendingByID[entry.ID] = entry
+	}
+
+	order := make([]task.QueueEntry, 0, len(pending))
+	listed := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		entry, ok := pendingByID[id]
+		if !ok {
+			return fmt.Errorf("队列条目 %d 不存在或已开始执行", id)
 		}
+		if listed[id] {
+			continue
+		}
+		listed[id] = true
+		order = append(order, entry)
 	}
-	return false
+	for _, entry := range pending {
+		if !listed[entry.ID] {
+			order = append(order, entry)
+		}
+	}
+
+	// 从后向前保证优先级不低于其后的条目，按优先级排序后仍是给定的顺序
+	for i := len(order) - 2; i >= 0; i-- {
+		if order[i].Priority < order[i+1].Priority {
+			order[i].Priority = order[i+1].Priority
+		}
+	}
+
+	if err := repository.TaskQueue.UpdateOrder(order); err != nil {
+		return fmt.Errorf("调整队列顺序失败: %w", err)
+	}
+	return nil
+}
+
+// Remove 移除等待中的队列条目
+func (tq *TaskQueue) Remove(id uint) error {
+	tq.mutex.Lock()
+	defer tq.mutex.Unlock()
+
+	entry, err := repository.TaskQueue.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("获取队列条目失败: %w", err)
+	}
+	if entry == nil {
+		return errors.New("队列条目不存在")
+	}
+	if entry.Status == task.QueueStatusRunning {
+		return errors.New("任务正在执行，请使用取消任务")
+	}
+
+	if err := repository.TaskQueue.Delete(id); err != nil {
+		return fmt.Errorf("移除队列条目失败: %w", err)
+	}
+	utils.Info("已从执行队列移除条目", "entry_id", id, "task_id", entry.TaskID, "sub_path", entry.SubPath)
+	return nil
 }
 
 // Shutdown 关闭任务队列
 func (tq *TaskQueue) Shutdown() {
 	close(tq.shutdown)
+	tq.cond.Broadcast()
 	utils.Info("任务队列关闭")
 }
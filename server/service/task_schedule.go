@@ -0,0 +1,251 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/config"
+	"github.com/MccRay-s/alist2strm/model/task"
+	taskRequest "github.com/MccRay-s/alist2strm/model/task/request"
+	taskResponse "github.com/MccRay-s/alist2strm/model/task/response"
+	"github.com/MccRay-s/alist2strm/utils"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// minScheduleInterval 间隔调度的最小间隔，避免频繁访问源站
+	minScheduleInterval = time.Minute
+	// defaultPreviewCount 预览默认返回的触发次数
+	defaultPreviewCount = 5
+	// maxPreviewCount 预览最多返回的触发次数
+	maxPreviewCount = 50
+)
+
+// cronParser 支持可选秒字段（6 字段）、CRON_TZ 前缀和 @every 等描述符
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// normalizeCronSpec 规范化调度表达式：支持 "every 6h" 形式的间隔调度，并在设置了时区时添加 CRON_TZ 前缀
+func normalizeCronSpec(spec, timezone string) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return "", errors.New("调度表达式不能为空")
+	}
+
+	lower := strings.ToLower(spec)
+	if strings.HasPrefix(lower, "every ") {
+		spec = "@every " + strings.TrimSpace(spec[len("every "):])
+		lower = strings.ToLower(spec)
+	}
+	if strings.HasPrefix(lower, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(spec[len("@every "):]))
+		if err != nil {
+			return "", fmt.Errorf("间隔格式错误，应为 every 6h、every 30m 等形式: %w", err)
+		}
+		if interval < minScheduleInterval {
+			return "", fmt.Errorf("执行间隔不能小于 %s", minScheduleInterval)
+		}
+	}
+
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		return spec, nil
+	}
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return "", fmt.Errorf("时区 '%s' 无效: %w", timezone, err)
+		}
+		spec = "CRON_TZ=" + timezone + " " + spec
+	}
+	return spec, nil
+}
+
+// parseCronSpec 解析调度表达式
+func parseCronSpec(spec, timezone string) (cron.Schedule, error) {
+	normalized, err := normalizeCronSpec(spec, timezone)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := cronParser.Parse(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("调度表达式 '%s' 无效: %w", spec, err)
+	}
+	return schedule, nil
+}
+
+// parseBlackoutWindows 解析禁止执行时段，格式为 HH:MM-HH:MM，多个时段用逗号分隔
+func parseBlackoutWindows(value string) ([]utils.ClockWindow, error) {
+	var windows []utils.ClockWindow
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.Split(part, "-")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("禁止执行时段 '%s' 格式错误，应为 HH:MM-HH:MM", part)
+		}
+		window, err := utils.ParseClockWindow(bounds[0], bounds[1])
+		if err != nil {
+			return nil, fmt.Errorf("禁止执行时段 '%s' 格式错误: %w", part, err)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// blackoutEnd 若 t 处于任一禁止执行时段内，返回可以执行的最早时间（连续或重叠的时段会合并计算）
+func blackoutEnd(windows []utils.ClockWindow, t time.Time) (time.Time, bool) {
+	end := t
+	deferred := false
+	for i := 0; i <= len(windows); i++ {
+		moved := false
+		for _, w := range windows {
+			if w.Contains(end) {
+				end = w.EndAfter(end)
+				moved = true
+				deferred = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+	return end, deferred
+}
+
+// schedulerConfig 获取定时调度全局配置
+func schedulerConfig() config.SchedulerConfig {
+	if config.GlobalConfig == nil {
+		return config.SchedulerConfig{}
+	}
+	return config.GlobalConfig.Scheduler
+}
+
+// specTimezone 获取调度使用的时区名称：表达式中的 CRON_TZ 前缀优先，其次为设置的时区
+func specTimezone(spec, timezone string) string {
+	spec = strings.TrimSpace(spec)
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if strings.HasPrefix(spec, prefix) {
+			if i := strings.IndexByte(spec, ' '); i > len(prefix) {
+				return spec[len(prefix):i]
+			}
+		}
+	}
+	return timezone
+}
+
+// scheduleLocation 获取调度使用的时区：任务时区优先，其次为全局时区，最后为服务器本地时区
+func scheduleLocation(timezone string) *time.Location {
+	if timezone == "" {
+		timezone = schedulerConfig().Timezone
+	}
+	if timezone != "" {
+		if loc, err := time.LoadLocation(timezone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// taskBlackoutWindows 获取任务生效的禁止执行时段（全局时段与任务时段的并集）
+func taskBlackoutWindows(value string) ([]utils.ClockWindow, error) {
+	global, err := parseBlackoutWindows(schedulerConfig().BlackoutWindows)
+	if err != nil {
+		return nil, fmt.Errorf("全局%w", err)
+	}
+	own, err := parseBlackoutWindows(value)
+	if err != nil {
+		return nil, err
+	}
+	return append(global, own...), nil
+}
+
+// taskJitter 获取任务触发时随机延迟的最大值
+func taskJitter(t *task.Task) time.Duration {
+	seconds := t.JitterSeconds
+	if seconds <= 0 {
+		seconds = schedulerConfig().JitterSeconds
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// validateSchedule 校验任务的调度设置
+func validateSchedule(t *task.Task) error {
+	if t.Timezone != "" {
+		if _, err := time.LoadLocation(t.Timezone); err != nil {
+			return fmt.Errorf("时区 '%s' 无效", t.Timezone)
+		}
+	}
+	if t.JitterSeconds < 0 {
+		return errors.New("随机延迟不能小于 0")
+	}
+	if t.MisfirePolicy != "" && !task.IsValidMisfirePolicy(t.MisfirePolicy) {
+		return fmt.Errorf("无效的补偿策略: %s", t.MisfirePolicy)
+	}
+	if t.MisfirePolicy == task.MisfirePolicyLimit && (t.MisfireLimit < 1 || t.MisfireLimit > task.MaxMisfireLimit) {
+		return fmt.Errorf("补偿执行次数应在 1 到 %d 之间", task.MaxMisfireLimit)
+	}
+	if _, err := parseBlackoutWindows(t.BlackoutWindows); err != nil {
+		return err
+	}
+	if t.Cron != "" {
+		if _, err := parseCronSpec(t.Cron, t.Timezone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PreviewSchedule 预览调度表达式接下来的触发时间，处于禁止执行时段内的触发会标明推迟后的执行时间
+func (s *TaskScheduler) PreviewSchedule(req *taskRequest.TaskSchedulePreviewReq) (*taskResponse.TaskSchedulePreviewResp, error) {
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = schedulerConfig().Timezone
+	}
+	schedule, err := parseCronSpec(req.Cron, timezone)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := taskBlackoutWindows(req.BlackoutWindows)
+	if err != nil {
+		return nil, err
+	}
+
+	count := req.Count
+	if count <= 0 {
+		count = defaultPreviewCount
+	}
+	if count > maxPreviewCount {
+		count = maxPreviewCount
+	}
+
+	loc := scheduleLocation(specTimezone(req.Cron, timezone))
+	jitter := req.JitterSeconds
+	if jitter <= 0 {
+		jitter = schedulerConfig().JitterSeconds
+	}
+	resp := &taskResponse.TaskSchedulePreviewResp{
+		Timezone:      loc.String(),
+		JitterSeconds: jitter,
+		Items:         make([]taskResponse.TaskScheduleFireTime, 0, count),
+	}
+
+	next := time.Now().In(loc)
+	for i := 0; i < count; i++ {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+		next = next.In(loc)
+		item := taskResponse.TaskScheduleFireTime{FireTime: next}
+		if end, deferred := blackoutEnd(windows, next); deferred {
+			item.DeferredTo = &end
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	return resp, nil
+}
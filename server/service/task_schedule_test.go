@@ -0,0 +1,226 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/config"
+	"github.com/MccRay-s/alist2strm/model/task"
+	"github.com/MccRay-s/alist2strm/utils"
+)
+
+// withSchedulerConfig 在测试期间替换全局调度配置
+func withSchedulerConfig(t *testing.T, scheduler config.SchedulerConfig) {
+	t.Helper()
+	previous := config.GlobalConfig
+	config.GlobalConfig = &config.AppConfig{Scheduler: scheduler}
+	t.Cleanup(func() { config.GlobalConfig = previous })
+}
+
+func TestNormalizeCronSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		timezone string
+		want     string
+		wantErr  bool
+	}{
+		{name: "标准表达式", spec: "0 3 * * *", want: "0 3 * * *"},
+		{name: "去除首尾空白", spec: "  0 3 * * *  ", want: "0 3 * * *"},
+		{name: "every 间隔", spec: "every 6h", want: "@every 6h"},
+		{name: "every 不区分大小写", spec: "Every 30m", want: "@every 30m"},
+		{name: "@every 间隔", spec: "@every 1h30m", want: "@every 1h30m"},
+		{name: "添加时区前缀", spec: "0 3 * * *", timezone: "Asia/Shanghai", want: "CRON_TZ=Asia/Shanghai 0 3 * * *"},
+		{name: "间隔调度也添加时区前缀", spec: "every 2h", timezone: "UTC", want: "CRON_TZ=UTC @every 2h"},
+		{name: "保留已有的 CRON_TZ", spec: "CRON_TZ=UTC 0 3 * * *", timezone: "Asia/Shanghai", want: "CRON_TZ=UTC 0 3 * * *"},
+		{name: "保留已有的 TZ", spec: "TZ=UTC 0 3 * * *", want: "TZ=UTC 0 3 * * *"},
+		{name: "空表达式", spec: "   ", wantErr: true},
+		{name: "间隔过短", spec: "every 30s", wantErr: true},
+		{name: "间隔格式错误", spec: "every six hours", wantErr: true},
+		{name: "时区无效", spec: "0 3 * * *", timezone: "Mars/Olympus", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeCronSpec(tt.spec, tt.timezone)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeCronSpec(%q, %q) error = %v, wantErr %v", tt.spec, tt.timezone, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("normalizeCronSpec(%q, %q) = %q, want %q", tt.spec, tt.timezone, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCronSpec(t *testing.T) {
+	from := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		spec     string
+		timezone string
+		want     time.Time
+		wantErr  bool
+	}{
+		{name: "五字段", spec: "0 3 * * *", timezone: "UTC", want: time.Date(2024, 3, 11, 3, 0, 0, 0, time.UTC)},
+		{name: "六字段含秒", spec: "30 0 3 * * *", timezone: "UTC", want: time.Date(2024, 3, 11, 3, 0, 30, 0, time.UTC)},
+		{name: "间隔调度", spec: "every 6h", want: from.Add(6 * time.Hour)},
+		{name: "按时区计算", spec: "0 3 * * *", timezone: "Asia/Shanghai", want: time.Date(2024, 3, 10, 19, 0, 0, 0, time.UTC)},
+		{name: "字段数错误", spec: "0 3 * *", wantErr: true},
+		{name: "字段值无效", spec: "0 25 * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCronSpec(tt.spec, tt.timezone)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCronSpec(%q, %q) error = %v, wantErr %v", tt.spec, tt.timezone, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecTimezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		timezone string
+		want     string
+	}{
+		{name: "使用设置的时区", spec: "0 3 * * *", timezone: "UTC", want: "UTC"},
+		{name: "CRON_TZ 优先", spec: "CRON_TZ=Asia/Tokyo 0 3 * * *", timezone: "UTC", want: "Asia/Tokyo"},
+		{name: "TZ 优先", spec: "TZ=Europe/Berlin 0 3 * * *", want: "Europe/Berlin"},
+		{name: "未设置时区", spec: "0 3 * * *", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := specTimezone(tt.spec, tt.timezone); got != tt.want {
+				t.Errorf("specTimezone(%q, %q) = %q, want %q", tt.spec, tt.timezone, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskJitter(t *testing.T) {
+	tests := []struct {
+		name   string
+		own    int
+		global int
+		want   time.Duration
+	}{
+		{name: "任务设置优先", own: 30, global: 120, want: 30 * time.Second},
+		{name: "使用全局设置", own: 0, global: 120, want: 2 * time.Minute},
+		{name: "负数视为未设置", own: -5, global: 10, want: 10 * time.Second},
+		{name: "均未设置", own: 0, global: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withSchedulerConfig(t, config.SchedulerConfig{JitterSeconds: tt.global})
+			if got := taskJitter(&task.Task{JitterSeconds: tt.own}); got != tt.want {
+				t.Errorf("taskJitter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBlackoutWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []utils.ClockWindow
+		wantErr bool
+	}{
+		{name: "为空", value: "", want: nil},
+		{name: "单个时段", value: "19:00-23:00", want: []utils.ClockWindow{{Start: 19 * 60, End: 23 * 60}}},
+		{name: "跨越午夜", value: "23:00-07:00", want: []utils.ClockWindow{{Start: 23 * 60, End: 7 * 60}}},
+		{name: "结束于 24:00", value: "22:00-24:00", want: []utils.ClockWindow{{Start: 22 * 60, End: 0}}},
+		{
+			name:  "多个时段并忽略空项",
+			value: " 12:00-13:00 ,, 23:30-06:00 ",
+			want:  []utils.ClockWindow{{Start: 12 * 60, End: 13 * 60}, {Start: 23*60 + 30, End: 6 * 60}},
+		},
+		{name: "缺少结束时间", value: "19:00", wantErr: true},
+		{name: "多余的分隔符", value: "19:00-20:00-21:00", wantErr: true},
+		{name: "开始与结束相同", value: "08:00-08:00", wantErr: true},
+		{name: "全天时段", value: "00:00-24:00", wantErr: true},
+		{name: "时间无效", value: "25:00-01:00", wantErr: true},
+		{name: "任一时段无效", value: "19:00-23:00,bad", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBlackoutWindows(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBlackoutWindows(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseBlackoutWindows(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("parseBlackoutWindows(%q)[%d] = %+v, want %+v", tt.value, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBlackoutEnd(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		windows  string
+		now      time.Time
+		want     time.Time
+		deferred bool
+	}{
+		{name: "没有时段", windows: "", now: at(10, 20, 0), want: at(10, 20, 0)},
+		{name: "时段外", windows: "19:00-23:00", now: at(10, 18, 59), want: at(10, 18, 59)},
+		{name: "时段内", windows: "19:00-23:00", now: at(10, 19, 0), want: at(10, 23, 0), deferred: true},
+		{name: "结束时间不包含", windows: "19:00-23:00", now: at(10, 23, 0), want: at(10, 23, 0)},
+		{name: "跨越午夜-午夜前", windows: "23:00-07:00", now: at(10, 23, 30), want: at(11, 7, 0), deferred: true},
+		{name: "跨越午夜-午夜后", windows: "23:00-07:00", now: at(11, 3, 0), want: at(11, 7, 0), deferred: true},
+		{name: "结束于 24:00", windows: "22:00-24:00", now: at(10, 23, 15), want: at(11, 0, 0), deferred: true},
+		{name: "相接的时段合并", windows: "22:00-24:00,00:00-02:00", now: at(10, 22, 30), want: at(11, 2, 0), deferred: true},
+		{name: "重叠的时段合并", windows: "23:00-07:00,06:30-08:00", now: at(10, 23, 30), want: at(11, 8, 0), deferred: true},
+		{name: "顺序无关", windows: "06:30-08:00,23:00-07:00", now: at(10, 23, 30), want: at(11, 8, 0), deferred: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows, err := parseBlackoutWindows(tt.windows)
+			if err != nil {
+				t.Fatalf("parseBlackoutWindows(%q) error = %v", tt.windows, err)
+			}
+			got, deferred := blackoutEnd(windows, tt.now)
+			if deferred != tt.deferred || !got.Equal(tt.want) {
+				t.Errorf("blackoutEnd(%q, %s) = (%s, %v), want (%s, %v)", tt.windows, tt.now, got, deferred, tt.want, tt.deferred)
+			}
+		})
+	}
+}
+
+func TestTaskBlackoutWindows(t *testing.T) {
+	withSchedulerConfig(t, config.SchedulerConfig{BlackoutWindows: "01:00-02:00"})
+
+	windows, err := taskBlackoutWindows("19:00-23:00")
+	if err != nil {
+		t.Fatalf("taskBlackoutWindows() error = %v", err)
+	}
+	want := []utils.ClockWindow{{Start: 60, End: 120}, {Start: 19 * 60, End: 23 * 60}}
+	if len(windows) != len(want) || windows[0] != want[0] || windows[1] != want[1] {
+		t.Errorf("taskBlackoutWindows() = %+v, want %+v", windows, want)
+	}
+
+	withSchedulerConfig(t, config.SchedulerConfig{BlackoutWindows: "bad"})
+	if _, err := taskBlackoutWindows(""); err == nil {
+		t.Error("taskBlackoutWindows() 在全局时段无效时应返回错误")
+	}
+}
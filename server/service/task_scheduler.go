@@ -1,9 +1,11 @@
 package service
 
 import (
+	"math/rand/v2"
 	"sync"
 	"time"
 
+	"github.com/MccRay-s/alist2strm/config"
 	"github.com/MccRay-s/alist2strm/model/task"
 	"github.com/MccRay-s/alist2strm/repository"
 	"github.com/MccRay-s/alist2strm/utils"
@@ -14,6 +16,7 @@ import (
 type TaskScheduler struct {
 	cron      *cron.Cron
 	entryIDs  map[uint]cron.EntryID
+	deferred  map[uint]*time.Timer // 因禁止执行时段推迟的执行
 	taskMutex sync.RWMutex
 }
 
@@ -27,9 +30,10 @@ var (
 func GetTaskScheduler() *TaskScheduler {
 	schedulerOnce.Do(func() {
 		scheduler = &TaskScheduler{
-			// 使用标准的 5 字段 Cron 格式 (分、时、日、月、周)，而不是 6 字段格式 (秒、分、时、日、月、周)
-			cron:      cron.New(),
+			// 支持标准的 5 字段 Cron 格式 (分、时、日、月、周) 和带秒的 6 字段格式，以及 CRON_TZ 前缀和 @every 间隔
+			cron:      cron.New(cron.WithParser(cronParser), cron.WithLocation(scheduleLocation(""))),
 			entryIDs:  make(map[uint]cron.EntryID),
+			deferred:  make(map[uint]*time.Timer),
 			taskMutex: sync.RWMutex{},
 		}
 	})
@@ -61,6 +65,9 @@ func (s *TaskScheduler) Start() int {
 
 	utils.Info("定时任务调度器启动成功", "task_count", newTaskCount, "task_ids", taskIDs)
 
+	// 补偿停机期间错过的定时执行
+	s.CatchUpMissedRuns()
+
 	// 输出所有任务的下一次执行时间
 	for _, id := range taskIDs {
 		nextTime := s.GetNextRunTime(id)
@@ -105,6 +112,12 @@ func (s *TaskScheduler) AddTask(t *task.Task) error {
 		return nil
 	}
 
+	spec, err := normalizeCronSpec(t.Cron, t.Timezone)
+	if err != nil {
+		utils.Error("任务调度表达式无效", "task_id", t.ID, "cron", t.Cron, "timezone", t.Timezone, "error", err.Error())
+		return err
+	}
+
 	s.taskMutex.Lock()
 	defer s.taskMutex.Unlock()
 
@@ -114,51 +127,20 @@ func (s *TaskScheduler) AddTask(t *task.Task) error {
 	}
 
 	// 创建任务执行函数
+	taskID, taskName := t.ID, t.Name
 	taskFunc := func() {
 		now := time.Now()
-		utils.Info("调度器触发任务执行", "task_id", t.ID, "name", t.Name, "time", now.Format("2006-01-02 15:04:05"))
-
-		// 使用一个单独的goroutine执行数据库操作，避免阻塞调度器
-		go func() {
-			// 检查任务当前状态
-			currentTask, err := repository.Task.GetByID(t.ID)
-			if err != nil {
-				utils.Error("获取任务信息失败", "task_id", t.ID, "error", err.Error())
-				return
-			}
-
-			// 如果任务已禁用或正在运行，则跳过本次执行
-			if !currentTask.Enabled {
-				utils.Info("任务已禁用，跳过本次执行", "task_id", t.ID, "name", currentTask.Name)
-				return
-			}
-
-			if currentTask.Running {
-				utils.Info("任务正在运行中，跳过本次执行", "task_id", t.ID, "name", currentTask.Name)
-				return
-			}
-
-			// 如果任务已在队列中，也跳过
-			taskQueue := GetTaskQueue()
-			if taskQueue.IsTaskInQueue(t.ID) {
-				utils.Info("任务已在队列中，跳过本次调度", "task_id", t.ID, "name", currentTask.Name)
-				return
-			}
+		utils.Info("调度器触发任务执行", "task_id", taskID, "name", taskName, "time", now.Format("2006-01-02 15:04:05"))
 
-			// 将任务添加到队列
-			utils.Info("定时任务触发，添加到执行队列", "task_id", t.ID, "name", currentTask.Name, "queue_length", taskQueue.GetQueueLength())
-			taskQueue.AddTask(t.ID)
-
-			// 再次检查队列状态
-			utils.Info("添加到队列后的状态", "task_id", t.ID, "in_queue", taskQueue.IsTaskInQueue(t.ID), "executor_running", taskQueue.IsExecutorRunning(), "queue_length", taskQueue.GetQueueLength())
-		}()
+		// 使用一个单独的goroutine执行数据库操作和随机延迟，避免阻塞调度器
+		go s.fire(taskID)
 	}
 
 	// 添加到cron调度器
-	utils.Info("正在添加任务到调度器", "task_id", t.ID, "cron", t.Cron, "task_name", t.Name)
-	entryID, err := s.cron.AddFunc(t.Cron, taskFunc)
+	utils.Info("正在添加任务到调度器", "task_id", t.ID, "cron", spec, "task_name", t.Name)
+	entryID, err := s.cron.AddFunc(spec, taskFunc)
 	if err != nil {
-		utils.Error("添加任务到调度器失败", "task_id", t.ID, "cron", t.Cron, "error", err.Error())
+		utils.Error("添加任务到调度器失败", "task_id", t.ID, "cron", spec, "error", err.Error())
 		return err
 	}
 
@@ -167,10 +149,114 @@ func (s *TaskScheduler) AddTask(t *task.Task) error {
 
 	// 获取并记录下次执行时间，帮助诊断
 	entry := s.cron.Entry(entryID)
-	utils.Info("任务已成功添加到调度器", "task_id", t.ID, "cron", t.Cron, "next_run", entry.Next.Format("2006-01-02 15:04:05"))
+	utils.Info("任务已成功添加到调度器", "task_id", t.ID, "cron", spec, "next_run", entry.Next.Format("2006-01-02 15:04:05"))
 	return nil
 }
 
+// fire 处理一次定时触发：按设置随机延迟，处于禁止执行时段时推迟到时段结束后，否则加入执行队列
+func (s *TaskScheduler) fire(taskID uint) {
+	currentTask, err := repository.Task.GetByID(taskID)
+	if err != nil {
+		utils.Error("获取任务信息失败", "task_id", taskID, "error", err.Error())
+		return
+	}
+	if currentTask == nil {
+		utils.Warn("任务不存在，跳过本次执行", "task_id", taskID)
+		return
+	}
+
+	// 随机延迟，避免多个任务在同一时刻访问源站
+	if jitter := taskJitter(currentTask); jitter > 0 {
+		delay := rand.N(jitter)
+		utils.Info("定时任务随机延迟执行", "task_id", taskID, "name", currentTask.Name, "delay", delay.Round(time.Second).String())
+		time.Sleep(delay)
+	}
+
+	// 禁止执行时段内推迟执行
+	windows, err := taskBlackoutWindows(currentTask.BlackoutWindows)
+	if err != nil {
+		utils.Warn("禁止执行时段设置无效，忽略", "task_id", taskID, "error", err.Error())
+	}
+	now := time.Now().In(scheduleLocation(specTimezone(currentTask.Cron, currentTask.Timezone)))
+	if end, deferred := blackoutEnd(windows, now); deferred {
+		s.deferRun(taskID, currentTask.Name, end, func() { s.enqueueScheduled(taskID) })
+		return
+	}
+
+	s.enqueueScheduled(taskID)
+}
+
+// deferRun 将处于禁止执行时段的定时执行推迟到时段结束后再执行 run，同一任务只保留一次推迟的执行
+func (s *TaskScheduler) deferRun(taskID uint, name string, at time.Time, run func()) {
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	if _, exists := s.deferred[taskID]; exists {
+		utils.Info("任务已有推迟的执行，合并本次触发", "task_id", taskID, "name", name)
+		return
+	}
+	if _, exists := s.entryIDs[taskID]; !exists {
+		return
+	}
+
+	s.deferred[taskID] = time.AfterFunc(time.Until(at), func() {
+		s.taskMutex.Lock()
+		delete(s.deferred, taskID)
+		s.taskMutex.Unlock()
+
+		utils.Info("禁止执行时段结束，执行推迟的定时任务", "task_id", taskID, "name", name)
+		run()
+	})
+	utils.Info("处于禁止执行时段，定时任务推迟执行", "task_id", taskID, "name", name, "run_at", at.Format("2006-01-02 15:04:05"))
+}
+
+// enqueueScheduled 检查任务状态后将定时触发加入执行队列
+func (s *TaskScheduler) enqueueScheduled(taskID uint) {
+	// 检查任务当前状态
+	currentTask, err := repository.Task.GetByID(taskID)
+	if err != nil {
+		utils.Error("获取任务信息失败", "task_id", taskID, "error", err.Error())
+		return
+	}
+	if currentTask == nil {
+		utils.Warn("任务不存在，跳过本次执行", "task_id", taskID)
+		return
+	}
+
+	// 如果任务已禁用或正在运行，则跳过本次执行
+	if !currentTask.Enabled {
+		utils.Info("任务已禁用，跳过本次执行", "task_id", taskID, "name", currentTask.Name)
+		return
+	}
+
+	if currentTask.Running {
+		utils.Info("任务正在运行中，跳过本次执行", "task_id", taskID, "name", currentTask.Name)
+		return
+	}
+
+	// 源站异常时按配置跳过，避免产生一条意义不明的失败记录
+	if config.GlobalConfig != nil && config.GlobalConfig.HealthCheck.SkipUnhealthyTasks {
+		if healthy, message := Health.IsSourceHealthy(currentTask.ConfigType); !healthy {
+			utils.Warn("任务源站异常，跳过本次执行", "task_id", taskID, "name", currentTask.Name, "config_type", currentTask.ConfigType, "error", message)
+			return
+		}
+	}
+
+	// 设置了仅在空闲时执行的任务，队列中有其他任务时跳过
+	taskQueue := GetTaskQueue()
+	if currentTask.RunOnlyIfIdle && !taskQueue.IsIdle() {
+		utils.Info("执行队列繁忙，跳过本次调度", "task_id", taskID, "name", currentTask.Name, "queue_length", taskQueue.GetQueueLength())
+		return
+	}
+
+	// 将任务添加到队列，已在队列中时合并到已排队的执行
+	utils.Info("定时任务触发，添加到执行队列", "task_id", taskID, "name", currentTask.Name, "queue_length", taskQueue.GetQueueLength())
+	taskQueue.AddTask(taskID, task.TriggerCron)
+
+	// 再次检查队列状态
+	utils.Info("添加到队列后的状态", "task_id", taskID, "in_queue", taskQueue.IsTaskInQueue(taskID), "executor_running", taskQueue.IsExecutorRunning(), "queue_length", taskQueue.GetQueueLength())
+}
+
 // RemoveTask 从调度器移除任务
 func (s *TaskScheduler) RemoveTask(taskID uint) {
 	s.taskMutex.Lock()
@@ -185,6 +271,10 @@ func (s *TaskScheduler) RemoveTaskNoLock(taskID uint) {
 		delete(s.entryIDs, taskID)
 		utils.Info("任务已从调度器移除", "task_id", taskID)
 	}
+	if timer, exists := s.deferred[taskID]; exists {
+		timer.Stop()
+		delete(s.deferred, taskID)
+	}
 }
 
 // UpdateTask 更新任务调度
@@ -76,6 +76,17 @@ func (s *TaskService) Create(req *taskRequest.TaskCreateReq) error {
 		DownloadSubtitle:   req.DownloadSubtitle,
 		MetadataExtensions: req.MetadataExtensions,
 		SubtitleExtensions: req.SubtitleExtensions,
+		NotifyEvents:       req.NotifyEvents,
+		NotifyChannels:     req.NotifyChannels,
+		NotifyMinGenerated: req.NotifyMinGenerated,
+		RunOnlyIfIdle:      req.RunOnlyIfIdle,
+		Timezone:           req.Timezone,
+		JitterSeconds:      req.JitterSeconds,
+		BlackoutWindows:    req.BlackoutWindows,
+		MisfirePolicy:      req.MisfirePolicy,
+		MisfireLimit:       req.MisfireLimit,
+		PostActions:        req.PostActions,
+		Hooks:              req.Hooks,
 	}
 
 	// 设置默认值
@@ -85,6 +96,24 @@ func (s *TaskService) Create(req *taskRequest.TaskCreateReq) error {
 	if newTask.SubtitleExtensions == "" {
 		newTask.SubtitleExtensions = "srt,ass,ssa"
 	}
+	if newTask.NotifyEvents == "" {
+		newTask.NotifyEvents = task.DefaultNotifyEvents
+	}
+	if newTask.MisfirePolicy == "" {
+		newTask.MisfirePolicy = task.MisfirePolicySkip
+	}
+	if err := validateNotifyRule(newTask); err != nil {
+		return err
+	}
+	if err := validateSchedule(newTask); err != nil {
+		return err
+	}
+	if err := validatePostActions(newTask); err != nil {
+		return err
+	}
+	if err := validateHooks(newTask); err != nil {
+		return err
+	}
 
 	err := repository.Task.Create(newTask)
 	if err != nil {
@@ -132,6 +161,17 @@ func (s *TaskService) GetTaskInfo(req *taskRequest.TaskInfoReq) (*taskResponse.T
 		DownloadSubtitle:   task.DownloadSubtitle,
 		MetadataExtensions: task.MetadataExtensions,
 		SubtitleExtensions: task.SubtitleExtensions,
+		NotifyEvents:       task.NotifyEvents,
+		NotifyChannels:     task.NotifyChannels,
+		NotifyMinGenerated: task.NotifyMinGenerated,
+		RunOnlyIfIdle:      task.RunOnlyIfIdle,
+		Timezone:           task.Timezone,
+		JitterSeconds:      task.JitterSeconds,
+		BlackoutWindows:    task.BlackoutWindows,
+		MisfirePolicy:      task.MisfirePolicy,
+		MisfireLimit:       task.MisfireLimit,
+		PostActions:        task.PostActions,
+		Hooks:              task.Hooks,
 	}
 
 	return resp, nil
@@ -207,12 +247,69 @@ func (s *TaskService) UpdateTask(req *taskRequest.TaskUpdateReq) error {
 		task.SubtitleExtensions = req.SubtitleExtensions
 		hasUpdate = true
 	}
+	if req.NotifyEvents != "" {
+		task.NotifyEvents = req.NotifyEvents
+		hasUpdate = true
+	}
+	if req.NotifyChannels != nil {
+		task.NotifyChannels = *req.NotifyChannels
+		hasUpdate = true
+	}
+	if req.NotifyMinGenerated != nil {
+		task.NotifyMinGenerated = *req.NotifyMinGenerated
+		hasUpdate = true
+	}
+	if req.RunOnlyIfIdle != nil {
+		task.RunOnlyIfIdle = *req.RunOnlyIfIdle
+		hasUpdate = true
+	}
+	if req.Timezone != nil {
+		task.Timezone = *req.Timezone
+		hasUpdate = true
+	}
+	if req.JitterSeconds != nil {
+		task.JitterSeconds = *req.JitterSeconds
+		hasUpdate = true
+	}
+	if req.BlackoutWindows != nil {
+		task.BlackoutWindows = *req.BlackoutWindows
+		hasUpdate = true
+	}
+	if req.MisfirePolicy != "" {
+		task.MisfirePolicy = req.MisfirePolicy
+		hasUpdate = true
+	}
+	if req.MisfireLimit != nil {
+		task.MisfireLimit = *req.MisfireLimit
+		hasUpdate = true
+	}
+	if req.PostActions != nil {
+		task.PostActions = *req.PostActions
+		hasUpdate = true
+	}
+	if req.Hooks != nil {
+		task.Hooks = *req.Hooks
+		hasUpdate = true
+	}
 
 	// 如果没有任何更新，返回错误
 	if !hasUpdate {
 		return errors.New("请提供要更新的信息")
 	}
 
+	if err := validateNotifyRule(task); err != nil {
+		return err
+	}
+	if err := validateSchedule(task); err != nil {
+		return err
+	}
+	if err := validatePostActions(task); err != nil {
+		return err
+	}
+	if err := validateHooks(task); err != nil {
+		return err
+	}
+
 	err = repository.Task.Update(task)
 	if err != nil {
 		return err
@@ -253,6 +350,21 @@ func (s *TaskService) DeleteTask(id uint) error {
 		return err
 	}
 
+	// 清理任务的目标文件映射
+	if err := repository.TargetMapping.DeleteByTaskID(id); err != nil {
+		utils.Warn("清理任务目标映射失败", "task_id", id, "error", err.Error())
+	}
+
+	// 清理任务的失败文件记录
+	if err := repository.FileFailure.DeleteByTaskID(id); err != nil {
+		utils.Warn("清理任务失败文件记录失败", "task_id", id, "error", err.Error())
+	}
+
+	// 清理任务的运行日志
+	if err := repository.TaskLogEntry.DeleteByTaskID(id); err != nil {
+		utils.Warn("清理任务运行日志失败", "task_id", id, "error", err.Error())
+	}
+
 	// 从调度器中移除任务
 	scheduler := GetTaskScheduler()
 	scheduler.RemoveTask(id)
@@ -289,6 +401,17 @@ func (s *TaskService) GetTaskList(req *taskRequest.TaskListReq) (*taskResponse.T
 			DownloadSubtitle:   t.DownloadSubtitle,
 			MetadataExtensions: t.MetadataExtensions,
 			SubtitleExtensions: t.SubtitleExtensions,
+			NotifyEvents:       t.NotifyEvents,
+			NotifyChannels:     t.NotifyChannels,
+			NotifyMinGenerated: t.NotifyMinGenerated,
+			RunOnlyIfIdle:      t.RunOnlyIfIdle,
+			Timezone:           t.Timezone,
+			JitterSeconds:      t.JitterSeconds,
+			BlackoutWindows:    t.BlackoutWindows,
+			MisfirePolicy:      t.MisfirePolicy,
+			MisfireLimit:       t.MisfireLimit,
+			PostActions:        t.PostActions,
+			Hooks:              t.Hooks,
 		}
 	}
 
@@ -331,6 +454,17 @@ func (s *TaskService) GetAllTasks(req *taskRequest.TaskAllReq) ([]taskResponse.T
 			DownloadSubtitle:   t.DownloadSubtitle,
 			MetadataExtensions: t.MetadataExtensions,
 			SubtitleExtensions: t.SubtitleExtensions,
+			NotifyEvents:       t.NotifyEvents,
+			NotifyChannels:     t.NotifyChannels,
+			NotifyMinGenerated: t.NotifyMinGenerated,
+			RunOnlyIfIdle:      t.RunOnlyIfIdle,
+			Timezone:           t.Timezone,
+			JitterSeconds:      t.JitterSeconds,
+			BlackoutWindows:    t.BlackoutWindows,
+			MisfirePolicy:      t.MisfirePolicy,
+			MisfireLimit:       t.MisfireLimit,
+			PostActions:        t.PostActions,
+			Hooks:              t.Hooks,
 		}
 	}
 
@@ -419,7 +553,7 @@ func (s *TaskService) checkTaskExecutable(taskID uint) (*task.Task, error) {
 // ExecuteTask 执行任务
 func (s *TaskService) ExecuteTask(id uint, req *taskRequest.TaskExecuteReq) (*taskResponse.TaskExecuteResp, error) {
 	// 使用辅助方法检查任务是否可执行
-	task, err := s.checkTaskExecutable(id)
+	taskInfo, err := s.checkTaskExecutable(id)
 	if err != nil {
 		return nil, err
 	}
@@ -428,7 +562,7 @@ func (s *TaskService) ExecuteTask(id uint, req *taskRequest.TaskExecuteReq) (*ta
 	startTime := time.Now()
 	resp := &taskResponse.TaskExecuteResp{
 		TaskID:    id,
-		TaskName:  task.Name,
+		TaskName:  taskInfo.Name,
 		IsSync:    req.Sync,
 		Status:    "running",
 		StartTime: startTime.Format("2006-01-02 15:04:05"),
@@ -463,7 +597,7 @@ func (s *TaskService) ExecuteTask(id uint, req *taskRequest.TaskExecuteReq) (*ta
 		return resp, err
 	} else {
 		// 异步执行
-		err := s.ExecuteStrmGenerationAsync(id)
+		err := s.ExecuteStrmGenerationAsync(id, task.TriggerManual)
 		if err != nil {
 			return nil, err
 		}
@@ -473,8 +607,13 @@ func (s *TaskService) ExecuteTask(id uint, req *taskRequest.TaskExecuteReq) (*ta
 	}
 }
 
-// ExecuteStrmGeneration 执行 STRM 文件生成任务
+// ExecuteStrmGeneration 同步执行 STRM 文件生成任务
 func (s *TaskService) ExecuteStrmGeneration(taskID uint) (*taskResponse.TaskExecuteResp, error) {
+	return s.ExecuteScopedStrmGeneration(taskID, "", task.TriggerManual)
+}
+
+// ExecuteScopedStrmGeneration 执行 STRM 文件生成任务，subPath 不为空时仅扫描该子路径，trigger 为触发来源
+func (s *TaskService) ExecuteScopedStrmGeneration(taskID uint, subPath string, trigger string) (*taskResponse.TaskExecuteResp, error) {
 	// 使用辅助方法检查任务是否可执行
 	taskInfo, err := s.checkTaskExecutable(taskID)
 	if err != nil {
@@ -495,9 +634,11 @@ func (s *TaskService) ExecuteStrmGeneration(taskID uint) (*taskResponse.TaskExec
 		return nil, fmt.Errorf("更新任务运行状态失败: %w", err)
 	}
 
-	// 更新最后执行时间
-	if err := repository.Task.UpdateLastRunAt(taskID, startTime); err != nil {
-		utils.Error("更新任务最后执行时间失败", "task_id", taskID, "error", err.Error())
+	// 更新最后执行时间，局部扫描只覆盖部分路径，不计入以免掩盖错过的定时执行
+	if subPath == "" {
+		if err := repository.Task.UpdateLastRunAt(taskID, startTime); err != nil {
+			utils.Error("更新任务最后执行时间失败", "task_id", taskID, "error", err.Error())
+		}
 	}
 
 	// 准备响应对象
@@ -510,7 +651,8 @@ func (s *TaskService) ExecuteStrmGeneration(taskID uint) (*taskResponse.TaskExec
 	}
 
 	// 启动 STRM 文件生成
-	err = strmService.GenerateStrmFiles(taskID)
+	taskLogID, err := strmService.GenerateStrmFilesInScope(taskID, subPath, trigger)
+	resp.TaskLogID = taskLogID
 
 	// 更新任务运行状态
 	if updateErr := repository.Task.UpdateRunningStatus(taskID, false); updateErr != nil {
@@ -522,18 +664,25 @@ func (s *TaskService) ExecuteStrmGeneration(taskID uint) (*taskResponse.TaskExec
 	resp.EndTime = endTime.Format("2006-01-02 15:04:05")
 	resp.Duration = endTime.Sub(startTime).String()
 
-	// 获取最新的任务日志记录，填充详细执行结果
-	taskLogs, total, logErr := repository.TaskLog.GetLatestByTaskID(taskID, 1)
-	if logErr == nil && total > 0 && len(taskLogs) > 0 {
-		latestLog := taskLogs[0]
-		resp.TotalCount = latestLog.TotalFile
-		resp.SuccessCount = latestLog.GeneratedFile
-		resp.SkippedCount = latestLog.SkipFile
-		resp.MetadataCount = latestLog.MetadataCount
-		resp.SubtitleCount = latestLog.SubtitleCount
-
-		// 计算失败文件数
-		resp.FailedCount = resp.TotalCount - resp.SuccessCount - resp.SkippedCount
+	// 获取本次执行的任务日志，填充详细执行结果
+	if taskLogID != 0 {
+		if taskLog, logErr := repository.TaskLog.GetByID(taskLogID); logErr == nil && taskLog != nil {
+			resp.TotalCount = taskLog.TotalFile
+			resp.SuccessCount = taskLog.GeneratedFile
+			resp.SkippedCount = taskLog.SkipFile
+			resp.MetadataCount = taskLog.MetadataCount
+			resp.SubtitleCount = taskLog.SubtitleCount
+
+			// 计算失败文件数
+			resp.FailedCount = resp.TotalCount - resp.SuccessCount - resp.SkippedCount
+
+			// 执行任务的后续动作，本次已执行刷新媒体库的动作时不再默认刷新全部媒体库
+			ran := runPostActions(taskInfo, taskLog)
+			if !ran[task.ActionTypeEmbyRefresh] {
+				changed := taskLog.GeneratedFile > 0 || taskLog.MetadataDownloaded > 0 || taskLog.SubtitleDownloaded > 0
+				strmService.refreshEmbyAfterRun(taskInfo, taskLog.Status, changed)
+			}
+		}
 	}
 
 	if err != nil {
@@ -545,17 +694,109 @@ func (s *TaskService) ExecuteStrmGeneration(taskID uint) (*taskResponse.TaskExec
 	return resp, nil
 }
 
-// ExecuteStrmGenerationAsync 异步执行 STRM 文件生成任务
-func (s *TaskService) ExecuteStrmGenerationAsync(taskID uint) error {
+// ExecuteStrmGenerationAsync 将 STRM 文件生成任务加入执行队列，trigger 为触发来源，决定在队列中的优先级
+func (s *TaskService) ExecuteStrmGenerationAsync(taskID uint, trigger string) error {
 	// 验证任务是否可执行
 	taskInfo, err := s.checkTaskExecutable(taskID)
 	if err != nil {
 		return err
 	}
 
-	utils.Info("准备异步执行任务", "task_id", taskID, "name", taskInfo.Name)
+	utils.Info("准备异步执行任务", "task_id", taskID, "name", taskInfo.Name, "trigger", trigger)
 
 	// 将任务添加到队列
-	GetTaskQueue().AddTask(taskID)
+	GetTaskQueue().AddTask(taskID, trigger)
 	return nil
 }
+
+// CancelTask 取消任务：排队中的执行从队列移除，正在执行的任务在处理完当前目录或文件后停止
+func (s *TaskService) CancelTask(id uint) (string, error) {
+	taskInfo, err := repository.Task.GetByID(id)
+	if err != nil {
+		return "", err
+	}
+	if taskInfo == nil {
+		return "", errors.New("任务不存在")
+	}
+
+	removed := GetTaskQueue().RemoveTaskFromQueue(id)
+	cancelled := GetStrmGeneratorService().CancelRun(id)
+
+	switch {
+	case cancelled:
+		utils.Info("已请求取消任务", "task_id", id, "name", taskInfo.Name)
+		return "已请求取消任务，将在处理完当前文件后停止", nil
+	case removed:
+		utils.Info("已从执行队列移除任务", "task_id", id, "name", taskInfo.Name)
+		return "任务已从执行队列中移除", nil
+	default:
+		return "", errors.New("任务未在执行或排队")
+	}
+}
+
+// RescanPath 对指定路径进行局部重新扫描，未指定任务时自动匹配源路径包含该路径的任务，trigger 为触发来源
+func (s *TaskService) RescanPath(req *taskRequest.TaskRescanReq, trigger string) (*taskResponse.TaskRescanResp, error) {
+	var candidates []*task.Task
+	if req.TaskID != 0 {
+		taskInfo, err := repository.Task.GetByID(req.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		if taskInfo == nil {
+			return nil, errors.New("任务不存在")
+		}
+		candidates = append(candidates, taskInfo)
+	} else {
+		enabled := true
+		tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{Enabled: &enabled})
+		if err != nil {
+			return nil, err
+		}
+		for i := range tasks {
+			if isPathWithin(normalizeSourcePath(req.Path), normalizeSourcePath(tasks[i].SourcePath)) {
+				candidates = append(candidates, &tasks[i])
+			}
+		}
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("没有已启用任务的源路径包含 '%s'", req.Path)
+		}
+	}
+
+	resp := &taskResponse.TaskRescanResp{Items: make([]taskResponse.TaskRescanItem, 0, len(candidates))}
+	for _, taskInfo := range candidates {
+		item := taskResponse.TaskRescanItem{
+			TaskID:   taskInfo.ID,
+			TaskName: taskInfo.Name,
+			Status:   "skipped",
+		}
+
+		scopePath, err := resolveScopePath(taskInfo, req.Path)
+		if err != nil {
+			item.Message = err.Error()
+			resp.Items = append(resp.Items, item)
+			continue
+		}
+		item.SubPath = scopePath
+
+		// 任务正在运行时同样加入队列，待本次执行结束后再扫描
+		if !taskInfo.Enabled {
+			item.Message = "任务已禁用，无法执行"
+			resp.Items = append(resp.Items, item)
+			continue
+		}
+
+		GetTaskQueue().AddScopedTask(taskInfo.ID, scopePath, trigger)
+		item.Status = "queued"
+		item.Message = "局部扫描已提交执行"
+		resp.Items = append(resp.Items, item)
+
+		utils.Info("局部扫描已加入队列", "task_id", taskInfo.ID, "name", taskInfo.Name, "path", scopePath)
+	}
+
+	// 指定任务时无法执行则直接返回错误
+	if req.TaskID != 0 && resp.Items[0].Status != "queued" {
+		return nil, errors.New(resp.Items[0].Message)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/notification"
+	"github.com/MccRay-s/alist2strm/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// telegramAPIBase Telegram Bot API 地址
+	telegramAPIBase = "https://api.telegram.org"
+	// telegramPollTimeout 长轮询等待时间（秒）
+	telegramPollTimeout = 30
+	// telegramRetryDelay 轮询失败后的重试间隔
+	telegramRetryDelay = 5 * time.Second
+)
+
+// telegramBotConfig Telegram 机器人配置，复用 Telegram 通知渠道的 botToken
+type telegramBotConfig struct {
+	token          string
+	allowedChatIDs map[int64]bool
+}
+
+// telegramBotConfigFrom 从通知设置中解析机器人配置，未开启机器人模式时返回 nil
+// 未配置 allowedChatIds 时仅允许通知使用的 chatId
+func telegramBotConfigFrom(settings *notification.Settings) (*telegramBotConfig, error) {
+	if settings == nil {
+		return nil, nil
+	}
+	channelConfig, exists := settings.Channels[string(notification.ChannelTypeTelegram)]
+	if !exists || channelConfig.Config["botEnabled"] != "true" {
+		return nil, nil
+	}
+
+	token := channelConfig.Config["botToken"]
+	if token == "" {
+		return nil, errors.New("启用 Telegram 机器人需要配置 botToken")
+	}
+
+	allowed := channelConfig.Config["allowedChatIds"]
+	if strings.TrimSpace(allowed) == "" {
+		allowed = channelConfig.Config["chatId"]
+	}
+	chatIDs := make(map[int64]bool)
+	for _, item := range strings.Split(allowed, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		chatID, err := strconv.ParseInt(item, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Telegram 机器人允许的会话 ID 无效: %s", item)
+		}
+		chatIDs[chatID] = true
+	}
+	if len(chatIDs) == 0 {
+		return nil, errors.New("启用 Telegram 机器人需要配置 allowedChatIds 或 chatId")
+	}
+
+	return &telegramBotConfig{token: token, allowedChatIDs: chatIDs}, nil
+}
+
+// equal 判断两份机器人配置是否相同
+func (c *telegramBotConfig) equal(other *telegramBotConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	if c.token != other.token || len(c.allowedChatIDs) != len(other.allowedChatIDs) {
+		return false
+	}
+	for chatID := range c.allowedChatIDs {
+		if !other.allowedChatIDs[chatID] {
+			return false
+		}
+	}
+	return true
+}
+
+// TelegramBotService 以长轮询方式接收 Telegram 消息，支持在手机上查看和控制任务
+type TelegramBotService struct {
+	logger *zap.Logger
+	client *http.Client
+	mu     sync.Mutex
+	config *telegramBotConfig
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var (
+	telegramBotInstance *TelegramBotService
+	telegramBotOnce     sync.Once
+)
+
+// GetTelegramBotService 获取 Telegram 机器人服务实例
+func GetTelegramBotService() *TelegramBotService {
+	telegramBotOnce.Do(func() {
+		telegramBotInstance = &TelegramBotService{
+			logger: utils.InfoLogger.Desugar(),
+			client: &http.Client{Timeout: (telegramPollTimeout + 10) * time.Second},
+		}
+	})
+	return telegramBotInstance
+}
+
+// Apply 根据通知设置启动、重启或停止机器人
+func (b *TelegramBotService) Apply(settings *notification.Settings) {
+	config, err := telegramBotConfigFrom(settings)
+	if err != nil {
+		b.logger.Warn("Telegram 机器人配置无效，机器人已停止", zap.Error(err))
+		config = nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if config.equal(b.config) {
+		return
+	}
+
+	b.stopLocked()
+	b.config = config
+	if config == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	go b.poll(ctx, config, b.done)
+	b.logger.Info("Telegram 机器人已启动", zap.Int("允许的会话数", len(config.allowedChatIDs)))
+}
+
+// Stop 停止机器人
+func (b *TelegramBotService) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopLocked()
+	b.config = nil
+}
+
+// stopLocked 停止轮询协程并等待其退出，调用方需持有锁
+func (b *TelegramBotService) stopLocked() {
+	if b.cancel == nil {
+		return
+	}
+	b.cancel()
+	<-b.done
+	b.cancel = nil
+	b.done = nil
+	b.logger.Info("Telegram 机器人已停止")
+}
+
+// poll 长轮询获取消息并逐条处理
+func (b *TelegramBotService) poll(ctx context.Context, config *telegramBotConfig, done chan struct{}) {
+	defer close(done)
+
+	if err := b.call(ctx, config.token, "setMyCommands", map[string]interface{}{"commands": telegramBotCommands}, nil); err != nil {
+		b.logger.Warn("设置 Telegram 机器人命令菜单失败", zap.Error(err))
+	}
+
+	offset := 0
+	for {
+		var updates []tgUpdate
+		err := b.call(ctx, config.token, "getUpdates", map[string]interface{}{
+			"offset":          offset,
+			"timeout":         telegramPollTimeout,
+			"allowed_updates": []string{"message", "callback_query"},
+		}, &updates)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			b.logger.Warn("获取 Telegram 消息失败，稍后重试", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(telegramRetryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			b.handleUpdate(ctx, config, update)
+		}
+	}
+}
+
+// handleUpdate 处理单条消息或按钮回调，仅响应允许的会话
+func (b *TelegramBotService) handleUpdate(ctx context.Context, config *telegramBotConfig, update tgUpdate) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("处理 Telegram 消息时发生异常", zap.Any("panic", r))
+		}
+	}()
+
+	var chatID int64
+	switch {
+	case update.Message != nil:
+		chatID = update.Message.Chat.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+		chatID = update.CallbackQuery.Message.Chat.ID
+	default:
+		return
+	}
+
+	if !config.allowedChatIDs[chatID] {
+		b.logger.Warn("拒绝未授权的 Telegram 会话", zap.Int64("chatId", chatID))
+		if update.CallbackQuery != nil {
+			b.answerCallback(ctx, config, update.CallbackQuery.ID, "未授权")
+			return
+		}
+		b.send(ctx, config, chatID, 0, &tgReply{Text: fmt.Sprintf("⛔ 当前会话未授权（chat id: <code>%d</code>）", chatID)})
+		return
+	}
+
+	if update.CallbackQuery != nil {
+		query := update.CallbackQuery
+		b.answerCallback(ctx, config, query.ID, "")
+		reply, edit := b.handleCallback(query.Data)
+		messageID := 0
+		if edit {
+			messageID = query.Message.MessageID
+		}
+		b.send(ctx, config, chatID, messageID, reply)
+		return
+	}
+
+	text := strings.TrimSpace(update.Message.Text)
+	if !strings.HasPrefix(text, "/") {
+		return
+	}
+	fields := strings.Fields(text)
+	// 群组中的命令可能带有 @机器人用户名 后缀
+	command := strings.ToLower(strings.SplitN(fields[0], "@", 2)[0])
+	args := strings.TrimSpace(strings.TrimPrefix(text, fields[0]))
+
+	b.logger.Info("收到 Telegram 机器人命令", zap.Int64("chatId", chatID), zap.String("command", command))
+	b.send(ctx, config, chatID, 0, b.handleCommand(command, args))
+}
+
+// send 发送回复，messageID 大于 0 时编辑原消息
+func (b *TelegramBotService) send(ctx context.Context, config *telegramBotConfig, chatID int64, messageID int, reply *tgReply) {
+	if reply == nil {
+		return
+	}
+	payload := map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     reply.Text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}
+	if len(reply.Keyboard) > 0 {
+		payload["reply_markup"] = map[string]interface{}{"inline_keyboard": reply.Keyboard}
+	}
+
+	method := "sendMessage"
+	if messageID > 0 {
+		method = "editMessageText"
+		payload["message_id"] = messageID
+	}
+	if err := b.call(ctx, config.token, method, payload, nil); err != nil {
+		// 内容未变化时编辑消息会返回错误，无需处理
+		if !strings.Contains(err.Error(), "message is not modified") {
+			b.logger.Warn("发送 Telegram 机器人回复失败", zap.String("method", method), zap.Error(err))
+		}
+	}
+}
+
+// answerCallback 应答按钮回调，消除客户端的加载状态
+func (b *TelegramBotService) answerCallback(ctx context.Context, config *telegramBotConfig, callbackID, text string) {
+	payload := map[string]interface{}{"callback_query_id": callbackID}
+	if text != "" {
+		payload["text"] = text
+	}
+	if err := b.call(ctx, config.token, "answerCallbackQuery", payload, nil); err != nil {
+		b.logger.Debug("应答 Telegram 回调失败", zap.Error(err))
+	}
+}
+
+// call 调用 Telegram Bot API，result 不为 nil 时解析返回结果
+func (b *TelegramBotService) call(ctx context.Context, token, method string, payload interface{}, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("JSON编码失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/bot%s/%s", telegramAPIBase, token, method), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	var apiResp struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return fmt.Errorf("解析 Telegram 响应失败 (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if !apiResp.OK {
+		return fmt.Errorf("telegram API错误 (HTTP %d): %s", resp.StatusCode, apiResp.Description)
+	}
+	if result != nil {
+		return json.Unmarshal(apiResp.Result, result)
+	}
+	return nil
+}
+
+// tgUpdate Telegram 更新
+type tgUpdate struct {
+	UpdateID      int              `json:"update_id"`
+	Message       *tgMessage       `json:"message"`
+	CallbackQuery *tgCallbackQuery `json:"callback_query"`
+}
+
+// tgMessage Telegram 消息
+type tgMessage struct {
+	MessageID int    `json:"message_id"`
+	Text      string `json:"text"`
+	Chat      struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+}
+
+// tgCallbackQuery Telegram 内联键盘按钮回调
+type tgCallbackQuery struct {
+	ID      string     `json:"id"`
+	Data    string     `json:"data"`
+	Message *tgMessage `json:"message"`
+}
+
+// tgButton 内联键盘按钮
+type tgButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// tgReply 机器人回复内容（HTML 格式）
+type tgReply struct {
+	Text     string
+	Keyboard [][]tgButton
+}
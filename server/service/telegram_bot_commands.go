@@ -0,0 +1,391 @@
+package service
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/model/task"
+	taskRequest "github.com/MccRay-s/alist2strm/model/task/request"
+	"github.com/MccRay-s/alist2strm/model/tasklog"
+	"github.com/MccRay-s/alist2strm/repository"
+)
+
+// maxBotTaskButtons 任务列表中最多显示的任务按钮数（Telegram 内联键盘最多 100 个按钮）
+const maxBotTaskButtons = 50
+
+// telegramBotCommands 机器人命令菜单
+var telegramBotCommands = []map[string]string{
+	{"command": "tasks", "description": "任务列表"},
+	{"command": "status", "description": "运行状态与处理进度"},
+	{"command": "run", "description": "执行任务：/run 任务ID或名称"},
+	{"command": "cancel", "description": "取消任务：/cancel 任务ID或名称"},
+	{"command": "log", "description": "最近一次执行日志：/log 任务ID或名称"},
+	{"command": "rescan", "description": "局部扫描路径：/rescan 源路径"},
+	{"command": "emby", "description": "刷新 Emby 媒体库"},
+	{"command": "help", "description": "帮助"},
+}
+
+// handleCommand 处理文本命令
+func (b *TelegramBotService) handleCommand(command, args string) *tgReply {
+	switch command {
+	case "/start", "/help":
+		return botHelpReply()
+	case "/tasks":
+		return botTaskListReply()
+	case "/status":
+		return botStatusReply()
+	case "/run":
+		return botTaskActionReply("run", args, botRunTask)
+	case "/cancel":
+		return botTaskActionReply("cancel", args, botCancelTask)
+	case "/log":
+		return botTaskActionReply("log", args, botLastLogReply)
+	case "/rescan":
+		return botRescanReply(args)
+	case "/emby":
+		return botEmbyRefreshReply()
+	default:
+		return &tgReply{Text: "未知命令，发送 /help 查看可用命令"}
+	}
+}
+
+// handleCallback 处理内联键盘按钮回调，返回回复内容以及是否编辑原消息
+func (b *TelegramBotService) handleCallback(data string) (*tgReply, bool) {
+	action, idStr, _ := strings.Cut(data, ":")
+	switch action {
+	case "tasks":
+		return botTaskListReply(), true
+	case "status":
+		return botStatusReply(), true
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		return &tgReply{Text: "无效的操作"}, false
+	}
+	taskInfo, err := repository.Task.GetByID(uint(id))
+	if err != nil || taskInfo == nil {
+		return &tgReply{Text: "任务不存在"}, false
+	}
+
+	switch action {
+	case "task":
+		return botTaskDetailReply(taskInfo), true
+	case "run":
+		return botRunTask(taskInfo), false
+	case "cancel":
+		return botCancelTask(taskInfo), false
+	case "log":
+		return botLastLogReply(taskInfo), false
+	default:
+		return &tgReply{Text: "无效的操作"}, false
+	}
+}
+
+// botHelpReply 帮助信息
+func botHelpReply() *tgReply {
+	var sb strings.Builder
+	sb.WriteString("🤖 <b>alist2strm 机器人</b>\n\n")
+	for _, command := range telegramBotCommands {
+		sb.WriteString(fmt.Sprintf("/%s - %s\n", command["command"], html.EscapeString(command["description"])))
+	}
+	return &tgReply{
+		Text:     sb.String(),
+		Keyboard: [][]tgButton{{{Text: "📋 任务列表", CallbackData: "tasks"}, {Text: "⏳ 运行状态", CallbackData: "status"}}},
+	}
+}
+
+// botTaskListReply 任务列表，点击任务查看详情
+func botTaskListReply() *tgReply {
+	tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{})
+	if err != nil {
+		return &tgReply{Text: "获取任务列表失败: " + html.EscapeString(err.Error())}
+	}
+	if len(tasks) == 0 {
+		return &tgReply{Text: "暂无任务"}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📋 <b>任务列表</b>（共 %d 个）\n\n", len(tasks)))
+	var keyboard [][]tgButton
+	var row []tgButton
+	for i, t := range tasks {
+		sb.WriteString(fmt.Sprintf("%s <b>%s</b> #%d\n", botTaskIcon(&t), html.EscapeString(t.Name), t.ID))
+		if i >= maxBotTaskButtons {
+			continue
+		}
+		row = append(row, tgButton{Text: t.Name, CallbackData: fmt.Sprintf("task:%d", t.ID)})
+		if len(row) == 2 {
+			keyboard = append(keyboard, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		keyboard = append(keyboard, row)
+	}
+	sb.WriteString("\n🟢 已启用 ⚪ 已禁用 ⏳ 运行中 🕒 排队中")
+	return &tgReply{Text: sb.String(), Keyboard: keyboard}
+}
+
+// botTaskDetailReply 任务详情及操作按钮
+func botTaskDetailReply(t *task.Task) *tgReply {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s <b>%s</b> #%d\n\n", botTaskIcon(t), html.EscapeString(t.Name), t.ID))
+	sb.WriteString(fmt.Sprintf("源路径：<code>%s</code>\n", html.EscapeString(t.SourcePath)))
+	sb.WriteString(fmt.Sprintf("目标路径：<code>%s</code>\n", html.EscapeString(t.TargetPath)))
+	if t.Cron != "" {
+		sb.WriteString(fmt.Sprintf("定时：<code>%s</code>\n", html.EscapeString(t.Cron)))
+	}
+	if t.LastRunAt != nil {
+		sb.WriteString(fmt.Sprintf("上次执行：%s\n", t.LastRunAt.Format("2006-01-02 15:04:05")))
+	}
+	if progress, ok := GetStrmGeneratorService().GetProgress(t.ID); ok {
+		sb.WriteString("\n" + botProgressText(progress))
+	}
+
+	return &tgReply{
+		Text: sb.String(),
+		Keyboard: [][]tgButton{
+			{{Text: "▶️ 执行", CallbackData: fmt.Sprintf("run:%d", t.ID)}, {Text: "⏹ 取消", CallbackData: fmt.Sprintf("cancel:%d", t.ID)}},
+			{{Text: "📄 最近日志", CallbackData: fmt.Sprintf("log:%d", t.ID)}, {Text: "⬅️ 返回", CallbackData: "tasks"}},
+		},
+	}
+}
+
+// botStatusReply 运行状态：正在执行的任务进度以及排队情况
+func botStatusReply() *tgReply {
+	var sb strings.Builder
+	sb.WriteString("⏳ <b>运行状态</b>\n\n")
+	keyboard := [][]tgButton{{{Text: "🔄 刷新", CallbackData: "status"}}}
+
+	progress, running := GetStrmGeneratorService().GetProgress(0)
+	if running {
+		taskName := digestTaskName(progress.TaskID)
+		sb.WriteString(fmt.Sprintf("<b>%s</b> #%d\n", html.EscapeString(taskName), progress.TaskID))
+		sb.WriteString(botProgressText(progress))
+		keyboard[0] = append(keyboard[0], tgButton{Text: "⏹ 取消", CallbackData: fmt.Sprintf("cancel:%d", progress.TaskID)})
+	} else {
+		sb.WriteString("当前没有正在执行的任务\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("\n排队中：%d 个", GetTaskQueue().GetQueueLength()))
+	sb.WriteString(fmt.Sprintf("\n更新时间：%s", time.Now().Format("15:04:05")))
+	return &tgReply{Text: sb.String(), Keyboard: keyboard}
+}
+
+// botProgressText 格式化处理进度
+func botProgressText(progress *TaskProgress) string {
+	stage := "扫描目录"
+	switch {
+	case progress.Cancelling:
+		stage = "正在取消"
+	case progress.ScanFinished && progress.StrmProcessingDone && progress.DownloadProcessingDone:
+		stage = "收尾中"
+	case progress.ScanFinished && progress.StrmProcessingDone:
+		stage = "下载元数据/字幕"
+	case progress.ScanFinished:
+		stage = "生成 STRM"
+	}
+
+	var sb strings.Builder
+	if progress.ScopePath != "" {
+		sb.WriteString(fmt.Sprintf("局部扫描：<code>%s</code>\n", html.EscapeString(progress.ScopePath)))
+	}
+	sb.WriteString(fmt.Sprintf("阶段：%s\n", stage))
+	sb.WriteString(fmt.Sprintf("已运行：%s\n", time.Since(progress.StartTime).Round(time.Second)))
+	sb.WriteString(fmt.Sprintf("已扫描：%d，已生成：%d，已跳过：%d\n", progress.TotalFiles, progress.GeneratedFile, progress.SkipFile))
+	sb.WriteString(fmt.Sprintf("元数据：%d，字幕：%d，失败：%d\n", progress.MetadataDownloaded, progress.SubtitleDownloaded, progress.FailedCount))
+	return sb.String()
+}
+
+// botTaskActionReply 按参数查找任务并执行操作，未提供参数时列出任务供选择
+func botTaskActionReply(action, args string, handler func(*task.Task) *tgReply) *tgReply {
+	if args == "" {
+		tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{})
+		if err != nil {
+			return &tgReply{Text: "获取任务列表失败: " + html.EscapeString(err.Error())}
+		}
+		if len(tasks) == 0 {
+			return &tgReply{Text: "暂无任务"}
+		}
+		var keyboard [][]tgButton
+		for i, t := range tasks {
+			if i >= maxBotTaskButtons {
+				break
+			}
+			keyboard = append(keyboard, []tgButton{{Text: botTaskIcon(&t) + " " + t.Name, CallbackData: fmt.Sprintf("%s:%d", action, t.ID)}})
+		}
+		return &tgReply{Text: "请选择任务：", Keyboard: keyboard}
+	}
+
+	taskInfo, err := botFindTask(args)
+	if err != nil {
+		return &tgReply{Text: html.EscapeString(err.Error())}
+	}
+	return handler(taskInfo)
+}
+
+// botFindTask 按任务 ID 或名称查找任务，名称支持唯一的模糊匹配
+func botFindTask(query string) (*task.Task, error) {
+	if id, err := strconv.ParseUint(query, 10, 64); err == nil {
+		taskInfo, err := repository.Task.GetByID(uint(id))
+		if err != nil {
+			return nil, err
+		}
+		if taskInfo != nil {
+			return taskInfo, nil
+		}
+	}
+
+	tasks, err := repository.Task.ListAll(&taskRequest.TaskAllReq{Name: query})
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if strings.EqualFold(tasks[i].Name, query) {
+			return &tasks[i], nil
+		}
+	}
+	switch len(tasks) {
+	case 0:
+		return nil, fmt.Errorf("未找到任务: %s", query)
+	case 1:
+		return &tasks[0], nil
+	default:
+		return nil, fmt.Errorf("匹配到 %d 个任务，请使用任务 ID", len(tasks))
+	}
+}
+
+// botRunTask 将任务加入执行队列
+func botRunTask(t *task.Task) *tgReply {
+	if err := Task.ExecuteStrmGenerationAsync(t.ID, task.TriggerManual); err != nil {
+		return &tgReply{Text: fmt.Sprintf("❌ 无法执行 <b>%s</b>：%s", html.EscapeString(t.Name), html.EscapeString(err.Error()))}
+	}
+	return &tgReply{
+		Text:     fmt.Sprintf("▶️ <b>%s</b> 已提交执行", html.EscapeString(t.Name)),
+		Keyboard: [][]tgButton{{{Text: "⏳ 查看进度", CallbackData: "status"}}},
+	}
+}
+
+// botCancelTask 取消正在执行或排队中的任务
+func botCancelTask(t *task.Task) *tgReply {
+	message, err := Task.CancelTask(t.ID)
+	if err != nil {
+		return &tgReply{Text: fmt.Sprintf("❌ 无法取消 <b>%s</b>：%s", html.EscapeString(t.Name), html.EscapeString(err.Error()))}
+	}
+	return &tgReply{Text: fmt.Sprintf("⏹ <b>%s</b>：%s", html.EscapeString(t.Name), html.EscapeString(message))}
+}
+
+// botLastLogReply 任务最近一次执行日志
+func botLastLogReply(t *task.Task) *tgReply {
+	logs, _, err := repository.TaskLog.GetLatestByTaskID(t.ID, 1)
+	if err != nil {
+		return &tgReply{Text: "获取任务日志失败: " + html.EscapeString(err.Error())}
+	}
+	if len(logs) == 0 {
+		return &tgReply{Text: fmt.Sprintf("<b>%s</b> 暂无执行记录", html.EscapeString(t.Name))}
+	}
+
+	log := logs[0]
+	statusText := map[string]string{
+		tasklog.TaskLogStatusRunning:   "⏳ 运行中",
+		tasklog.TaskLogStatusCompleted: "✅ 成功",
+		tasklog.TaskLogStatusFailed:    "❌ 失败",
+		tasklog.TaskLogStatusCancelled: "⏹ 已取消",
+	}[log.Status]
+	if statusText == "" {
+		statusText = log.Status
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📄 <b>%s</b> 最近一次执行 #%d\n\n", html.EscapeString(t.Name), log.ID))
+	sb.WriteString(fmt.Sprintf("状态：%s\n", statusText))
+	if triggerText := botTriggerText(log.Trigger); triggerText != "" {
+		sb.WriteString(fmt.Sprintf("触发：%s\n", triggerText))
+	}
+	sb.WriteString(fmt.Sprintf("开始：%s\n", log.StartTime.Format("2006-01-02 15:04:05")))
+	if log.EndTime != nil {
+		sb.WriteString(fmt.Sprintf("结束：%s（耗时 %d 秒）\n", log.EndTime.Format("2006-01-02 15:04:05"), log.Duration))
+	}
+	if log.ScopePath != "" {
+		sb.WriteString(fmt.Sprintf("局部扫描：<code>%s</code>\n", html.EscapeString(log.ScopePath)))
+	}
+	sb.WriteString(fmt.Sprintf("文件：总计 %d，生成 %d，跳过 %d，失败 %d\n", log.TotalFile, log.GeneratedFile, log.SkipFile, log.FailedCount))
+	sb.WriteString(fmt.Sprintf("元数据：%d，字幕：%d\n", log.MetadataDownloaded, log.SubtitleDownloaded))
+	if log.Message != "" {
+		sb.WriteString(fmt.Sprintf("信息：%s\n", html.EscapeString(log.Message)))
+	}
+	return &tgReply{
+		Text:     sb.String(),
+		Keyboard: [][]tgButton{{{Text: "▶️ 再次执行", CallbackData: fmt.Sprintf("run:%d", t.ID)}}},
+	}
+}
+
+// botRescanReply 对指定路径进行局部扫描
+func botRescanReply(path string) *tgReply {
+	if path == "" {
+		return &tgReply{Text: "用法：/rescan 源路径，例如 <code>/rescan /电视剧/某剧</code>"}
+	}
+
+	resp, err := Task.RescanPath(&taskRequest.TaskRescanReq{Path: path}, task.TriggerManual)
+	if err != nil {
+		return &tgReply{Text: "❌ 局部扫描失败：" + html.EscapeString(err.Error())}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔍 局部扫描 <code>%s</code>\n\n", html.EscapeString(path)))
+	for _, item := range resp.Items {
+		icon := "⏭"
+		if item.Status == "queued" {
+			icon = "✅"
+		}
+		sb.WriteString(fmt.Sprintf("%s <b>%s</b>：%s\n", icon, html.EscapeString(item.TaskName), html.EscapeString(item.Message)))
+	}
+	return &tgReply{Text: sb.String(), Keyboard: [][]tgButton{{{Text: "⏳ 查看进度", CallbackData: "status"}}}}
+}
+
+// botEmbyRefreshReply 刷新 Emby 媒体库
+func botEmbyRefreshReply() *tgReply {
+	if !Emby.IsConfigured() {
+		return &tgReply{Text: "未配置 Emby"}
+	}
+	if err := Emby.RefreshAllLibraries(); err != nil {
+		return &tgReply{Text: "❌ 刷新 Emby 媒体库失败：" + html.EscapeString(err.Error())}
+	}
+	return &tgReply{Text: "✅ 已请求刷新 Emby 媒体库"}
+}
+
+// botTriggerText 触发来源的显示名称
+func botTriggerText(trigger string) string {
+	switch trigger {
+	case task.TriggerCron:
+		return "定时调度"
+	case task.TriggerManual:
+		return "手动执行"
+	case task.TriggerWebhook:
+		return "文件变更通知"
+	case task.TriggerAPI:
+		return "接口调用"
+	case task.TriggerCatchUp:
+		return "补偿执行"
+	default:
+		return trigger
+	}
+}
+
+// botTaskIcon 任务状态图标
+func botTaskIcon(t *task.Task) string {
+	switch {
+	case t.Running:
+		return "⏳"
+	case GetTaskQueue().IsTaskInQueue(t.ID):
+		return "🕒"
+	case t.Enabled:
+		return "🟢"
+	default:
+		return "⚪"
+	}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MccRay-s/alist2strm/config"
+	"github.com/MccRay-s/alist2strm/model/task"
+	"github.com/MccRay-s/alist2strm/model/trash"
+	trashRequest "github.com/MccRay-s/alist2strm/model/trash/request"
+	trashResponse "github.com/MccRay-s/alist2strm/model/trash/response"
+	"github.com/MccRay-s/alist2strm/repository"
+	"github.com/MccRay-s/alist2strm/utils"
+)
+
+const (
+	// trashPurgeInterval 回收站过期清理间隔
+	trashPurgeInterval = time.Hour
+	// trashPurgeBatchSize 每批清理的条目数
+	trashPurgeBatchSize = 200
+)
+
+type TrashService struct {
+	purgeOnce sync.Once
+}
+
+// 包级别的全局实例
+var Trash = &TrashService{}
+
+// getTrashConfig 获取回收站配置
+func (s *TrashService) getTrashConfig() config.TrashConfig {
+	cfg := config.TrashConfig{BaseDir: "../data/trash", RetentionDays: 30}
+	if config.GlobalConfig != nil {
+		if config.GlobalConfig.Trash.BaseDir != "" {
+			cfg.BaseDir = config.GlobalConfig.Trash.BaseDir
+		}
+		if config.GlobalConfig.Trash.RetentionDays > 0 {
+			cfg.RetentionDays = config.GlobalConfig.Trash.RetentionDays
+		}
+	}
+	return cfg
+}
+
+// MoveToTrash 将目标文件移入回收站并记录原始路径
+func (s *TrashService) MoveToTrash(taskInfo *task.Task, sourcePath, filePath string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	cfg := s.getTrashConfig()
+
+	// 保留文件相对于任务目标目录的层级，便于在回收站中辨认
+	relativePath, err := filepath.Rel(taskInfo.TargetPath, filePath)
+	if err != nil || strings.HasPrefix(relativePath, "..") {
+		relativePath = strings.TrimLeft(filepath.ToSlash(filepath.Clean(filePath)), "/")
+		relativePath = strings.ReplaceAll(relativePath, ":", "")
+	}
+
+	batchDir := time.Now().Format("20060102-150405.000")
+	trashPath := s.uniquePath(filepath.Join(cfg.BaseDir, fmt.Sprintf("%d", taskInfo.ID), batchDir, relativePath))
+
+	if err := os.MkdirAll(filepath.Dir(trashPath), 0755); err != nil {
+		return fmt.Errorf("创建回收站目录失败: %w", err)
+	}
+	if err := moveFile(filePath, trashPath); err != nil {
+		return fmt.Errorf("移动文件到回收站失败: %w", err)
+	}
+
+	item := &trash.TrashItem{
+		TaskID:       taskInfo.ID,
+		SourcePath:   sourcePath,
+		OriginalPath: filePath,
+		TrashPath:    trashPath,
+		FileName:     filepath.Base(filePath),
+		FileSize:     info.Size(),
+		Status:       trash.TrashStatusTrashed,
+		ExpireAt:     time.Now().AddDate(0, 0, cfg.RetentionDays),
+	}
+	if err := repository.Trash.Create(item); err != nil {
+		// 记录失败时将文件移回原处，避免文件下落不明
+		if restoreErr := moveFile(trashPath, filePath); restoreErr != nil {
+			utils.Error("回收站记录失败且文件无法移回", "file", filePath, "trash_path", trashPath, "error", restoreErr.Error())
+		}
+		return fmt.Errorf("保存回收站记录失败: %w", err)
+	}
+
+	utils.Info("文件已移入回收站", "file", filePath, "trash_path", trashPath, "task_id", taskInfo.ID)
+	return nil
+}
+
+// GetTrashList 获取回收站分页列表
+func (s *TrashService) GetTrashList(req *trashRequest.TrashListReq) (*trashResponse.TrashListResp, error) {
+	items, total, err := repository.Trash.List(req)
+	if err != nil {
+		utils.Error("获取回收站列表失败", "error", err.Error())
+		return nil, fmt.Errorf("获取回收站列表失败: %w", err)
+	}
+
+	return &trashResponse.TrashListResp{
+		List:  items,
+		Total: total,
+		Page:  req.Page,
+		Size:  req.PageSize,
+	}, nil
+}
+
+// Restore 按ID或按目录恢复回收站条目
+func (s *TrashService) Restore(req *trashRequest.TrashRestoreReq) (*trashResponse.TrashRestoreResp, error) {
+	if len(req.IDs) == 0 && req.Directory == "" {
+		return nil, errors.New("请指定要恢复的条目ID或目录")
+	}
+
+	var items []*trash.TrashItem
+	if len(req.IDs) > 0 {
+		byIDs, err := repository.Trash.ListTrashedByIDs(req.IDs)
+		if err != nil {
+			return nil, fmt.Errorf("查询回收站条目失败: %w", err)
+		}
+		items = append(items, byIDs...)
+	}
+	if req.Directory != "" {
+		byDir, err := repository.Trash.ListTrashedByDirectory(filepath.Clean(req.Directory), req.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("查询回收站条目失败: %w", err)
+		}
+		items = append(items, byDir...)
+	}
+
+	resp := &trashResponse.TrashRestoreResp{Errors: []string{}}
+	seen := make(map[uint]bool)
+	for _, item := range items {
+		if seen[item.ID] {
+			continue
+		}
+		seen[item.ID] = true
+
+		if err := s.restoreItem(item); err != nil {
+			resp.Failed++
+			resp.Errors = append(resp.Errors, fmt.Sprintf("%s: %s", item.OriginalPath, err.Error()))
+			utils.Warn("恢复回收站条目失败", "id", item.ID, "original_path", item.OriginalPath, "error", err.Error())
+			continue
+		}
+		resp.Restored++
+	}
+
+	utils.Info("回收站恢复完成", "restored", resp.Restored, "failed", resp.Failed)
+	return resp, nil
+}
+
+// restoreItem 将单个条目移回原始路径
+func (s *TrashService) restoreItem(item *trash.TrashItem) error {
+	if _, err := os.Stat(item.OriginalPath); err == nil {
+		return errors.New("原始路径已存在同名文件")
+	}
+	if _, err := os.Stat(item.TrashPath); err != nil {
+		return fmt.Errorf("回收站文件不存在: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(item.OriginalPath), 0755); err != nil {
+		return fmt.Errorf("创建原始目录失败: %w", err)
+	}
+	if err := moveFile(item.TrashPath, item.OriginalPath); err != nil {
+		return err
+	}
+	s.removeEmptyParents(filepath.Dir(item.TrashPath))
+
+	return repository.Trash.MarkRestored(item.ID, time.Now())
+}
+
+// PurgeExpired 清除已过期的回收站条目，返回清除的条目数
+func (s *TrashService) PurgeExpired() (int, error) {
+	purged := 0
+	now := time.Now()
+	for {
+		items, err := repository.Trash.ListExpired(now, trashPurgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		if len(items) == 0 {
+			return purged, nil
+		}
+
+		for _, item := range items {
+			if item.Status == trash.TrashStatusTrashed {
+				if err := os.RemoveAll(item.TrashPath); err != nil {
+					utils.Error("清除回收站文件失败", "trash_path", item.TrashPath, "error", err.Error())
+					continue
+				}
+				s.removeEmptyParents(filepath.Dir(item.TrashPath))
+			}
+			if err := repository.Trash.Delete(item.ID); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(items) < trashPurgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// StartPurgeTask 启动回收站定期清理任务
+func (s *TrashService) StartPurgeTask() {
+	s.purgeOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(trashPurgeInterval)
+			defer ticker.Stop()
+
+			for {
+				if purged, err := s.PurgeExpired(); err != nil {
+					utils.Error("清理过期回收站条目失败", "error", err.Error())
+				} else if purged > 0 {
+					utils.Info("已清理过期回收站条目", "count", purged)
+				}
+				<-ticker.C
+			}
+		}()
+	})
+}
+
+// uniquePath 若路径已存在则追加序号，避免覆盖回收站中的同名文件
+func (s *TrashService) uniquePath(path string) string {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s_%d%s", base, i, ext)
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// removeEmptyParents 自下而上删除回收站中的空目录，直到回收站根目录
+func (s *TrashService) removeEmptyParents(dir string) {
+	root := filepath.Clean(s.getTrashConfig().BaseDir)
+	for dir = filepath.Clean(dir); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
+
+// moveFile 移动文件，跨设备时退化为复制后删除
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	// 跨设备时复制后删除源文件，源文件在复制函数返回时已关闭，删除不受占用影响
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// copyFile 复制文件内容和权限，失败时删除不完整的目标文件
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
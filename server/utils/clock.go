@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minutesPerDay 一天的分钟数
+const minutesPerDay = 24 * 60
+
+// ParseClock 将 HH:MM 解析为当天的分钟数，24:00 视为次日 00:00
+func ParseClock(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("时间 '%s' 应为 HH:MM", value)
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 24 {
+		return 0, fmt.Errorf("时间 '%s' 的小时无效", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("时间 '%s' 的分钟无效", value)
+	}
+	return (hour*60 + minute) % minutesPerDay, nil
+}
+
+// ClockWindow 每日重复的时段，以当天的分钟数表示，End 小于 Start 时表示跨越午夜
+type ClockWindow struct {
+	Start int
+	End   int
+}
+
+// ParseClockWindow 解析 HH:MM 格式的开始和结束时间，结束时间早于开始时间表示跨越午夜
+func ParseClockWindow(start, end string) (ClockWindow, error) {
+	startMinute, err := ParseClock(start)
+	if err != nil {
+		return ClockWindow{}, fmt.Errorf("开始时间无效: %w", err)
+	}
+	endMinute, err := ParseClock(end)
+	if err != nil {
+		return ClockWindow{}, fmt.Errorf("结束时间无效: %w", err)
+	}
+	if startMinute == endMinute {
+		return ClockWindow{}, errors.New("开始时间与结束时间不能相同")
+	}
+	return ClockWindow{Start: startMinute, End: endMinute}, nil
+}
+
+// Contains 判断时间是否处于该时段内（包含开始时间，不包含结束时间）
+func (w ClockWindow) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// EndAfter 获取 t 之后最近一次时段结束的时间
+func (w ClockWindow) EndAfter(t time.Time) time.Time {
+	end := time.Date(t.Year(), t.Month(), t.Day(), w.End/60, w.End%60, 0, 0, t.Location())
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
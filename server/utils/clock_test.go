@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "午夜", value: "00:00", want: 0},
+		{name: "单位数小时", value: "7:05", want: 7*60 + 5},
+		{name: "当天最后一分钟", value: "23:59", want: 23*60 + 59},
+		{name: "24:00 视为 00:00", value: "24:00", want: 0},
+		{name: "忽略首尾空白", value: " 08:30 ", want: 8*60 + 30},
+		{name: "24 点后的分钟", value: "24:01", wantErr: true},
+		{name: "小时超出范围", value: "25:00", wantErr: true},
+		{name: "分钟超出范围", value: "12:60", wantErr: true},
+		{name: "负数", value: "-1:00", wantErr: true},
+		{name: "缺少分钟", value: "12", wantErr: true},
+		{name: "多余的字段", value: "12:00:00", wantErr: true},
+		{name: "非数字", value: "ab:cd", wantErr: true},
+		{name: "空字符串", value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseClock(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClock(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseClock(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClockWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		want       ClockWindow
+		wantErr    bool
+	}{
+		{name: "同一天内", start: "13:00", end: "14:00", want: ClockWindow{Start: 13 * 60, End: 14 * 60}},
+		{name: "跨越午夜", start: "23:00", end: "07:00", want: ClockWindow{Start: 23 * 60, End: 7 * 60}},
+		{name: "结束于 24:00", start: "22:00", end: "24:00", want: ClockWindow{Start: 22 * 60, End: 0}},
+		{name: "开始于 24:00", start: "24:00", end: "06:00", want: ClockWindow{Start: 0, End: 6 * 60}},
+		{name: "开始与结束相同", start: "08:00", end: "08:00", wantErr: true},
+		{name: "00:00 与 24:00 相同", start: "00:00", end: "24:00", wantErr: true},
+		{name: "开始时间无效", start: "8", end: "09:00", wantErr: true},
+		{name: "结束时间无效", start: "08:00", end: "09:75", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseClockWindow(tt.start, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClockWindow(%q, %q) error = %v, wantErr %v", tt.start, tt.end, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseClockWindow(%q, %q) = %+v, want %+v", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClockWindowContainsAndEndAfter(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 10, hour, minute, 0, 0, time.UTC)
+	}
+	sameDay := ClockWindow{Start: 13 * 60, End: 14 * 60}
+	overnight := ClockWindow{Start: 23 * 60, End: 7 * 60}
+	untilMidnight := ClockWindow{Start: 22 * 60, End: 0}
+
+	tests := []struct {
+		name     string
+		window   ClockWindow
+		now      time.Time
+		contains bool
+		endAfter time.Time
+	}{
+		{name: "同一天内-开始时间", window: sameDay, now: day(13, 0), contains: true, endAfter: day(14, 0)},
+		{name: "同一天内-结束时间不包含", window: sameDay, now: day(14, 0), contains: false, endAfter: day(14, 0).AddDate(0, 0, 1)},
+		{name: "同一天内-之前", window: sameDay, now: day(12, 59), contains: false, endAfter: day(14, 0)},
+		{name: "跨越午夜-午夜前", window: overnight, now: day(23, 30), contains: true, endAfter: day(7, 0).AddDate(0, 0, 1)},
+		{name: "跨越午夜-午夜后", window: overnight, now: day(2, 0), contains: true, endAfter: day(7, 0)},
+		{name: "跨越午夜-时段外", window: overnight, now: day(12, 0), contains: false, endAfter: day(7, 0).AddDate(0, 0, 1)},
+		{name: "结束于 24:00-时段内", window: untilMidnight, now: day(23, 59), contains: true, endAfter: day(0, 0).AddDate(0, 0, 1)},
+		{name: "结束于 24:00-午夜不包含", window: untilMidnight, now: day(0, 0), contains: false, endAfter: day(0, 0).AddDate(0, 0, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Contains(tt.now); got != tt.contains {
+				t.Errorf("Contains(%s) = %v, want %v", tt.now.Format("15:04"), got, tt.contains)
+			}
+			if got := tt.window.EndAfter(tt.now); !got.Equal(tt.endAfter) {
+				t.Errorf("EndAfter(%s) = %s, want %s", tt.now.Format("15:04"), got, tt.endAfter)
+			}
+		})
+	}
+}